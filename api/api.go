@@ -0,0 +1,28 @@
+// Package api holds the request and response bodies of the REST API. It
+// depends on nothing beyond the standard library, so that the client shares
+// them with the server without pulling in the server's dependencies. The
+// packages that own each type alias it under its usual name.
+package api
+
+// Message attributes of requests and replies
+const (
+	CorrelationIDAttribute = "CorrelationId"
+	ReplyToAttribute       = "ReplyTo"
+)
+
+// ReplyQueuePrefix begins the names of the queues requesters receive replies
+// on. Replies are only sent to queues named so, so that a request cannot
+// direct a responder to write to any other queue.
+const ReplyQueuePrefix = "pubsub-reply-"
+
+// FieldError describes why a field failed validation. Field is the JSON path
+// of the field, or the name of the path parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type TagInput struct {
+	Tags map[string]string `json:"tags" binding:"required,max=50"`
+}
@@ -0,0 +1,35 @@
+package api
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Diff is a field whose actual value differs from the manifest.
+type Diff struct {
+	Field   string `json:"field"`
+	Current any    `json:"current"`
+	Desired any    `json:"desired"`
+}
+
+// Change is a step that converges one resource.
+type Change struct {
+	Action  Action `json:"action"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Diffs   []Diff `json:"diffs,omitempty"`
+	Applied bool   `json:"applied,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Plan lists the changes that converge the actual state to a manifest, in the
+// order they are applied. Orphaned lists resources a manifest applied earlier
+// that are no longer declared; pruning deletes them.
+type Plan struct {
+	Changes  []*Change `json:"changes"`
+	Orphaned []string  `json:"orphaned,omitempty"`
+	InSync   bool      `json:"inSync"`
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"pub-sub-service/cloudevents"
+)
+
+// QueueEncryption holds the server-side encryption settings of a queue: SSE-SQS
+// with SQSManagedSSEEnabled, or SSE-KMS with a KMS key. Nil fields are left
+// unchanged.
+type QueueEncryption struct {
+	KMSMasterKeyID               *string `json:"kmsMasterKeyId,omitempty"`
+	KMSDataKeyReusePeriodSeconds *int64  `json:"kmsDataKeyReusePeriodSeconds,omitempty"`
+	SQSManagedSSEEnabled         *bool   `json:"sqsManagedSseEnabled,omitempty"`
+}
+
+// RedrivePolicy moves messages to a dead-letter queue after they have been
+// received MaxReceiveCount times.
+type RedrivePolicy struct {
+	DeadLetterTargetARN string `json:"deadLetterTargetArn"`
+	MaxReceiveCount     int64  `json:"maxReceiveCount"`
+}
+
+// QueueAttributes holds the standard attributes of a queue. Nil fields are left
+// unchanged, or at their defaults when creating a queue.
+type QueueAttributes struct {
+	QueueEncryption
+	DelaySeconds                  *int64         `json:"delaySeconds,omitempty"`
+	MaximumMessageSize            *int64         `json:"maximumMessageSize,omitempty"`
+	MessageRetentionPeriod        *int64         `json:"messageRetentionPeriod,omitempty"`
+	ReceiveMessageWaitTimeSeconds *int64         `json:"receiveMessageWaitTimeSeconds,omitempty"`
+	VisibilityTimeout             *int64         `json:"visibilityTimeout,omitempty"`
+	RedrivePolicy                 *RedrivePolicy `json:"redrivePolicy,omitempty"`
+}
+
+// QueueDescription is a queue's attributes along with its read-only details.
+type QueueDescription struct {
+	QueueAttributes
+	QueueARN                              string    `json:"queueArn"`
+	ApproximateNumberOfMessages           int64     `json:"approximateNumberOfMessages"`
+	ApproximateNumberOfMessagesNotVisible int64     `json:"approximateNumberOfMessagesNotVisible"`
+	ApproximateNumberOfMessagesDelayed    int64     `json:"approximateNumberOfMessagesDelayed"`
+	CreatedAt                             time.Time `json:"createdAt"`
+	LastModifiedAt                        time.Time `json:"lastModifiedAt"`
+}
+
+// QueueMessage is a message received from a queue. Messages fanned out by
+// SNS are unwrapped from their notification envelope, and the payload is
+// decoded: text payloads are returned in Body and binary ones in Data.
+// TopicMessageID is the ID SNS gave a fanned out message when it was
+// published, which unlike MessageID is kept when SNS delivers it twice.
+type QueueMessage struct {
+	MessageID        string            `json:"messageId"`
+	ReceiptHandle    string            `json:"receiptHandle"`
+	TopicARN         string            `json:"topicArn,omitempty"`
+	TopicMessageID   string            `json:"topicMessageId,omitempty"`
+	ContentType      string            `json:"contentType,omitempty"`
+	Body             string            `json:"body,omitempty"`
+	Data             []byte            `json:"data,omitempty"`
+	Attributes       map[string]string `json:"attributes,omitempty"`
+	BinaryAttributes map[string][]byte `json:"binaryAttributes,omitempty"`
+	SentAt           time.Time         `json:"sentAt"`
+}
+
+// Payload returns the decoded payload, text or binary.
+func (m *QueueMessage) Payload() []byte {
+	if m.Data != nil {
+		return m.Data
+	}
+	return []byte(m.Body)
+}
+
+type CreateQueueInput struct {
+	QueueName  string          `json:"queueName" binding:"required,queuename"`
+	Attributes QueueAttributes `json:"attributes"`
+}
+
+// SendMessageInput carries either a text Body or a binary payload in Data,
+// base64 encoded in JSON. Compression overrides the configured payload
+// compression. DeliverAt or DelaySeconds delay the message: SQS holds it for
+// up to 15 minutes, and the scheduler holds it for longer. CorrelationID is
+// set on replies to requests, with the CorrelationId attribute of the request.
+type SendMessageInput struct {
+	Subject          string            `json:"subject" binding:"max=256"`
+	Body             string            `json:"body" binding:"required_without=Data"`
+	Data             []byte            `json:"data"`
+	ContentType      string            `json:"contentType" binding:"max=256"`
+	Attributes       map[string]string `json:"attributes" binding:"max=9"`
+	BinaryAttributes map[string][]byte `json:"binaryAttributes" binding:"max=9"`
+	Compression      string            `json:"compression" binding:"omitempty,oneof=none gzip zstd"`
+	DeliverAt        *time.Time        `json:"deliverAt,omitempty" binding:"excluded_with=DelaySeconds"`
+	DelaySeconds     int64             `json:"delaySeconds,omitempty" binding:"min=0,max=31536000"`
+	CorrelationID    string            `json:"correlationId,omitempty" binding:"max=128"`
+}
+
+// Delay returns how long to hold the message before it is sent.
+func (input SendMessageInput) Delay() time.Duration {
+	return delay(input.DeliverAt, input.DelaySeconds)
+}
+
+type ReceiveMessageInput struct {
+	VisibilityTimeout int  `json:"visibilityTimeout" binding:"min=0,max=43200"`
+	Decode            bool `json:"decode"`
+	CloudEvents       bool `json:"cloudEvents"`
+}
+
+type DeleteMessageInput struct {
+	ReceiptHandle string `json:"receiptHandle" binding:"required"`
+}
+
+type ChangeMessageVisibilityInput struct {
+	ReceiptHandle     string `json:"receiptHandle" binding:"required"`
+	VisibilityTimeout int    `json:"visibilityTimeout" binding:"min=0,max=43200"`
+}
+
+// ReceivedMessage is a received queue message, with its payload decoded to
+// JSON when requested and its schema is known.
+type ReceivedMessage struct {
+	*QueueMessage
+	Decoded json.RawMessage `json:"decoded,omitempty"`
+}
+
+// ReceivedCloudEvent is a received queue message returned as a CloudEvent,
+// with the receipt handle needed to delete it.
+type ReceivedCloudEvent struct {
+	ReceiptHandle string             `json:"receiptHandle"`
+	Event         *cloudevents.Event `json:"event"`
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScheduledMessage is a message held for later delivery. Payload is the
+// publish or send request, dispatched as it was given. For encrypted topics
+// and queues it is encrypted, as a base64 string, and Encryption holds what
+// decrypting it needs.
+type ScheduledMessage struct {
+	ID         string          `json:"id"`
+	Kind       string          `json:"kind"`
+	Target     string          `json:"target"`
+	DeliverAt  time.Time       `json:"deliverAt"`
+	Payload    json.RawMessage `json:"payload"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Status     string          `json:"status"`
+	Attempts   int             `json:"attempts,omitempty"`
+	RetryAt    *time.Time      `json:"retryAt,omitempty"`
+	LastError  string          `json:"lastError,omitempty"`
+	Encryption string          `json:"encryption,omitempty"`
+}
+
+// Recurring is a schedule that publishes a templated message to a topic, or
+// sends one to a queue, on each run of a cron expression. Template is a Go
+// text/template executed with .Name, .ScheduledTime and .FiredTime; Subject
+// and Attributes apply to queue messages only. NextRun and LastRun are filled
+// in when the schedule is read.
+type Recurring struct {
+	Name        string            `json:"name"`
+	Cron        string            `json:"cron"`
+	TimeZone    string            `json:"timeZone,omitempty"`
+	Kind        string            `json:"kind"`
+	Target      string            `json:"target"`
+	Template    string            `json:"template"`
+	ContentType string            `json:"contentType,omitempty"`
+	Subject     string            `json:"subject,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	MissedRuns  string            `json:"missedRuns"`
+	Paused      bool              `json:"paused,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	NextRun     *time.Time        `json:"nextRun,omitempty"`
+	LastRun     *time.Time        `json:"lastRun,omitempty"`
+}
+
+// Run is an entry in the execution history of a schedule. Missed counts the
+// earlier runs it stands in for under the missed run policy.
+type Run struct {
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	FiredAt     *time.Time `json:"firedAt,omitempty"`
+	Status      string     `json:"status"`
+	Missed      int        `json:"missed,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Instance    string     `json:"instance"`
+}
+
+// RecurringInput describes a recurring schedule that publishes to a topic or
+// sends to a queue, both given by name. Template is a Go text/template
+// executed with .Name, .ScheduledTime and .FiredTime.
+type RecurringInput struct {
+	Cron        string            `json:"cron" binding:"required,max=256"`
+	TimeZone    string            `json:"timeZone" binding:"omitempty,timezone"`
+	Topic       string            `json:"topic" binding:"required_without=Queue,excluded_with=Queue,omitempty,topicname"`
+	Queue       string            `json:"queue" binding:"omitempty,queuename"`
+	Template    string            `json:"template" binding:"required,max=262144"`
+	ContentType string            `json:"contentType" binding:"max=256"`
+	Subject     string            `json:"subject" binding:"max=256"`
+	Attributes  map[string]string `json:"attributes" binding:"max=9"`
+	MissedRuns  string            `json:"missedRuns" binding:"omitempty,oneof=once skip all"`
+	Paused      bool              `json:"paused"`
+}
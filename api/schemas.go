@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Schema is a version of a topic's schema. Its ID is derived from the format
+// and definition, so identical schemas share an ID across topics and
+// versions.
+type Schema struct {
+	ID         string          `json:"id"`
+	Topic      string          `json:"topic"`
+	Version    int             `json:"version"`
+	Format     string          `json:"format"`
+	Definition json.RawMessage `json:"definition"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// Stamp returns the value of the Schema message attribute for messages
+// matching s.
+func (s *Schema) Stamp() string {
+	return s.ID + ":" + strconv.Itoa(s.Version) + ":" + s.Format
+}
+
+// SchemaConfig is the schema configuration of a topic. A zero ActiveVersion
+// means the latest registered version is active.
+type SchemaConfig struct {
+	Compatibility string `json:"compatibility"`
+	ActiveVersion int    `json:"activeVersion"`
+}
+
+type RegisterSchemaInput struct {
+	Format     string          `json:"format" binding:"omitempty,oneof=JSON AVRO PROTOBUF"`
+	Definition json.RawMessage `json:"definition" binding:"required"`
+}
+
+type SchemaConfigInput struct {
+	Compatibility string `json:"compatibility" binding:"required,oneof=NONE BACKWARD BACKWARD_TRANSITIVE FORWARD FORWARD_TRANSITIVE FULL FULL_TRANSITIVE"`
+	ActiveVersion int    `json:"activeVersion" binding:"min=0"`
+}
@@ -0,0 +1,28 @@
+package api
+
+// TopicSettings holds the service-side settings of a topic.
+type TopicSettings struct {
+	// CloudEvents requires messages published to the topic to be CloudEvents.
+	CloudEvents bool `json:"cloudEvents"`
+	// Encrypt turns on envelope encryption of messages published to the topic.
+	Encrypt bool `json:"encrypt"`
+}
+
+// QueueSettings holds the service-side settings of a queue.
+type QueueSettings struct {
+	// Encrypt turns on envelope encryption of messages sent to the queue.
+	Encrypt bool `json:"encrypt"`
+}
+
+// TopicSettingsInput updates the settings that are set, leaving the others
+// unchanged.
+type TopicSettingsInput struct {
+	CloudEvents *bool `json:"cloudEvents"`
+	Encrypt     *bool `json:"encrypt"`
+}
+
+// QueueSettingsInput updates the settings that are set, leaving the others
+// unchanged.
+type QueueSettingsInput struct {
+	Encrypt *bool `json:"encrypt"`
+}
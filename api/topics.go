@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TopicEncryption holds the server-side encryption settings of a topic. An
+// empty KMS key ID turns encryption off; nil leaves it unchanged.
+type TopicEncryption struct {
+	KMSMasterKeyID *string `json:"kmsMasterKeyId,omitempty"`
+}
+
+// TopicAttributes holds the standard attributes of a topic. Nil fields are left
+// unchanged, or at their defaults when creating a topic. FIFO settings can only
+// be given at creation.
+type TopicAttributes struct {
+	TopicEncryption
+	DisplayName               *string         `json:"displayName,omitempty"`
+	DeliveryPolicy            json.RawMessage `json:"deliveryPolicy,omitempty"`
+	Policy                    json.RawMessage `json:"policy,omitempty"`
+	FifoTopic                 *bool           `json:"fifoTopic,omitempty"`
+	ContentBasedDeduplication *bool           `json:"contentBasedDeduplication,omitempty"`
+}
+
+// TopicDescription is a topic's attributes along with its read-only details.
+type TopicDescription struct {
+	TopicAttributes
+	TopicARN               string `json:"topicArn"`
+	Owner                  string `json:"owner"`
+	SubscriptionsConfirmed int64  `json:"subscriptionsConfirmed"`
+	SubscriptionsPending   int64  `json:"subscriptionsPending"`
+	SubscriptionsDeleted   int64  `json:"subscriptionsDeleted"`
+}
+
+// SubscriptionAttributes holds the settable attributes of a subscription. Nil
+// fields are left unchanged; a filter policy of {} removes the filter.
+type SubscriptionAttributes struct {
+	FilterPolicy       json.RawMessage `json:"filterPolicy,omitempty"`
+	FilterPolicyScope  *string         `json:"filterPolicyScope,omitempty"`
+	RawMessageDelivery *bool           `json:"rawMessageDelivery,omitempty"`
+	// RedrivePolicy sends messages SNS could not deliver to a dead-letter queue,
+	// e.g. {"deadLetterTargetArn": "arn:aws:sqs:..."}
+	RedrivePolicy json.RawMessage `json:"redrivePolicy,omitempty"`
+}
+
+// SNSTopic and SNSSubscription are topics and subscriptions as the /topics
+// routes list them, which pass SNS results through.
+type SNSTopic struct {
+	TopicArn *string
+}
+
+type SNSSubscription struct {
+	Endpoint        *string
+	Owner           *string
+	Protocol        *string
+	SubscriptionArn *string
+	TopicArn        *string
+}
+
+type CreateTopicInput struct {
+	TopicName  string          `json:"topicName" binding:"required,topicname"`
+	Attributes TopicAttributes `json:"attributes"`
+}
+
+type SubscribeEmailToTopicInput struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+type SubscribeQueueToTopicInput struct {
+	QueueName string `json:"queueName" binding:"required,queuename"`
+}
+
+type UnsubscribeFromTopicInput struct {
+	SubscriptionID string `json:"subscriptionID" binding:"required,subscriptionarn"`
+}
+
+// PublishMessageInput carries either a text Message or a binary payload in
+// Data, base64 encoded in JSON. Compression overrides the configured payload
+// compression. DeliverAt or DelaySeconds schedule the message for later.
+type PublishMessageInput struct {
+	Message      string     `json:"message" binding:"required_without=Data"`
+	Data         []byte     `json:"data"`
+	ContentType  string     `json:"contentType" binding:"max=256"`
+	Compression  string     `json:"compression" binding:"omitempty,oneof=none gzip zstd"`
+	DeliverAt    *time.Time `json:"deliverAt,omitempty" binding:"excluded_with=DelaySeconds"`
+	DelaySeconds int64      `json:"delaySeconds,omitempty" binding:"min=0,max=31536000"`
+}
+
+// Delay returns how long to hold the message before it is published.
+func (input PublishMessageInput) Delay() time.Duration {
+	return delay(input.DeliverAt, input.DelaySeconds)
+}
+
+func delay(deliverAt *time.Time, delaySeconds int64) time.Duration {
+	if deliverAt != nil {
+		return time.Until(*deliverAt)
+	}
+	return time.Duration(delaySeconds) * time.Second
+}
+
+// Topic is a topic by name, as addressed by the /v1 routes.
+type Topic struct {
+	Name string `json:"name"`
+	ARN  string `json:"arn"`
+}
+
+// SubscribeInput subscribes either an email address or a queue to a topic.
+type SubscribeInput struct {
+	Email      string                 `json:"email" binding:"required_without=QueueName,excluded_with=QueueName,omitempty,email,max=254"`
+	QueueName  string                 `json:"queueName" binding:"omitempty,queuename"`
+	Attributes SubscriptionAttributes `json:"attributes"`
+}
+
+// Subscription is a topic subscription as returned by the /v1 routes. Its ID
+// is the last segment of its ARN, and is empty while an email subscription
+// is pending confirmation.
+type Subscription struct {
+	ID       string `json:"id"`
+	ARN      string `json:"arn"`
+	Protocol string `json:"protocol"`
+	Endpoint string `json:"endpoint"`
+	Pending  bool   `json:"pending"`
+}
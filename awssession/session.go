@@ -0,0 +1,31 @@
+package awssession
+
+import (
+	"sync"
+
+	"pub-sub-service/metrics"
+	"pub-sub-service/tracing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+var (
+	once sync.Once
+	sess *session.Session
+)
+
+// New returns the session shared by every SNS and SQS client. It loads
+// credentials and configuration from the shared config (~/.aws) and carries
+// the request handlers used for instrumentation.
+func New() *session.Session {
+	once.Do(func() {
+		sess = session.Must(session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		}))
+
+		metrics.InstrumentAWS(&sess.Handlers)
+		tracing.InstrumentAWS(&sess.Handlers)
+	})
+
+	return sess
+}
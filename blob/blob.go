@@ -0,0 +1,168 @@
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"pub-sub-service/logging"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps payloads too large to travel in a message body.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Expirer is a Store that removes blobs itself once they are old enough.
+// Stores without it, such as S3, rely on the backend's own expiry.
+type Expirer interface {
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// DefaultTTL outlives the longest SQS retention period, so that no message
+// outlives its payload.
+const DefaultTTL = 14 * 24 * time.Hour
+
+var (
+	mu            sync.Mutex
+	defaultStore  Store
+	defaultShared bool
+	defaultTTL    = DefaultTTL
+)
+
+// Setup builds the default store from BLOB_BACKEND: "filesystem" (the
+// default, for local runs), which keeps blobs under BLOB_DIR, or "s3", which
+// uses the bucket named by BLOB_BUCKET and the optional key prefix BLOB_PREFIX.
+// BLOB_TTL is how long the filesystem store keeps blobs (default 14 days, 0
+// keeps them).
+func Setup() error {
+	var s Store
+	shared := false
+	switch backend := os.Getenv("BLOB_BACKEND"); backend {
+	case "", "filesystem":
+		s = NewFilesystem(defaultDir())
+	case "s3":
+		bucket := os.Getenv("BLOB_BUCKET")
+		if bucket == "" {
+			return errors.New("BLOB_BUCKET is required for the s3 blob store")
+		}
+		s = NewS3(bucket, os.Getenv("BLOB_PREFIX"))
+		shared = true
+	default:
+		return fmt.Errorf("unknown BLOB_BACKEND %q", backend)
+	}
+
+	ttl := DefaultTTL
+	if value := os.Getenv("BLOB_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid BLOB_TTL %q", value)
+		}
+		ttl = parsed
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultStore = s
+	defaultShared = shared
+	defaultTTL = ttl
+	return nil
+}
+
+// Shared reports whether the default store is shared between instances, so
+// that a blob stored by one instance can be read by another.
+func Shared() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return defaultShared
+}
+
+// Expire deletes blobs older than the configured TTL from the default store
+// on each interval until ctx is cancelled, if the store expires blobs itself.
+func Expire(ctx context.Context, interval time.Duration) {
+	mu.Lock()
+	ttl := defaultTTL
+	mu.Unlock()
+
+	expirer, ok := Default().(Expirer)
+	if !ok || ttl == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := expirer.DeleteOlderThan(ctx, time.Now().Add(-ttl))
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Warn("unable to expire blobs", slog.Any("error", err))
+		}
+		if deleted > 0 {
+			logging.FromContext(ctx).Info("expired blobs", slog.Int("count", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Default returns the store configured by Setup, or a filesystem store if
+// Setup has not been called.
+func Default() Store {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if defaultStore == nil {
+		defaultStore = NewFilesystem(defaultDir())
+	}
+	return defaultStore
+}
+
+func defaultDir() string {
+	if dir := os.Getenv("BLOB_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "pub-sub-service-blobs")
+}
+
+// NewKey returns a new random blob key.
+func NewKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+
+// validateKey rejects keys that could escape the store, since keys are read
+// back from message attributes.
+func validateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
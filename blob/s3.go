@@ -0,0 +1,91 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"pub-sub-service/awssession"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type s3Store struct {
+	svc    *s3.S3
+	bucket string
+	prefix string
+}
+
+// NewS3 returns a Store keeping blobs as objects in an S3 bucket, under an
+// optional key prefix.
+func NewS3(bucket, prefix string) Store {
+	return &s3Store{
+		svc:    s3.New(awssession.New()),
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Ping verifies that the bucket exists and is accessible.
+func (s *s3Store) Ping(ctx context.Context) error {
+	_, err := s.svc.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	return err
+}
+
+func (s *s3Store) key(key string) (*string, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	return aws.String(s.prefix + key), nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	objectKey, err := s.key(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.svc.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    objectKey,
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	objectKey, err := s.key(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    objectKey,
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	return io.ReadAll(result.Body)
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	objectKey, err := s.key(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    objectKey,
+	})
+	return err
+}
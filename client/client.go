@@ -0,0 +1,289 @@
+// Package client is a typed Go client for the pub-sub-service REST API. It
+// shares its request and response types, in package api, with the server and
+// is released with it, so a client matches the routes of the server at the
+// same version.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"pub-sub-service/api"
+	"pub-sub-service/version"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the service. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
+}
+
+// RetryPolicy controls how requests that fail with 429, a 5xx status or a
+// transport error are retried, with exponential backoff and full jitter. A
+// Retry-After header from the service takes precedence over the backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts; 1 disables retries
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy makes up to 4 attempts, backing off from 100ms to 5s.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, e.g. for TLS
+// client certificates or a custom transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(retry RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithUserAgent prefixes the client's User-Agent with the caller's.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent + " " + c.userAgent
+	}
+}
+
+// New returns a client for the service at baseURL, e.g.
+// "http://localhost:8080".
+func New(baseURL string, options ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid service URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		userAgent:  "pub-sub-service-client/" + version.Version,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+
+	return c, nil
+}
+
+// Error is an error response from the service.
+type Error struct {
+	StatusCode int
+	Message    string
+	// Errors lists details such as schema validation failures, when given
+	Errors []string
+	// Fields lists the request fields that failed validation
+	Fields []api.FieldError
+	// Body is the raw response body
+	Body json.RawMessage
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	details := e.Errors
+	for _, field := range e.Fields {
+		details = append(details, field.Field+" "+field.Message)
+	}
+	if len(details) > 0 {
+		message += ": " + strings.Join(details, "; ")
+	}
+	return fmt.Sprintf("pub-sub-service: %s (%d)", message, e.StatusCode)
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// request is an API call. Body is sent as JSON unless it is raw bytes, which
+// are sent as is with ContentType.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        any
+	contentType string
+	header      http.Header
+}
+
+// do sends a request and decodes the "response" field of the reply into out,
+// if out is not nil.
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	response, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	var reply struct {
+		Response json.RawMessage `json:"response"`
+	}
+	if err := json.Unmarshal(response, &reply); err != nil {
+		return fmt.Errorf("pub-sub-service: decoding response: %w", err)
+	}
+	if len(reply.Response) == 0 || string(reply.Response) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(reply.Response, out); err != nil {
+		return fmt.Errorf("pub-sub-service: decoding response: %w", err)
+	}
+	return nil
+}
+
+// send sends a request, retrying as the retry policy allows, and returns the
+// body of a successful response.
+func (c *Client) send(ctx context.Context, r request) ([]byte, error) {
+	var body []byte
+	contentType := r.contentType
+	switch value := r.body.(type) {
+	case nil:
+	case []byte:
+		body = value
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		contentType = "application/json"
+	}
+
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	for attempt := 1; ; attempt++ {
+		request, err := http.NewRequestWithContext(ctx, r.method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range r.header {
+			request.Header[name] = values
+		}
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+		request.Header.Set("Accept", "application/json")
+		request.Header.Set("User-Agent", c.userAgent)
+
+		responseBody, retryAfter, err := c.roundTrip(request)
+		if err == nil {
+			return responseBody, nil
+		}
+		if attempt >= c.retry.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			wait = c.backoff(attempt)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) roundTrip(request *http.Request) ([]byte, time.Duration, error) {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if response.StatusCode < 300 {
+		return body, 0, nil
+	}
+
+	apiErr := &Error{StatusCode: response.StatusCode, Body: body}
+	var reply struct {
+		Message string           `json:"message"`
+		Error   string           `json:"error"`
+		Errors  []string         `json:"errors"`
+		Fields  []api.FieldError `json:"fields"`
+	}
+	if json.Unmarshal(body, &reply) == nil {
+		apiErr.Message = reply.Message
+		apiErr.Errors = reply.Errors
+		apiErr.Fields = reply.Fields
+		if reply.Error != "" {
+			apiErr.Errors = append(apiErr.Errors, reply.Error)
+		}
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return nil, retryAfter, apiErr
+}
+
+// retryable reports whether a failed attempt may succeed if retried: the
+// service was throttling or failed, or the request didn't get through.
+func retryable(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	backoff := c.retry.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || (c.retry.MaxBackoff > 0 && backoff > c.retry.MaxBackoff) {
+		backoff = c.retry.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return rand.N(backoff) + 1
+}
+
+func pathSegment(segment string) string {
+	return url.PathEscape(segment)
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"pub-sub-service/api"
+	"sync"
+	"time"
+)
+
+// Handler processes a received message. Returning nil deletes the message;
+// returning an error leaves it on the queue to be redelivered.
+type Handler func(ctx context.Context, message *api.ReceivedMessage) error
+
+// ConsumerOptions configure Consume and Messages.
+type ConsumerOptions struct {
+	// VisibilityTimeout is how long, in seconds, a message stays hidden
+	// while it is processed (default 30)
+	VisibilityTimeout int
+	// Decode decodes schema-encoded payloads to JSON
+	Decode bool
+	// Concurrency is the number of messages processed at once (default 1)
+	Concurrency int
+	// PollInterval is how long to wait when the queue is empty (default 1s)
+	PollInterval time.Duration
+	// RetryDelay, when set, makes a failed message visible again after this
+	// long instead of after the visibility timeout
+	RetryDelay *time.Duration
+	// OnError, when set, is called when handler fails or a processed message
+	// can't be deleted
+	OnError func(message *api.ReceivedMessage, err error)
+}
+
+func (o ConsumerOptions) withDefaults() ConsumerOptions {
+	if o.VisibilityTimeout <= 0 {
+		o.VisibilityTimeout = 30
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	return o
+}
+
+// Messages streams messages from a queue until ctx is done or receiving
+// fails; the caller deletes each message once processed. Errors the retry
+// policy gave up on end the stream.
+func (c *Client) Messages(ctx context.Context, queueName string, options ConsumerOptions) iter.Seq2[*api.ReceivedMessage, error] {
+	options = options.withDefaults()
+
+	return func(yield func(*api.ReceivedMessage, error) bool) {
+		for ctx.Err() == nil {
+			message, err := c.ReceiveMessage(ctx, queueName, api.ReceiveMessageInput{
+				VisibilityTimeout: options.VisibilityTimeout,
+				Decode:            options.Decode,
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if message == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(options.PollInterval):
+				}
+				continue
+			}
+
+			if !yield(message, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Consume receives messages from a queue and passes them to handler, with up
+// to options.Concurrency in flight, deleting each message handler succeeds
+// on. It returns when ctx is done, after in-flight messages finish, or when
+// receiving fails.
+func (c *Client) Consume(ctx context.Context, queueName string, options ConsumerOptions, handler Handler) error {
+	options = options.withDefaults()
+
+	// Handlers run on their own context so that stopping the consumer lets
+	// in-flight messages finish and be deleted
+	handlerCtx := context.WithoutCancel(ctx)
+
+	slots := make(chan struct{}, options.Concurrency)
+	var inFlight sync.WaitGroup
+	defer inFlight.Wait()
+
+	for message, err := range c.Messages(ctx, queueName, options) {
+		if err != nil {
+			return err
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		inFlight.Add(1)
+		go func() {
+			defer inFlight.Done()
+			defer func() { <-slots }()
+
+			c.handle(handlerCtx, queueName, message, options, handler)
+		}()
+	}
+
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) handle(ctx context.Context, queueName string, message *api.ReceivedMessage, options ConsumerOptions, handler Handler) {
+	onError := func(err error) {
+		if options.OnError != nil {
+			options.OnError(message, err)
+		}
+	}
+
+	if err := handler(ctx, message); err != nil {
+		onError(err)
+		if options.RetryDelay != nil {
+			if err := c.ChangeMessageVisibility(ctx, queueName, message.ReceiptHandle, int(options.RetryDelay.Seconds())); err != nil {
+				onError(err)
+			}
+		}
+		return
+	}
+
+	if err := c.DeleteMessage(ctx, queueName, message.ReceiptHandle); err != nil {
+		onError(err)
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"pub-sub-service/api"
+)
+
+// ServerVersion returns the version of the service, checking it is alive.
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	body, err := c.send(ctx, request{method: http.MethodGet, path: "/healthz"})
+	if err != nil {
+		return "", err
+	}
+
+	var reply struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return "", err
+	}
+	return reply.Version, nil
+}
+
+// Readiness returns the service's readiness report. A service that is not
+// ready returns its report along with a 503 Error; readiness is not retried.
+func (c *Client) Readiness(ctx context.Context) (*api.HealthReport, error) {
+	noRetry := *c
+	noRetry.retry.MaxAttempts = 1
+
+	body, err := noRetry.send(ctx, request{method: http.MethodGet, path: "/readyz"})
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable {
+		body = apiErr.Body
+	} else if err != nil {
+		return nil, err
+	}
+
+	var report api.HealthReport
+	if jsonErr := json.Unmarshal(body, &report); jsonErr != nil {
+		return nil, jsonErr
+	}
+	return &report, err
+}
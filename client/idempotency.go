@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context whose publishes and sends use key as
+// their Idempotency-Key, so that repeating one, e.g. after a producer
+// restart, returns the original result instead of publishing again. Without
+// it each call uses a new key, which deduplicates only the client's own
+// retries.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyHeader returns the Idempotency-Key header for a publish or
+// send made with ctx
+func idempotencyHeader(ctx context.Context) http.Header {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	if key == "" {
+		random := make([]byte, 16)
+		rand.Read(random)
+		key = hex.EncodeToString(random)
+	}
+	return http.Header{idempotencyKeyHeader: {key}}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"pub-sub-service/api"
+)
+
+// The iterators walk a listing page by page. The service currently returns
+// each listing in one page, having paged through AWS itself, so they make a
+// single request; callers that range over them keep working if the service
+// starts paginating.
+
+// paginate yields the items of the pages fetched by list, stopping at the
+// first error.
+func paginate[T any](list func() ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		items, err := list()
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Topics iterates over the topics.
+func (c *Client) Topics(ctx context.Context) iter.Seq2[*api.SNSTopic, error] {
+	return paginate(func() ([]*api.SNSTopic, error) {
+		return c.ListTopics(ctx)
+	})
+}
+
+// Subscriptions iterates over the subscriptions to a topic.
+func (c *Client) Subscriptions(ctx context.Context, topicARN string) iter.Seq2[*api.SNSSubscription, error] {
+	return paginate(func() ([]*api.SNSSubscription, error) {
+		return c.ListSubscriptions(ctx, topicARN)
+	})
+}
+
+// Queues iterates over the queue URLs.
+func (c *Client) Queues(ctx context.Context) iter.Seq2[string, error] {
+	return paginate(func() ([]string, error) {
+		return c.ListQueues(ctx)
+	})
+}
+
+// Schemas iterates over the schema versions of a topic.
+func (c *Client) Schemas(ctx context.Context, topicARN string) iter.Seq2[api.Schema, error] {
+	return paginate(func() ([]api.Schema, error) {
+		return c.ListSchemas(ctx, topicARN)
+	})
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"pub-sub-service/api"
+)
+
+func manifestQuery(prune bool) url.Values {
+	if !prune {
+		return nil
+	}
+	return url.Values{"prune": {"true"}}
+}
+
+// PlanManifest compares a YAML or JSON manifest with the actual state.
+func (c *Client) PlanManifest(ctx context.Context, document []byte, prune bool) (*api.Plan, error) {
+	var plan api.Plan
+	err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        "/manifest/plan",
+		query:       manifestQuery(prune),
+		body:        document,
+		contentType: "application/yaml",
+	}, &plan)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ApplyManifest converges the actual state to a manifest. When a change
+// fails, the plan is returned along with the error, showing which changes
+// were applied. Apply is not retried, as changes may have been made.
+func (c *Client) ApplyManifest(ctx context.Context, document []byte, prune bool) (*api.Plan, error) {
+	noRetry := *c
+	noRetry.retry.MaxAttempts = 1
+
+	var plan api.Plan
+	err := noRetry.do(ctx, request{
+		method:      http.MethodPost,
+		path:        "/manifest/apply",
+		query:       manifestQuery(prune),
+		body:        document,
+		contentType: "application/yaml",
+	}, &plan)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		var reply struct {
+			Plan *api.Plan `json:"plan"`
+		}
+		if json.Unmarshal(apiErr.Body, &reply) == nil && reply.Plan != nil {
+			return reply.Plan, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
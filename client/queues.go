@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"pub-sub-service/api"
+)
+
+func queuePath(queueName string) string {
+	return "/queues/" + pathSegment(queueName)
+}
+
+// ListQueues returns the URLs of the queues.
+func (c *Client) ListQueues(ctx context.Context) ([]string, error) {
+	var queueURLs []string
+	err := c.do(ctx, request{method: http.MethodGet, path: "/queues"}, &queueURLs)
+	return queueURLs, err
+}
+
+func (c *Client) CreateQueue(ctx context.Context, input api.CreateQueueInput) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/queues", body: input}, nil)
+}
+
+func (c *Client) GetQueueURL(ctx context.Context, queueName string) (string, error) {
+	var queueURL string
+	err := c.do(ctx, request{method: http.MethodGet, path: queuePath(queueName)}, &queueURL)
+	return queueURL, err
+}
+
+func (c *Client) DeleteQueue(ctx context.Context, queueName string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: queuePath(queueName)}, nil)
+}
+
+func (c *Client) SendMessage(ctx context.Context, queueName string, input api.SendMessageInput) error {
+	return c.do(ctx, request{
+		method: http.MethodPost,
+		path:   queuePath(queueName) + "/messages",
+		body:   input,
+		header: idempotencyHeader(ctx),
+	}, nil)
+}
+
+// ReceiveMessage receives a message, or nil when the queue is empty.
+func (c *Client) ReceiveMessage(ctx context.Context, queueName string, input api.ReceiveMessageInput) (*api.ReceivedMessage, error) {
+	input.CloudEvents = false
+
+	var message *api.ReceivedMessage
+	err := c.do(ctx, request{method: http.MethodPut, path: queuePath(queueName) + "/messages/receive", body: input}, &message)
+	return message, err
+}
+
+// ReceiveCloudEvent receives a message as a CloudEvent, or nil when the
+// queue is empty.
+func (c *Client) ReceiveCloudEvent(ctx context.Context, queueName string, input api.ReceiveMessageInput) (*api.ReceivedCloudEvent, error) {
+	input.CloudEvents = true
+
+	var event *api.ReceivedCloudEvent
+	err := c.do(ctx, request{method: http.MethodPut, path: queuePath(queueName) + "/messages/receive", body: input}, &event)
+	return event, err
+}
+
+func (c *Client) DeleteMessage(ctx context.Context, queueName, receiptHandle string) error {
+	return c.do(ctx, request{
+		method: http.MethodPut,
+		path:   queuePath(queueName) + "/messages/delete",
+		body:   api.DeleteMessageInput{ReceiptHandle: receiptHandle},
+	}, nil)
+}
+
+func (c *Client) ChangeMessageVisibility(ctx context.Context, queueName, receiptHandle string, visibilityTimeout int) error {
+	return c.do(ctx, request{
+		method: http.MethodPut,
+		path:   queuePath(queueName) + "/messages/visibility",
+		body:   api.ChangeMessageVisibilityInput{ReceiptHandle: receiptHandle, VisibilityTimeout: visibilityTimeout},
+	}, nil)
+}
+
+func (c *Client) GetQueueAttributes(ctx context.Context, queueName string) (*api.QueueDescription, error) {
+	var description api.QueueDescription
+	err := c.do(ctx, request{method: http.MethodGet, path: queuePath(queueName) + "/attributes"}, &description)
+	if err != nil {
+		return nil, err
+	}
+	return &description, nil
+}
+
+func (c *Client) SetQueueAttributes(ctx context.Context, queueName string, attributes api.QueueAttributes) error {
+	return c.do(ctx, request{method: http.MethodPut, path: queuePath(queueName) + "/attributes", body: attributes}, nil)
+}
+
+func (c *Client) ListQueueTags(ctx context.Context, queueName string) (map[string]string, error) {
+	var tags map[string]string
+	err := c.do(ctx, request{method: http.MethodGet, path: queuePath(queueName) + "/tags"}, &tags)
+	return tags, err
+}
+
+func (c *Client) TagQueue(ctx context.Context, queueName string, tags map[string]string) error {
+	return c.do(ctx, request{method: http.MethodPut, path: queuePath(queueName) + "/tags", body: api.TagInput{Tags: tags}}, nil)
+}
+
+func (c *Client) UntagQueue(ctx context.Context, queueName string, keys ...string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: queuePath(queueName) + "/tags", query: url.Values{"key": keys}}, nil)
+}
+
+func (c *Client) GetQueueEncryption(ctx context.Context, queueName string) (*api.QueueEncryption, error) {
+	var encryption api.QueueEncryption
+	err := c.do(ctx, request{method: http.MethodGet, path: queuePath(queueName) + "/encryption"}, &encryption)
+	if err != nil {
+		return nil, err
+	}
+	return &encryption, nil
+}
+
+func (c *Client) SetQueueEncryption(ctx context.Context, queueName string, encryption api.QueueEncryption) error {
+	return c.do(ctx, request{method: http.MethodPut, path: queuePath(queueName) + "/encryption", body: encryption}, nil)
+}
+
+func (c *Client) GetQueueSettings(ctx context.Context, queueName string) (*api.QueueSettings, error) {
+	var queueSettings api.QueueSettings
+	err := c.do(ctx, request{method: http.MethodGet, path: queuePath(queueName) + "/settings"}, &queueSettings)
+	if err != nil {
+		return nil, err
+	}
+	return &queueSettings, nil
+}
+
+// SetQueueSettings updates the settings that are set and returns the result.
+func (c *Client) SetQueueSettings(ctx context.Context, queueName string, input api.QueueSettingsInput) (*api.QueueSettings, error) {
+	var queueSettings api.QueueSettings
+	err := c.do(ctx, request{method: http.MethodPut, path: queuePath(queueName) + "/settings", body: input}, &queueSettings)
+	if err != nil {
+		return nil, err
+	}
+	return &queueSettings, nil
+}
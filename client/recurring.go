@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"pub-sub-service/api"
+	"strconv"
+)
+
+func recurringPath(name string) string {
+	return "/v1/recurring/" + pathSegment(name)
+}
+
+// ListRecurring returns the recurring schedules ordered by name.
+func (c *Client) ListRecurring(ctx context.Context) ([]*api.Recurring, error) {
+	var schedules []*api.Recurring
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/recurring"}, &schedules)
+	return schedules, err
+}
+
+func (c *Client) GetRecurring(ctx context.Context, name string) (*api.Recurring, error) {
+	var recurring api.Recurring
+	err := c.do(ctx, request{method: http.MethodGet, path: recurringPath(name)}, &recurring)
+	if err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// PutRecurring creates or replaces a recurring schedule.
+func (c *Client) PutRecurring(ctx context.Context, name string, input api.RecurringInput) (*api.Recurring, error) {
+	var recurring api.Recurring
+	err := c.do(ctx, request{method: http.MethodPut, path: recurringPath(name), body: input}, &recurring)
+	if err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (c *Client) DeleteRecurring(ctx context.Context, name string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: recurringPath(name)}, nil)
+}
+
+// RecurringHistory returns up to limit of the most recent runs of a
+// schedule, newest first; zero uses the server's default of 100.
+func (c *Client) RecurringHistory(ctx context.Context, name string, limit int) ([]*api.Run, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var runs []*api.Run
+	err := c.do(ctx, request{method: http.MethodGet, path: recurringPath(name) + "/history", query: query}, &runs)
+	return runs, err
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"pub-sub-service/api"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PublishRequest publishes a request to a topic, given by name, and returns
+// the reply. It waits up to timeout, or the server's default of ten seconds
+// when zero; no reply in time is a 504 *Error. Requests are not retried, as
+// the request may have been handled.
+func (c *Client) PublishRequest(ctx context.Context, topicName string, input api.PublishMessageInput, timeout time.Duration) (*api.ReceivedMessage, error) {
+	return c.request(ctx, "/v1/topics/"+pathSegment(topicName)+"/requests", input, timeout)
+}
+
+// SendRequest sends a request to a queue and returns the reply, waiting as
+// PublishRequest does.
+func (c *Client) SendRequest(ctx context.Context, queueName string, input api.SendMessageInput, timeout time.Duration) (*api.ReceivedMessage, error) {
+	return c.request(ctx, "/v1/queues/"+pathSegment(queueName)+"/requests", input, timeout)
+}
+
+func (c *Client) request(ctx context.Context, path string, input any, timeout time.Duration) (*api.ReceivedMessage, error) {
+	noRetry := *c
+	noRetry.retry.MaxAttempts = 1
+
+	var query url.Values
+	if timeout > 0 {
+		query = url.Values{"timeout": {strconv.Itoa(int((timeout + time.Second - 1) / time.Second))}}
+	}
+
+	var reply api.ReceivedMessage
+	err := noRetry.do(ctx, request{method: http.MethodPost, path: path, query: query, body: input}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Reply sends reply to the queue a request asks replies to be sent to, with
+// the request's correlation ID, for consumers answering requests. Requests
+// whose ReplyTo is not a reply queue are not replied to.
+func (c *Client) Reply(ctx context.Context, request *api.ReceivedMessage, reply api.SendMessageInput) error {
+	replyTo := request.Attributes[api.ReplyToAttribute]
+	if replyTo == "" {
+		return errors.New("pub-sub-service: message is not a request: it has no ReplyTo attribute")
+	}
+	if !strings.HasPrefix(replyTo, api.ReplyQueuePrefix) {
+		return fmt.Errorf("pub-sub-service: ReplyTo %q is not a reply queue", replyTo)
+	}
+
+	reply.CorrelationID = request.Attributes[api.CorrelationIDAttribute]
+	return c.SendMessage(ctx, replyTo, reply)
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"pub-sub-service/api"
+)
+
+// SchedulePublish publishes a message to a topic at input.DeliverAt or after
+// input.DelaySeconds, and returns the scheduled message.
+func (c *Client) SchedulePublish(ctx context.Context, topicARN string, input api.PublishMessageInput) (*api.ScheduledMessage, error) {
+	if input.Delay() <= 0 {
+		return nil, errors.New("pub-sub-service: scheduled publish needs a future deliverAt or delaySeconds")
+	}
+
+	var message api.ScheduledMessage
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   topicPath(topicARN),
+		body:   input,
+		header: idempotencyHeader(ctx),
+	}, &message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// ScheduleSend sends a message to a queue at input.DeliverAt or after
+// input.DelaySeconds. Delays SQS can hold itself are sent straight away and
+// return a nil message; longer ones return the scheduled message.
+func (c *Client) ScheduleSend(ctx context.Context, queueName string, input api.SendMessageInput) (*api.ScheduledMessage, error) {
+	var response json.RawMessage
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   queuePath(queueName) + "/messages",
+		body:   input,
+		header: idempotencyHeader(ctx),
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQS sends reply with true rather than a scheduled message
+	if len(response) == 0 || response[0] != '{' {
+		return nil, nil
+	}
+
+	var message api.ScheduledMessage
+	if err := json.Unmarshal(response, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// ListScheduled returns the pending scheduled messages in delivery order,
+// followed by those that failed.
+func (c *Client) ListScheduled(ctx context.Context) ([]*api.ScheduledMessage, error) {
+	var messages []*api.ScheduledMessage
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/scheduled"}, &messages)
+	return messages, err
+}
+
+func (c *Client) GetScheduled(ctx context.Context, id string) (*api.ScheduledMessage, error) {
+	var message api.ScheduledMessage
+	err := c.do(ctx, request{method: http.MethodGet, path: "/v1/scheduled/" + pathSegment(id)}, &message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// CancelScheduled cancels a scheduled message. A message already being
+// dispatched may still be delivered.
+func (c *Client) CancelScheduled(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/v1/scheduled/" + pathSegment(id)}, nil)
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"pub-sub-service/api"
+	"strconv"
+)
+
+func (c *Client) ListSchemas(ctx context.Context, topicARN string) ([]api.Schema, error) {
+	var schemas []api.Schema
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/schemas"}, &schemas)
+	return schemas, err
+}
+
+// RegisterSchema registers a new schema version for a topic. A schema that
+// breaks the topic's compatibility rule fails with a 409 Error.
+func (c *Client) RegisterSchema(ctx context.Context, topicARN string, input api.RegisterSchemaInput) (*api.Schema, error) {
+	var registered api.Schema
+	err := c.do(ctx, request{method: http.MethodPost, path: topicPath(topicARN) + "/schemas", body: input}, &registered)
+	if err != nil {
+		return nil, err
+	}
+	return &registered, nil
+}
+
+func (c *Client) GetSchema(ctx context.Context, topicARN string, version int) (*api.Schema, error) {
+	var found api.Schema
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/schemas/" + strconv.Itoa(version)}, &found)
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (c *Client) GetSchemaConfig(ctx context.Context, topicARN string) (*api.SchemaConfig, error) {
+	var config api.SchemaConfig
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/schemas/config"}, &config)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Client) SetSchemaConfig(ctx context.Context, topicARN string, input api.SchemaConfigInput) (*api.SchemaConfig, error) {
+	var config api.SchemaConfig
+	err := c.do(ctx, request{method: http.MethodPut, path: topicPath(topicARN) + "/schemas/config", body: input}, &config)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"pub-sub-service/api"
+	"pub-sub-service/cloudevents"
+)
+
+func topicPath(topicARN string) string {
+	return "/topics/" + pathSegment(topicARN)
+}
+
+func (c *Client) ListTopics(ctx context.Context) ([]*api.SNSTopic, error) {
+	var topics []*api.SNSTopic
+	err := c.do(ctx, request{method: http.MethodGet, path: "/topics"}, &topics)
+	return topics, err
+}
+
+// CreateTopic creates a topic and returns its ARN.
+func (c *Client) CreateTopic(ctx context.Context, input api.CreateTopicInput) (string, error) {
+	var output struct{ TopicArn string }
+	err := c.do(ctx, request{method: http.MethodPost, path: "/topics", body: input}, &output)
+	if err != nil {
+		return "", err
+	}
+	return output.TopicArn, nil
+}
+
+func (c *Client) ListSubscriptions(ctx context.Context, topicARN string) ([]*api.SNSSubscription, error) {
+	var subscriptions []*api.SNSSubscription
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/subscriptions"}, &subscriptions)
+	return subscriptions, err
+}
+
+// SubscribeEmailToTopic subscribes an email address and returns the
+// subscription ARN, "pending confirmation" until the address confirms.
+func (c *Client) SubscribeEmailToTopic(ctx context.Context, topicARN, email string) (string, error) {
+	var output struct{ SubscriptionArn string }
+	err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   topicPath(topicARN) + "/subscribe/email",
+		body:   api.SubscribeEmailToTopicInput{Email: email},
+	}, &output)
+	if err != nil {
+		return "", err
+	}
+	return output.SubscriptionArn, nil
+}
+
+func (c *Client) SubscribeQueueToTopic(ctx context.Context, topicARN, queueName string) error {
+	return c.do(ctx, request{
+		method: http.MethodPut,
+		path:   topicPath(topicARN) + "/subscribe/queue",
+		body:   api.SubscribeQueueToTopicInput{QueueName: queueName},
+	}, nil)
+}
+
+func (c *Client) UnsubscribeFromTopic(ctx context.Context, topicARN, subscriptionARN string) error {
+	return c.do(ctx, request{
+		method: http.MethodPut,
+		path:   topicPath(topicARN) + "/unsubscribe",
+		body:   api.UnsubscribeFromTopicInput{SubscriptionID: subscriptionARN},
+	}, nil)
+}
+
+// Publish publishes a message to every subscriber of a topic and returns its
+// message ID.
+func (c *Client) Publish(ctx context.Context, topicARN string, input api.PublishMessageInput) (string, error) {
+	var output struct{ MessageId string }
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   topicPath(topicARN),
+		body:   input,
+		header: idempotencyHeader(ctx),
+	}, &output)
+	if err != nil {
+		return "", err
+	}
+	return output.MessageId, nil
+}
+
+// PublishCloudEvent publishes a CloudEvent in structured mode and returns
+// the message ID.
+func (c *Client) PublishCloudEvent(ctx context.Context, topicARN string, event *cloudevents.Event) (string, error) {
+	data, err := event.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	var output struct{ MessageId string }
+	err = c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        topicPath(topicARN),
+		body:        data,
+		contentType: cloudevents.ContentType,
+		header:      idempotencyHeader(ctx),
+	}, &output)
+	if err != nil {
+		return "", err
+	}
+	return output.MessageId, nil
+}
+
+func (c *Client) GetTopicAttributes(ctx context.Context, topicARN string) (*api.TopicDescription, error) {
+	var description api.TopicDescription
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/attributes"}, &description)
+	if err != nil {
+		return nil, err
+	}
+	return &description, nil
+}
+
+func (c *Client) SetTopicAttributes(ctx context.Context, topicARN string, attributes api.TopicAttributes) error {
+	return c.do(ctx, request{method: http.MethodPut, path: topicPath(topicARN) + "/attributes", body: attributes}, nil)
+}
+
+func (c *Client) ListTopicTags(ctx context.Context, topicARN string) (map[string]string, error) {
+	var tags map[string]string
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/tags"}, &tags)
+	return tags, err
+}
+
+func (c *Client) TagTopic(ctx context.Context, topicARN string, tags map[string]string) error {
+	return c.do(ctx, request{method: http.MethodPut, path: topicPath(topicARN) + "/tags", body: api.TagInput{Tags: tags}}, nil)
+}
+
+func (c *Client) UntagTopic(ctx context.Context, topicARN string, keys ...string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: topicPath(topicARN) + "/tags", query: url.Values{"key": keys}}, nil)
+}
+
+func (c *Client) GetTopicEncryption(ctx context.Context, topicARN string) (*api.TopicEncryption, error) {
+	var encryption api.TopicEncryption
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/encryption"}, &encryption)
+	if err != nil {
+		return nil, err
+	}
+	return &encryption, nil
+}
+
+func (c *Client) SetTopicEncryption(ctx context.Context, topicARN string, encryption api.TopicEncryption) error {
+	return c.do(ctx, request{method: http.MethodPut, path: topicPath(topicARN) + "/encryption", body: encryption}, nil)
+}
+
+func (c *Client) GetTopicSettings(ctx context.Context, topicARN string) (*api.TopicSettings, error) {
+	var topicSettings api.TopicSettings
+	err := c.do(ctx, request{method: http.MethodGet, path: topicPath(topicARN) + "/settings"}, &topicSettings)
+	if err != nil {
+		return nil, err
+	}
+	return &topicSettings, nil
+}
+
+// SetTopicSettings updates the settings that are set and returns the result.
+func (c *Client) SetTopicSettings(ctx context.Context, topicARN string, input api.TopicSettingsInput) (*api.TopicSettings, error) {
+	var topicSettings api.TopicSettings
+	err := c.do(ctx, request{method: http.MethodPut, path: topicPath(topicARN) + "/settings", body: input}, &topicSettings)
+	if err != nil {
+		return nil, err
+	}
+	return &topicSettings, nil
+}
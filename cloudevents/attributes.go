@@ -0,0 +1,53 @@
+package cloudevents
+
+import (
+	"strings"
+	"time"
+)
+
+// AttributePrefix prefixes the message attributes CloudEvent context
+// attributes are mapped to. The data content type maps to the payload
+// ContentType attribute instead.
+const AttributePrefix = "ce-"
+
+// ToAttributes maps the context attributes of an event to message attributes.
+func ToAttributes(event *Event, attributes map[string]string) {
+	set := func(name, value string) {
+		if value != "" {
+			attributes[AttributePrefix+name] = value
+		}
+	}
+
+	set("specversion", event.SpecVersion)
+	set("id", event.ID)
+	set("source", event.Source)
+	set("type", event.Type)
+	set("subject", event.Subject)
+	set("dataschema", event.DataSchema)
+	if event.Time != nil {
+		set("time", event.Time.Format(time.RFC3339Nano))
+	}
+	for name, value := range event.Extensions {
+		set(name, value)
+	}
+}
+
+// FromAttributes rebuilds an event from message attributes, reporting false
+// if they carry no CloudEvent.
+func FromAttributes(attributes map[string]string) (*Event, bool) {
+	if attributes[AttributePrefix+"specversion"] == "" {
+		return nil, false
+	}
+
+	event := &Event{}
+	for name, value := range attributes {
+		if !strings.HasPrefix(name, AttributePrefix) {
+			continue
+		}
+		if err := event.setAttribute(strings.TrimPrefix(name, AttributePrefix), value); err != nil {
+			return nil, false
+		}
+	}
+
+	return event, event.Validate() == nil
+}
@@ -0,0 +1,183 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	SpecVersion = "1.0"
+
+	// ContentType is the media type of structured mode events.
+	ContentType = "application/cloudevents+json"
+)
+
+var extensionName = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+
+// Event is a CloudEvents 1.0 event. Data holds JSON data; binary data is
+// carried in DataBase64.
+type Event struct {
+	SpecVersion     string
+	ID              string
+	Source          string
+	Type            string
+	Subject         string
+	Time            *time.Time
+	DataContentType string
+	DataSchema      string
+	Data            json.RawMessage
+	DataBase64      []byte
+	Extensions      map[string]string
+}
+
+// Validate checks the required attributes and extension names.
+func (e *Event) Validate() error {
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("unsupported specversion %q", e.SpecVersion)
+	}
+	if e.ID == "" || e.Source == "" || e.Type == "" {
+		return errors.New("id, source and type are required")
+	}
+	for name := range e.Extensions {
+		if !extensionName.MatchString(name) {
+			return fmt.Errorf("invalid extension attribute name %q", name)
+		}
+	}
+	return nil
+}
+
+// Payload returns the event data as bytes. Text data of a non-JSON content
+// type is carried as a JSON string and returned unquoted.
+func (e *Event) Payload() []byte {
+	if e.DataBase64 != nil {
+		return e.DataBase64
+	}
+	if !isJSON(e.DataContentType) {
+		var text string
+		if json.Unmarshal(e.Data, &text) == nil {
+			return []byte(text)
+		}
+	}
+	return e.Data
+}
+
+// SetData sets the event data and its content type: JSON as is, other text as
+// a JSON string and binary data as data_base64.
+func (e *Event) SetData(contentType string, data []byte) {
+	e.DataContentType = contentType
+	e.Data = nil
+	e.DataBase64 = nil
+
+	switch {
+	case len(data) == 0:
+	case isJSON(contentType) && json.Valid(data):
+		e.Data = data
+	case utf8.Valid(data) && strings.HasPrefix(contentType, "text/"):
+		e.Data, _ = json.Marshal(string(data))
+	default:
+		e.DataBase64 = data
+	}
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{
+		"specversion": e.SpecVersion,
+		"id":          e.ID,
+		"source":      e.Source,
+		"type":        e.Type,
+	}
+	for name, value := range e.Extensions {
+		fields[name] = value
+	}
+	if e.Subject != "" {
+		fields["subject"] = e.Subject
+	}
+	if e.Time != nil {
+		fields["time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	if e.DataContentType != "" {
+		fields["datacontenttype"] = e.DataContentType
+	}
+	if e.DataSchema != "" {
+		fields["dataschema"] = e.DataSchema
+	}
+	if e.DataBase64 != nil {
+		fields["data_base64"] = e.DataBase64
+	} else if e.Data != nil {
+		fields["data"] = e.Data
+	}
+	return json.Marshal(fields)
+}
+
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	*e = Event{Extensions: map[string]string{}}
+	for name, raw := range fields {
+		switch name {
+		case "data":
+			e.Data = raw
+			continue
+		case "data_base64":
+			if err := json.Unmarshal(raw, &e.DataBase64); err != nil {
+				return fmt.Errorf("invalid data_base64: %v", err)
+			}
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		s, ok := value.(string)
+		if !ok {
+			// Extension attributes may be booleans or numbers
+			s = string(raw)
+		}
+		if err := e.setAttribute(name, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setAttribute sets a context attribute from its string form.
+func (e *Event) setAttribute(name, value string) error {
+	switch name {
+	case "specversion":
+		e.SpecVersion = value
+	case "id":
+		e.ID = value
+	case "source":
+		e.Source = value
+	case "type":
+		e.Type = value
+	case "subject":
+		e.Subject = value
+	case "time":
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("invalid time: %v", err)
+		}
+		e.Time = &t
+	case "datacontenttype":
+		e.DataContentType = value
+	case "dataschema":
+		e.DataSchema = value
+	default:
+		if e.Extensions == nil {
+			e.Extensions = map[string]string{}
+		}
+		e.Extensions[name] = value
+	}
+	return nil
+}
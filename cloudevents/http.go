@@ -0,0 +1,67 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const headerPrefix = "Ce-"
+
+// IsCloudEvent reports whether a request carries a CloudEvent, in structured
+// mode (an application/cloudevents+json body) or binary mode (ce-* headers).
+func IsCloudEvent(r *http.Request) bool {
+	return isStructured(r) || r.Header.Get(headerPrefix+"Specversion") != ""
+}
+
+func isStructured(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == ContentType
+}
+
+// FromRequest reads the CloudEvent carried by a request.
+func FromRequest(r *http.Request) (*Event, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var event Event
+	if isStructured(r) {
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("invalid structured event: %v", err)
+		}
+	} else {
+		if r.Header.Get(headerPrefix+"Specversion") == "" {
+			return nil, errors.New("request is not a CloudEvent")
+		}
+
+		for name, values := range r.Header {
+			if !strings.HasPrefix(name, headerPrefix) || len(values) == 0 {
+				continue
+			}
+			if err := event.setAttribute(strings.ToLower(strings.TrimPrefix(name, headerPrefix)), values[0]); err != nil {
+				return nil, err
+			}
+		}
+
+		event.SetData(r.Header.Get("Content-Type"), body)
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || mediaType == "text/json"
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"pub-sub-service/blob"
+	"pub-sub-service/client"
+	"pub-sub-service/envelope"
+	"pub-sub-service/idempotency"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+	"pub-sub-service/routes"
+	"pub-sub-service/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newDirectClient sets up the service's dependencies from the environment as
+// the server does, and returns a client whose requests are served by the
+// service's routes in process, so both modes behave the same. Logs go to
+// standard error, at warn level unless LOG_LEVEL says otherwise.
+func newDirectClient() (*client.Client, error) {
+	if os.Getenv("LOG_LEVEL") == "" {
+		os.Setenv("LOG_LEVEL", "warn")
+	}
+	if err := logging.SetupOutput(os.Stderr); err != nil {
+		return nil, err
+	}
+	if err := store.Setup(); err != nil {
+		return nil, err
+	}
+	if err := blob.Setup(); err != nil {
+		return nil, err
+	}
+	if err := payload.Setup(); err != nil {
+		return nil, err
+	}
+	if err := envelope.Setup(); err != nil {
+		return nil, err
+	}
+	if err := idempotency.Setup(); err != nil {
+		return nil, err
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.Use(logging.Middleware())
+	routes.RegisterRoutes(engine)
+
+	return client.New("http://pubsubctl",
+		client.WithHTTPClient(&http.Client{Transport: handlerTransport{engine}}),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+		client.WithUserAgent("pubsubctl"),
+	)
+}
+
+// handlerTransport round-trips requests through an http.Handler.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, request)
+	return recorder.Result(), nil
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"pub-sub-service/api"
+	"pub-sub-service/client"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errUsage = errors.New("usage")
+
+type cli struct {
+	client  *client.Client
+	printer *printer
+}
+
+var (
+	topicColumns        = []column{{"TOPIC ARN", "TopicArn"}}
+	subscriptionColumns = []column{{"SUBSCRIPTION ARN", "SubscriptionArn"}, {"PROTOCOL", "Protocol"}, {"ENDPOINT", "Endpoint"}}
+	queueColumns        = []column{{"QUEUE URL", ""}}
+	messageColumns      = []column{{"MESSAGE ID", "messageId"}, {"SENT AT", "sentAt"}, {"SUBJECT", "attributes.Subject"}, {"BODY", "body"}, {"RECEIPT HANDLE", "receiptHandle"}}
+)
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	command, args := args[0], args[1:]
+	switch command {
+	case "topics":
+		return c.topics(ctx, args)
+	case "subscriptions":
+		if len(args) != 2 || args[0] != "list" {
+			return errUsage
+		}
+		subscriptions, err := c.client.ListSubscriptions(ctx, args[1])
+		return c.print(subscriptions, err, subscriptionColumns)
+	case "subscribe":
+		return c.subscribe(ctx, args)
+	case "unsubscribe":
+		if len(args) != 2 {
+			return errUsage
+		}
+		return c.printDone(c.client.UnsubscribeFromTopic(ctx, args[0], args[1]))
+	case "publish":
+		return c.publish(ctx, args)
+	case "queues":
+		return c.queues(ctx, args)
+	case "send":
+		return c.send(ctx, args)
+	case "receive":
+		return c.receive(ctx, args)
+	case "tail":
+		return c.tail(ctx, args)
+	case "delete":
+		if len(args) != 2 {
+			return errUsage
+		}
+		return c.printDone(c.client.DeleteMessage(ctx, args[0], args[1]))
+	case "visibility":
+		if len(args) != 3 {
+			return errUsage
+		}
+		seconds, err := strconv.Atoi(args[2])
+		if err != nil {
+			return errUsage
+		}
+		return c.printDone(c.client.ChangeMessageVisibility(ctx, args[0], args[1], seconds))
+	}
+	return errUsage
+}
+
+func (c *cli) print(value any, err error, columns []column) error {
+	if err != nil {
+		return err
+	}
+	return c.printer.print(value, columns)
+}
+
+// printDone prints the outcome of a call with no result.
+func (c *cli) printDone(err error) error {
+	return c.print(true, err, nil)
+}
+
+func (c *cli) topics(ctx context.Context, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		topics, err := c.client.ListTopics(ctx)
+		return c.print(topics, err, topicColumns)
+	case len(args) == 2 && args[0] == "create":
+		topicARN, err := c.client.CreateTopic(ctx, api.CreateTopicInput{TopicName: args[1]})
+		return c.print(topicARN, err, nil)
+	}
+	return errUsage
+}
+
+func (c *cli) subscribe(ctx context.Context, args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "email":
+		subscriptionARN, err := c.client.SubscribeEmailToTopic(ctx, args[1], args[2])
+		return c.print(subscriptionARN, err, nil)
+	case "queue":
+		return c.printDone(c.client.SubscribeQueueToTopic(ctx, args[1], args[2]))
+	}
+	return errUsage
+}
+
+func (c *cli) queues(ctx context.Context, args []string) error {
+	if len(args) == 1 && args[0] == "list" {
+		queueURLs, err := c.client.ListQueues(ctx)
+		return c.print(queueURLs, err, queueColumns)
+	}
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "create":
+		return c.printDone(c.client.CreateQueue(ctx, api.CreateQueueInput{QueueName: args[1]}))
+	case "url":
+		queueURL, err := c.client.GetQueueURL(ctx, args[1])
+		return c.print(queueURL, err, nil)
+	case "delete":
+		return c.printDone(c.client.DeleteQueue(ctx, args[1]))
+	}
+	return errUsage
+}
+
+// messageFlags are the flags shared by publish and send.
+type messageFlags struct {
+	subject     *string
+	contentType *string
+	compression *string
+}
+
+func newMessageFlags(flags *flag.FlagSet) messageFlags {
+	return messageFlags{
+		subject:     flags.String("subject", "", "message subject (send only)"),
+		contentType: flags.String("content-type", "", "content type; non-text payloads are sent as binary data"),
+		compression: flags.String("compression", "", "none, gzip or zstd"),
+	}
+}
+
+// readPayload returns the message argument, or standard input for "-".
+func readPayload(argument string) ([]byte, error) {
+	if argument == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return []byte(argument), nil
+}
+
+// isText reports whether a payload with the content type is sent as a string
+// rather than base64 data.
+func isText(contentType string) bool {
+	return contentType == "" || contentType == "application/json" || strings.HasPrefix(contentType, "text/")
+}
+
+func (c *cli) publish(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("publish", flag.ContinueOnError)
+	messageFlags := newMessageFlags(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+
+	body, err := readPayload(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	input := api.PublishMessageInput{
+		ContentType: *messageFlags.contentType,
+		Compression: *messageFlags.compression,
+	}
+	if isText(input.ContentType) {
+		input.Message = string(body)
+	} else {
+		input.Data = body
+	}
+
+	messageID, err := c.client.Publish(ctx, flags.Arg(0), input)
+	return c.print(messageID, err, nil)
+}
+
+func (c *cli) send(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("send", flag.ContinueOnError)
+	messageFlags := newMessageFlags(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+
+	body, err := readPayload(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	input := api.SendMessageInput{
+		Subject:     *messageFlags.subject,
+		ContentType: *messageFlags.contentType,
+		Compression: *messageFlags.compression,
+	}
+	if isText(input.ContentType) {
+		input.Body = string(body)
+	} else {
+		input.Data = body
+	}
+
+	return c.printDone(c.client.SendMessage(ctx, flags.Arg(0), input))
+}
+
+func (c *cli) receive(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("receive", flag.ContinueOnError)
+	visibility := flags.Int("visibility", 30, "seconds the message stays hidden from other consumers")
+	decode := flags.Bool("decode", false, "decode schema-encoded payloads to JSON")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	message, err := c.client.ReceiveMessage(ctx, flags.Arg(0), api.ReceiveMessageInput{VisibilityTimeout: *visibility, Decode: *decode})
+	return c.print(message, err, messageColumns)
+}
+
+// tail prints messages as they arrive until interrupted, deleting each once
+// printed unless -keep is set.
+func (c *cli) tail(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("tail", flag.ContinueOnError)
+	visibility := flags.Int("visibility", 30, "seconds each message stays hidden from other consumers")
+	interval := flags.Duration("interval", time.Second, "how long to wait when the queue is empty")
+	keep := flags.Bool("keep", false, "leave messages on the queue")
+	decode := flags.Bool("decode", false, "decode schema-encoded payloads to JSON")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	queueName := flags.Arg(0)
+	options := client.ConsumerOptions{
+		VisibilityTimeout: *visibility,
+		Decode:            *decode,
+		PollInterval:      *interval,
+	}
+	for message, err := range c.client.Messages(ctx, queueName, options) {
+		if err != nil {
+			return err
+		}
+
+		if err := c.printer.printRow(message, messageColumns); err != nil {
+			return err
+		}
+
+		if !*keep {
+			if err := c.client.DeleteMessage(ctx, queueName, message.ReceiptHandle); err != nil && ctx.Err() == nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
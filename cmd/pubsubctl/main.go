@@ -0,0 +1,94 @@
+// Command pubsubctl manages topics, subscriptions and queues and publishes
+// and consumes messages, either through a running pub-sub-service or directly
+// against the backend.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"pub-sub-service/client"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: pubsubctl [flags] <command> [arguments]
+
+Commands:
+  topics list
+  topics create <name>
+  subscriptions list <topic-arn>
+  subscribe email <topic-arn> <email>
+  subscribe queue <topic-arn> <queue>
+  unsubscribe <topic-arn> <subscription-arn>
+  publish [-subject s] [-content-type t] [-compression c] <topic-arn> <message|->
+  queues list
+  queues create <name>
+  queues url <name>
+  queues delete <name>
+  send [-subject s] [-content-type t] [-compression c] <queue> <body|->
+  receive [-visibility seconds] [-decode] <queue>
+  tail [-visibility seconds] [-interval d] [-keep] [-decode] <queue>
+  delete <queue> <receipt-handle>
+  visibility <queue> <receipt-handle> <seconds>
+
+Flags:
+`
+
+func main() {
+	godotenv.Load()
+
+	flags := flag.NewFlagSet("pubsubctl", flag.ExitOnError)
+	addr := flags.String("addr", envOr("PUBSUB_ADDR", "http://localhost:8080"), "service URL (PUBSUB_ADDR)")
+	direct := flags.Bool("direct", false, "call the backend directly instead of a running service")
+	output := flags.String("o", "table", "output format: table or json")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "pubsubctl: unknown output format %q\n", *output)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var apiClient *client.Client
+	var err error
+	if *direct {
+		apiClient, err = newDirectClient()
+	} else {
+		apiClient, err = client.New(*addr, client.WithUserAgent("pubsubctl"))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pubsubctl: %v\n", err)
+		os.Exit(1)
+	}
+
+	cli := &cli{client: apiClient, printer: newPrinter(os.Stdout, *output == "json")}
+	if err := cli.run(ctx, flags.Args()); err != nil {
+		if err == errUsage {
+			flags.Usage()
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "pubsubctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
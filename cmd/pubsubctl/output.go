@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// column is a table column showing a field of each row, addressed by a
+// dotted path. An empty path shows the row itself.
+type column struct {
+	Header string
+	Path   string
+}
+
+// printer writes responses as JSON, or as a table when columns are given.
+// Responses without columns are printed as plain values.
+type printer struct {
+	out    io.Writer
+	asJSON bool
+}
+
+func newPrinter(out io.Writer, asJSON bool) *printer {
+	return &printer{out: out, asJSON: asJSON}
+}
+
+func (p *printer) print(result any, columns []column) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	if p.asJSON {
+		return p.printJSON(response)
+	}
+	if isNull(response) {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(response, &value); err != nil {
+		return err
+	}
+
+	if columns == nil {
+		return p.printValue(value)
+	}
+
+	rows, ok := value.([]any)
+	if !ok {
+		rows = []any{value}
+	}
+
+	writer := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = cell(lookup(row, column.Path))
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	return writer.Flush()
+}
+
+// printRow prints one row of a stream on its own line, as its columns can't
+// be aligned ahead of time.
+func (p *printer) printRow(result any, columns []column) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	if p.asJSON {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, response); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, compact.String())
+		return err
+	}
+
+	var value any
+	if err := json.Unmarshal(response, &value); err != nil {
+		return err
+	}
+
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = cell(lookup(value, column.Path))
+	}
+	_, err = fmt.Fprintln(p.out, strings.Join(cells, "  "))
+	return err
+}
+
+func (p *printer) printJSON(response []byte) error {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, response, "", "  "); err != nil {
+		return err
+	}
+	indented.WriteByte('\n')
+	_, err := p.out.Write(indented.Bytes())
+	return err
+}
+
+func (p *printer) printValue(value any) error {
+	switch value := value.(type) {
+	case string:
+		_, err := fmt.Fprintln(p.out, value)
+		return err
+	case bool:
+		if value {
+			_, err := fmt.Fprintln(p.out, "ok")
+			return err
+		}
+		_, err := fmt.Fprintln(p.out, "failed")
+		return err
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.out, string(data))
+	return err
+}
+
+func lookup(value any, path string) any {
+	if path == "" {
+		return value
+	}
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = fields[key]
+	}
+	return value
+}
+
+// cell formats a value for a table, on one line.
+func cell(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		return strings.Join(strings.Fields(value), " ")
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func isNull(response []byte) bool {
+	return string(response) == "null"
+}
@@ -0,0 +1,137 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	ErrNotConfigured = errors.New("encryption is not configured")
+	ErrUnknownKey    = errors.New("unknown master key")
+)
+
+// Keyring wraps per-message data keys with master keys.
+type Keyring interface {
+	// GenerateDataKey returns a new AES-256 data key, in plaintext and wrapped
+	// by the current master key, and the ID of that master key.
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, keyID string, err error)
+	// Decrypt unwraps a data key wrapped by the master key keyID.
+	Decrypt(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+var (
+	mu             sync.Mutex
+	defaultKeyring Keyring
+)
+
+// Setup builds the default keyring from ENCRYPTION_KEYRING: "kms", which
+// wraps data keys with the KMS key ENCRYPTION_KMS_KEY_ID, or "local", which
+// uses the keys in ENCRYPTION_LOCAL_KEYS. Encryption is unavailable if unset.
+func Setup() error {
+	var k Keyring
+	switch keyring := os.Getenv("ENCRYPTION_KEYRING"); keyring {
+	case "":
+	case "kms":
+		keyID := os.Getenv("ENCRYPTION_KMS_KEY_ID")
+		if keyID == "" {
+			return errors.New("ENCRYPTION_KMS_KEY_ID is required for the kms keyring")
+		}
+		k = NewKMS(keyID)
+	case "local":
+		var err error
+		k, err = ParseLocal(os.Getenv("ENCRYPTION_LOCAL_KEYS"))
+		if err != nil {
+			return fmt.Errorf("invalid ENCRYPTION_LOCAL_KEYS: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown ENCRYPTION_KEYRING %q", keyring)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultKeyring = k
+	return nil
+}
+
+// Default returns the keyring configured by Setup, or nil if encryption is
+// not configured.
+func Default() Keyring {
+	mu.Lock()
+	defer mu.Unlock()
+	return defaultKeyring
+}
+
+// Encrypt encrypts data with a new data key from keyring, returning the
+// ciphertext, the wrapped data key and the ID of the master key that wrapped
+// it.
+func Encrypt(ctx context.Context, keyring Keyring, data []byte) (ciphertext, wrapped []byte, keyID string, err error) {
+	if keyring == nil {
+		return nil, nil, "", ErrNotConfigured
+	}
+
+	dataKey, wrapped, keyID, err := keyring.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("unable to generate data key: %v", err)
+	}
+
+	ciphertext, err = seal(dataKey, data, []byte(keyID))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return ciphertext, wrapped, keyID, nil
+}
+
+// Decrypt reverses Encrypt.
+func Decrypt(ctx context.Context, keyring Keyring, ciphertext, wrapped []byte, keyID string) ([]byte, error) {
+	if keyring == nil {
+		return nil, ErrNotConfigured
+	}
+
+	dataKey, err := keyring.Decrypt(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt data key: %w", err)
+	}
+
+	return open(dataKey, ciphertext, []byte(keyID))
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
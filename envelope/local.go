@@ -0,0 +1,79 @@
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type localKeyring struct {
+	keys    map[string][]byte
+	current string
+}
+
+// NewLocal returns a Keyring wrapping data keys with local AES-256 master
+// keys, for offline use. New data keys are wrapped by the current key; the
+// others are kept to decrypt older messages after a rotation.
+func NewLocal(keys map[string][]byte, current string) (Keyring, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, current)
+	}
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes", id)
+		}
+	}
+
+	return &localKeyring{keys: keys, current: current}, nil
+}
+
+// ParseLocal builds a local keyring from comma-separated "id:base64key"
+// pairs. The first key is the current one.
+func ParseLocal(value string) (Keyring, error) {
+	keys := map[string][]byte{}
+	var current string
+
+	for _, pair := range strings.Split(value, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || id == "" {
+			return nil, errors.New(`keys must be "id:base64key" pairs`)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not base64: %v", id, err)
+		}
+		keys[id] = key
+
+		if current == "" {
+			current = id
+		}
+	}
+
+	return NewLocal(keys, current)
+}
+
+func (k *localKeyring) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, "", err
+	}
+
+	wrapped, err := seal(k.keys[k.current], dataKey, []byte(k.current))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return dataKey, wrapped, k.current, nil
+}
+
+func (k *localKeyring) Decrypt(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+
+	return open(key, wrapped, []byte(keyID))
+}
@@ -0,0 +1,240 @@
+package grpcserver
+
+import (
+	"context"
+	"pub-sub-service/models"
+	pubsubv1 "pub-sub-service/proto/pubsub/v1"
+	queue "pub-sub-service/sqs"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *server) ListQueues(ctx context.Context, req *pubsubv1.ListQueuesRequest) (*pubsubv1.ListQueuesResponse, error) {
+	res, err := models.ListQueues(ctx)
+	if err != nil {
+		return nil, statusError(err, "could not list queues")
+	}
+
+	return &pubsubv1.ListQueuesResponse{QueueUrls: res.Response.([]string)}, nil
+}
+
+func (s *server) CreateQueue(ctx context.Context, req *pubsubv1.CreateQueueRequest) (*pubsubv1.CreateQueueResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+
+	input := models.CreateQueueInput{QueueName: req.QueueName}
+	if attributes := req.Attributes; attributes != nil {
+		input.Attributes = queue.QueueAttributes{
+			QueueEncryption: queue.QueueEncryption{
+				KMSMasterKeyID:               attributes.KmsMasterKeyId,
+				KMSDataKeyReusePeriodSeconds: attributes.KmsDataKeyReusePeriodSeconds,
+				SQSManagedSSEEnabled:         attributes.SqsManagedSseEnabled,
+			},
+			DelaySeconds:                  attributes.DelaySeconds,
+			MaximumMessageSize:            attributes.MaximumMessageSize,
+			MessageRetentionPeriod:        attributes.MessageRetentionPeriod,
+			ReceiveMessageWaitTimeSeconds: attributes.ReceiveMessageWaitTimeSeconds,
+			VisibilityTimeout:             attributes.VisibilityTimeout,
+		}
+	}
+
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	_, err := models.CreateQueue(ctx, input)
+	if err != nil {
+		return nil, statusError(err, "could not create queue")
+	}
+
+	return &pubsubv1.CreateQueueResponse{}, nil
+}
+
+func (s *server) GetQueueUrl(ctx context.Context, req *pubsubv1.GetQueueUrlRequest) (*pubsubv1.GetQueueUrlResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+
+	res, err := models.GetQueueURL(ctx, req.QueueName)
+	if err != nil {
+		return nil, statusError(err, "could not get queue URL")
+	}
+
+	return &pubsubv1.GetQueueUrlResponse{QueueUrl: res.Response.(string)}, nil
+}
+
+func (s *server) DeleteQueue(ctx context.Context, req *pubsubv1.DeleteQueueRequest) (*pubsubv1.DeleteQueueResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+
+	_, err := models.DeleteQueue(ctx, req.QueueName)
+	if err != nil {
+		return nil, statusError(err, "could not delete queue")
+	}
+
+	return &pubsubv1.DeleteQueueResponse{}, nil
+}
+
+func (s *server) SendMessage(ctx context.Context, req *pubsubv1.SendMessageRequest) (*pubsubv1.SendMessageResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+
+	input := models.SendMessageInput{
+		Subject:          req.Subject,
+		Body:             req.GetBody(),
+		ContentType:      req.ContentType,
+		Attributes:       req.Attributes,
+		BinaryAttributes: req.BinaryAttributes,
+		Compression:      req.Compression,
+	}
+	if data, ok := req.Payload.(*pubsubv1.SendMessageRequest_Data); ok {
+		input.Data = data.Data
+	}
+
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	_, err := models.SendMessage(ctx, req.QueueName, input)
+	if err != nil {
+		return nil, statusError(err, "could not send message")
+	}
+
+	return &pubsubv1.SendMessageResponse{}, nil
+}
+
+func (s *server) ReceiveMessage(ctx context.Context, req *pubsubv1.ReceiveMessageRequest) (*pubsubv1.ReceiveMessageResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+
+	message, err := receive(ctx, req.QueueName, req.VisibilityTimeout, req.Decode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pubsubv1.ReceiveMessageResponse{Message: message}, nil
+}
+
+// StreamMessages polls the queue for as long as the client keeps the stream
+// open, sending each message as it arrives.
+func (s *server) StreamMessages(req *pubsubv1.StreamMessagesRequest, stream grpc.ServerStreamingServer[pubsubv1.Message]) error {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return err
+	}
+
+	pollInterval := time.Second
+	if req.PollIntervalMs > 0 {
+		pollInterval = time.Duration(req.PollIntervalMs) * time.Millisecond
+	}
+
+	ctx := stream.Context()
+	for {
+		message, err := receive(ctx, req.QueueName, req.VisibilityTimeout, req.Decode)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if message == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		if err := stream.Send(message); err != nil {
+			return err
+		}
+	}
+}
+
+func receive(ctx context.Context, queueName string, visibilityTimeout int32, decode bool) (*pubsubv1.Message, error) {
+	input := models.ReceiveMessageInput{
+		VisibilityTimeout: int(visibilityTimeout),
+		Decode:            decode,
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	res, err := models.ReceiveMessage(ctx, queueName, input)
+	if err != nil {
+		return nil, statusError(err, "could not receive message")
+	}
+	if res.Response == nil {
+		return nil, nil
+	}
+
+	received := res.Response.(models.ReceivedMessage)
+	message := &pubsubv1.Message{
+		MessageId:        received.MessageID,
+		ReceiptHandle:    received.ReceiptHandle,
+		TopicArn:         received.TopicARN,
+		ContentType:      received.ContentType,
+		Attributes:       received.Attributes,
+		BinaryAttributes: received.BinaryAttributes,
+		SentAt:           timestamppb.New(received.SentAt),
+		Decoded:          string(received.Decoded),
+	}
+	if received.Data != nil {
+		message.Payload = &pubsubv1.Message_Data{Data: received.Data}
+	} else {
+		message.Payload = &pubsubv1.Message_Body{Body: received.Body}
+	}
+	return message, nil
+}
+
+func (s *server) DeleteMessage(ctx context.Context, req *pubsubv1.DeleteMessageRequest) (*pubsubv1.DeleteMessageResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+	if err := required("receipt_handle", req.ReceiptHandle); err != nil {
+		return nil, err
+	}
+
+	input := models.DeleteMessageInput{ReceiptHandle: req.ReceiptHandle}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	_, err := models.DeleteMessage(ctx, req.QueueName, input)
+	if err != nil {
+		return nil, statusError(err, "could not delete message")
+	}
+
+	return &pubsubv1.DeleteMessageResponse{}, nil
+}
+
+func (s *server) ChangeMessageVisibility(ctx context.Context, req *pubsubv1.ChangeMessageVisibilityRequest) (*pubsubv1.ChangeMessageVisibilityResponse, error) {
+	if err := validateField("queue_name", req.QueueName, queueNameRule); err != nil {
+		return nil, err
+	}
+	if err := required("receipt_handle", req.ReceiptHandle); err != nil {
+		return nil, err
+	}
+
+	input := models.ChangeMessageVisibilityInput{
+		ReceiptHandle:     req.ReceiptHandle,
+		VisibilityTimeout: int(req.VisibilityTimeout),
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	_, err := models.ChangeMessageVisibility(ctx, req.QueueName, input)
+	if err != nil {
+		return nil, statusError(err, "could not change message visibility")
+	}
+
+	return &pubsubv1.ChangeMessageVisibilityResponse{}, nil
+}
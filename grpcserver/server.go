@@ -0,0 +1,128 @@
+// Package grpcserver serves the PubSub gRPC API on the same models service
+// layer as the REST routes.
+package grpcserver
+
+import (
+	"errors"
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+	"pub-sub-service/models"
+	"pub-sub-service/payload"
+	pubsubv1 "pub-sub-service/proto/pubsub/v1"
+	"pub-sub-service/schema"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+	"pub-sub-service/tracing"
+	"pub-sub-service/validation"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Rules checked on the request fields that stand in for the routes' path
+// parameters
+const (
+	topicARNRule  = "required," + validation.RuleTopicARN
+	queueNameRule = "required," + validation.RuleQueueName
+)
+
+type server struct {
+	pubsubv1.UnimplementedPubSubServer
+}
+
+// New returns a gRPC server with the PubSub service registered, traced,
+// logged and measured like the REST routes. Server reflection is enabled so
+// tools such as grpcurl can discover the API.
+func New(options ...grpc.ServerOption) *grpc.Server {
+	options = append(options,
+		grpc.ChainUnaryInterceptor(
+			tracing.UnaryServerInterceptor(),
+			logging.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			tracing.StreamServerInterceptor(),
+			logging.StreamServerInterceptor(),
+			metrics.StreamServerInterceptor(),
+		),
+	)
+
+	grpcServer := grpc.NewServer(options...)
+	pubsubv1.RegisterPubSubServer(grpcServer, &server{})
+	reflection.Register(grpcServer)
+	return grpcServer
+}
+
+// statusError maps a service error to a gRPC status, as the routes map them
+// to HTTP statuses; message describes the failed operation.
+func statusError(err error, message string) error {
+	var validationErr *schema.ValidationError
+	var awsErr awserr.Error
+	switch {
+	case errors.Is(err, payload.ErrUnknownCompression),
+		errors.Is(err, notification.ErrInvalidAttributes),
+		errors.Is(err, queue.ErrInvalidAttributes):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, queue.ErrQueueNotFound),
+		errors.Is(err, notification.ErrTopicNotFound):
+		return status.Error(codes.NotFound, message+": not found")
+	case errors.Is(err, models.ErrCloudEventRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &validationErr):
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	case errors.As(err, &awsErr):
+		switch awsErr.Code() {
+		case sqs.ErrCodeQueueDoesNotExist, sns.ErrCodeNotFoundException:
+			return status.Error(codes.NotFound, message+": not found")
+		case sqs.ErrCodeQueueNameExists:
+			return status.Error(codes.AlreadyExists, message+": already exists")
+		}
+	}
+	return status.Error(codes.Internal, message)
+}
+
+func required(field, value string) error {
+	if value == "" {
+		return status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	return nil
+}
+
+// validate checks an input built from a request against the binding rules
+// the routes apply to the same input in a request body.
+func validate(input any) error {
+	validation.Register()
+
+	err := binding.Validator.ValidateStruct(input)
+	if err == nil {
+		return nil
+	}
+	if fields := validation.Fields(err); fields != nil {
+		return fieldsError(fields)
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// validateField checks a request field against rule, as the routes check
+// the path parameter it stands in for.
+func validateField(field, value, rule string) error {
+	if fields := validation.Value(field, value, rule); fields != nil {
+		return fieldsError(fields)
+	}
+	return nil
+}
+
+func fieldsError(fields []validation.FieldError) error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return status.Error(codes.InvalidArgument, "invalid request: "+strings.Join(messages, "; "))
+}
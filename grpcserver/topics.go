@@ -0,0 +1,225 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"pub-sub-service/models"
+	pubsubv1 "pub-sub-service/proto/pubsub/v1"
+	notification "pub-sub-service/sns"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *server) ListTopics(ctx context.Context, req *pubsubv1.ListTopicsRequest) (*pubsubv1.ListTopicsResponse, error) {
+	res, err := models.ListTopics(ctx)
+	if err != nil {
+		return nil, statusError(err, "could not list topics")
+	}
+
+	topics := res.Response.([]*sns.Topic)
+	response := &pubsubv1.ListTopicsResponse{Topics: make([]*pubsubv1.Topic, len(topics))}
+	for i, topic := range topics {
+		response.Topics[i] = &pubsubv1.Topic{TopicArn: aws.StringValue(topic.TopicArn)}
+	}
+	return response, nil
+}
+
+func (s *server) CreateTopic(ctx context.Context, req *pubsubv1.CreateTopicRequest) (*pubsubv1.CreateTopicResponse, error) {
+	if err := required("topic_name", req.TopicName); err != nil {
+		return nil, err
+	}
+
+	input := models.CreateTopicInput{TopicName: req.TopicName}
+	if attributes := req.Attributes; attributes != nil {
+		input.Attributes = notification.TopicAttributes{
+			TopicEncryption:           notification.TopicEncryption{KMSMasterKeyID: attributes.KmsMasterKeyId},
+			DisplayName:               attributes.DisplayName,
+			FifoTopic:                 attributes.FifoTopic,
+			ContentBasedDeduplication: attributes.ContentBasedDeduplication,
+		}
+		if attributes.DeliveryPolicy != "" {
+			input.Attributes.DeliveryPolicy = json.RawMessage(attributes.DeliveryPolicy)
+		}
+		if attributes.Policy != "" {
+			input.Attributes.Policy = json.RawMessage(attributes.Policy)
+		}
+	}
+
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	res, err := models.CreateTopic(ctx, input)
+	if err != nil {
+		return nil, statusError(err, "could not create topic")
+	}
+
+	output := res.Response.(*sns.CreateTopicOutput)
+	return &pubsubv1.CreateTopicResponse{TopicArn: aws.StringValue(output.TopicArn)}, nil
+}
+
+func (s *server) ListSubscriptions(ctx context.Context, req *pubsubv1.ListSubscriptionsRequest) (*pubsubv1.ListSubscriptionsResponse, error) {
+	if err := validateField("topic_arn", req.TopicArn, topicARNRule); err != nil {
+		return nil, err
+	}
+
+	res, err := models.ListSubscriptions(ctx, req.TopicArn)
+	if err != nil {
+		return nil, statusError(err, "could not list subscriptions to topic")
+	}
+
+	subscriptions := res.Response.([]*sns.Subscription)
+	response := &pubsubv1.ListSubscriptionsResponse{Subscriptions: make([]*pubsubv1.Subscription, len(subscriptions))}
+	for i, subscription := range subscriptions {
+		response.Subscriptions[i] = &pubsubv1.Subscription{
+			SubscriptionArn: aws.StringValue(subscription.SubscriptionArn),
+			Protocol:        aws.StringValue(subscription.Protocol),
+			Endpoint:        aws.StringValue(subscription.Endpoint),
+			Owner:           aws.StringValue(subscription.Owner),
+			TopicArn:        aws.StringValue(subscription.TopicArn),
+		}
+	}
+	return response, nil
+}
+
+func (s *server) SubscribeEmail(ctx context.Context, req *pubsubv1.SubscribeEmailRequest) (*pubsubv1.SubscribeEmailResponse, error) {
+	if err := validateField("topic_arn", req.TopicArn, topicARNRule); err != nil {
+		return nil, err
+	}
+	if err := required("email", req.Email); err != nil {
+		return nil, err
+	}
+
+	input := models.SubscribeEmailToTopicInput{Email: req.Email}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	res, err := models.SubscribeEmailToTopic(ctx, req.TopicArn, input)
+	if err != nil {
+		return nil, statusError(err, "could not subscribe email to topic")
+	}
+
+	output := res.Response.(*sns.SubscribeOutput)
+	return &pubsubv1.SubscribeEmailResponse{SubscriptionArn: aws.StringValue(output.SubscriptionArn)}, nil
+}
+
+func (s *server) SubscribeQueue(ctx context.Context, req *pubsubv1.SubscribeQueueRequest) (*pubsubv1.SubscribeQueueResponse, error) {
+	if err := validateField("topic_arn", req.TopicArn, topicARNRule); err != nil {
+		return nil, err
+	}
+	if err := required("queue_name", req.QueueName); err != nil {
+		return nil, err
+	}
+
+	input := models.SubscribeQueueToTopicInput{QueueName: req.QueueName}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	_, err := models.SubscribeQueueToTopic(ctx, req.TopicArn, input)
+	if err != nil {
+		return nil, statusError(err, "could not subscribe queue to topic")
+	}
+
+	return &pubsubv1.SubscribeQueueResponse{}, nil
+}
+
+func (s *server) Unsubscribe(ctx context.Context, req *pubsubv1.UnsubscribeRequest) (*pubsubv1.UnsubscribeResponse, error) {
+	if err := validateField("topic_arn", req.TopicArn, topicARNRule); err != nil {
+		return nil, err
+	}
+	if err := required("subscription_arn", req.SubscriptionArn); err != nil {
+		return nil, err
+	}
+
+	input := models.UnsubscribeFromTopicInput{SubscriptionID: req.SubscriptionArn}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	_, err := models.UnsubscribeFromTopic(ctx, req.TopicArn, input)
+	if err != nil {
+		return nil, statusError(err, "could not unsubscribe from topic")
+	}
+
+	return &pubsubv1.UnsubscribeResponse{}, nil
+}
+
+func (s *server) Publish(ctx context.Context, req *pubsubv1.PublishRequest) (*pubsubv1.PublishResponse, error) {
+	messageID, err := publish(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pubsubv1.PublishResponse{MessageId: messageID}, nil
+}
+
+// maxPublishStreamMessages is how many messages one PublishStream call
+// publishes, which bounds the results held until the stream closes.
+const maxPublishStreamMessages = 1000
+
+// PublishStream publishes messages as they arrive; a message that fails is
+// reported in its result and does not end the stream. After
+// maxPublishStreamMessages messages it closes the stream with their results,
+// leaving any messages sent after them unpublished.
+func (s *server) PublishStream(stream grpc.ClientStreamingServer[pubsubv1.PublishRequest, pubsubv1.PublishStreamResponse]) error {
+	ctx := stream.Context()
+
+	response := &pubsubv1.PublishStreamResponse{}
+	for {
+		if len(response.Results) == maxPublishStreamMessages {
+			return stream.SendAndClose(response)
+		}
+
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+
+		messageID, err := publish(ctx, req)
+		if err != nil {
+			response.Results = append(response.Results, &pubsubv1.PublishResult{Error: status.Convert(err).Message()})
+			continue
+		}
+		response.Results = append(response.Results, &pubsubv1.PublishResult{MessageId: messageID})
+	}
+}
+
+func publish(ctx context.Context, req *pubsubv1.PublishRequest) (string, error) {
+	if err := validateField("topic_arn", req.TopicArn, topicARNRule); err != nil {
+		return "", err
+	}
+
+	input := models.PublishMessageInput{
+		ContentType: req.ContentType,
+		Compression: req.Compression,
+	}
+	switch payload := req.Payload.(type) {
+	case *pubsubv1.PublishRequest_Message:
+		input.Message = payload.Message
+	case *pubsubv1.PublishRequest_Data:
+		input.Data = payload.Data
+	default:
+		return "", status.Error(codes.InvalidArgument, "message or data is required")
+	}
+	if err := validate(input); err != nil {
+		return "", err
+	}
+
+	res, err := models.PublishMessageToAllTopicSubscribers(ctx, req.TopicArn, input)
+	if err != nil {
+		return "", statusError(err, "could not publish message")
+	}
+
+	output := res.Response.(*sns.PublishOutput)
+	return aws.StringValue(output.MessageId), nil
+}
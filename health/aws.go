@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pub-sub-service/awssession"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+func checkCredentials(ctx context.Context) error {
+	svc := sts.New(awssession.New())
+
+	_, err := svc.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	return err
+}
+
+func checkSNS(ctx context.Context) error {
+	svc := sns.New(awssession.New())
+
+	_, err := svc.ListTopicsWithContext(ctx, &sns.ListTopicsInput{})
+	return err
+}
+
+func checkSQS(ctx context.Context) error {
+	svc := sqs.New(awssession.New())
+
+	_, err := svc.ListQueuesWithContext(ctx, &sqs.ListQueuesInput{
+		MaxResults: aws.Int64(1),
+	})
+	return err
+}
+
+func checkTopics(ctx context.Context, topicNames []string) error {
+	svc := sns.New(awssession.New())
+
+	missing := map[string]bool{}
+	for _, name := range topicNames {
+		missing[name] = true
+	}
+
+	err := svc.ListTopicsPagesWithContext(ctx, &sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+		for _, topic := range page.Topics {
+			arn := aws.StringValue(topic.TopicArn)
+			delete(missing, arn[strings.LastIndex(arn, ":")+1:])
+		}
+		return len(missing) > 0
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing topics: %s", strings.Join(keys(missing), ", "))
+	}
+	return nil
+}
+
+func checkQueues(ctx context.Context, queueNames []string) error {
+	svc := sqs.New(awssession.New())
+
+	var missing []string
+	for _, name := range queueNames {
+		_, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+			QueueName: aws.String(name),
+		})
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == sqs.ErrCodeQueueDoesNotExist {
+			missing = append(missing, name)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing queues: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func keys(set map[string]bool) []string {
+	var list []string
+	for key := range set {
+		list = append(list, key)
+	}
+	return list
+}
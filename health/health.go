@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"os"
+	"pub-sub-service/api"
+	"pub-sub-service/blob"
+	"pub-sub-service/envelope"
+	"pub-sub-service/outbox"
+	"pub-sub-service/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check verifies a single dependency.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// CheckResult and Report are the readiness of a dependency and of the
+// service, shared with the client.
+type (
+	CheckResult = api.CheckResult
+	Report      = api.HealthReport
+)
+
+var (
+	once   sync.Once
+	checks []Check
+)
+
+// Readiness runs every dependency check concurrently, each bounded by
+// READINESS_TIMEOUT (default 2s). The report is ok only if all checks pass.
+func Readiness(ctx context.Context) Report {
+	once.Do(func() {
+		checks = DefaultChecks()
+	})
+
+	timeout := 2 * time.Second
+	if value, err := time.ParseDuration(os.Getenv("READINESS_TIMEOUT")); err == nil && value > 0 {
+		timeout = value
+	}
+
+	return Run(ctx, checks, timeout)
+}
+
+// Run runs checks concurrently and collects their results.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(checkCtx)
+			result := CheckResult{
+				Status:    StatusOK,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if err != nil {
+				report.Status = StatusUnavailable
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// pinger is a configured dependency that can verify it is reachable.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DefaultChecks verifies SNS and SQS reachability and credential validity,
+// plus the existence of the topics and queues named in the comma-separated
+// REQUIRED_TOPICS and REQUIRED_QUEUES variables. The outbox database, the
+// DynamoDB store, the S3 blob store and the KMS key are checked when they are
+// configured, so it should be called after their Setup.
+func DefaultChecks() []Check {
+	checks := []Check{
+		{Name: "credentials", Check: checkCredentials},
+		{Name: "sns", Check: checkSNS},
+		{Name: "sqs", Check: checkSQS},
+	}
+
+	if relay := outbox.Default(); relay != nil {
+		checks = append(checks, Check{Name: "outbox", Check: relay.Ping})
+	}
+	if s, ok := store.Default().(pinger); ok {
+		checks = append(checks, Check{Name: "store", Check: s.Ping})
+	}
+	if s, ok := blob.Default().(pinger); ok {
+		checks = append(checks, Check{Name: "blob", Check: s.Ping})
+	}
+	if k, ok := envelope.Default().(pinger); ok {
+		checks = append(checks, Check{Name: "kms", Check: k.Ping})
+	}
+
+	if topics := splitList(os.Getenv("REQUIRED_TOPICS")); len(topics) > 0 {
+		checks = append(checks, Check{Name: "topics", Check: func(ctx context.Context) error {
+			return checkTopics(ctx, topics)
+		}})
+	}
+	if queues := splitList(os.Getenv("REQUIRED_QUEUES")); len(queues) > 0 {
+		checks = append(checks, Check{Name: "queues", Check: func(ctx context.Context) error {
+			return checkQueues(ctx, queues)
+		}})
+	}
+
+	return checks
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
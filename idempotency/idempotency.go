@@ -0,0 +1,186 @@
+// Package idempotency remembers the results of requests made with a
+// client-supplied idempotency key, so that a retried request returns the
+// original result instead of being carried out again. Results are kept in
+// the store for a configurable window.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"pub-sub-service/store"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInProgress = errors.New("a request with this idempotency key is in progress")
+	ErrMismatch   = errors.New("idempotency key was used for a different request")
+)
+
+const (
+	keyPrefix = "idempotency/"
+
+	// MaxKeyLength bounds the keys clients may send
+	MaxKeyLength = 255
+
+	// inProgressTTL bounds how long a request that never completes, such as
+	// one on an instance that crashed, blocks retries with its key
+	inProgressTTL = time.Minute
+)
+
+// Result is the outcome of a request, kept under its idempotency key.
+// Fingerprint identifies the request, so a key reused for a different
+// request is detected.
+type Result struct {
+	Fingerprint string          `json:"fingerprint"`
+	Done        bool            `json:"done"`
+	StatusCode  int             `json:"statusCode,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// Keys tracks idempotency keys and their results.
+type Keys struct {
+	store  store.Store
+	window time.Duration
+}
+
+func New(s store.Store, window time.Duration) *Keys {
+	return &Keys{store: s, window: window}
+}
+
+var (
+	mu            sync.Mutex
+	defaultWindow = 24 * time.Hour
+	defaultKeys   *Keys
+)
+
+// Setup reads IDEMPOTENCY_WINDOW, how long results are kept (default 24h).
+func Setup() error {
+	window := 24 * time.Hour
+	if value := os.Getenv("IDEMPOTENCY_WINDOW"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid IDEMPOTENCY_WINDOW %q", value)
+		}
+		window = parsed
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultWindow = window
+	defaultKeys = nil
+	return nil
+}
+
+// Default returns the keys kept in the default store for the configured
+// window.
+func Default() *Keys {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if defaultKeys == nil {
+		defaultKeys = New(store.Default(), defaultWindow)
+	}
+	return defaultKeys
+}
+
+// ValidKey reports whether key can be used as an idempotency key: 1 to
+// MaxKeyLength printable ASCII characters.
+func ValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Begin claims key within scope, such as a topic or queue, for a request. It
+// returns nil when the request should be carried out, followed by Complete
+// or Abandon, or the result of an earlier request with the same key to
+// return instead. A request still in progress is ErrInProgress, and a
+// different request under the same key is ErrMismatch.
+func (k *Keys) Begin(ctx context.Context, scope, key, fingerprint string) (*Result, error) {
+	claim, err := json.Marshal(Result{Fingerprint: fingerprint, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+
+	// The earlier result may expire between the two calls, so try twice
+	for range 2 {
+		claimed, err := k.store.PutIfAbsent(ctx, storeKey(scope, key), claim, inProgressTTL)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		value, err := k.store.Get(ctx, storeKey(scope, key))
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var result Result
+		if err := json.Unmarshal(value, &result); err != nil {
+			return nil, err
+		}
+		if result.Fingerprint != fingerprint {
+			return nil, ErrMismatch
+		}
+		if !result.Done {
+			return nil, ErrInProgress
+		}
+		return &result, nil
+	}
+	return nil, ErrInProgress
+}
+
+// Complete stores the result of a request claimed with Begin for the window.
+// Its fingerprint must be the one the key was claimed with.
+func (k *Keys) Complete(ctx context.Context, scope, key string, result Result) error {
+	result.Done = true
+	if result.CreatedAt.IsZero() {
+		result.CreatedAt = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return k.store.Put(ctx, storeKey(scope, key), data, k.window)
+}
+
+// Abandon releases a key claimed with Begin, so that the request can be
+// retried, e.g. after it failed.
+func (k *Keys) Abandon(ctx context.Context, scope, key string) error {
+	return k.store.Delete(ctx, storeKey(scope, key))
+}
+
+// Fingerprint hashes the parts that identify a request.
+func Fingerprint(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(fmt.Sprintf("%d:", len(part))))
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// storeKey hashes the scope and key, which may hold any printable character
+func storeKey(scope, key string) string {
+	hash := sha256.Sum256([]byte(scope + "\n" + key))
+	return keyPrefix + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type contextKey struct{}
+
+// Setup builds the default logger from LOG_LEVEL (debug, info, warn, error)
+// and LOG_FORMAT (json or text), and installs it as the slog and log default.
+func Setup() error {
+	return SetupOutput(os.Stdout)
+}
+
+// SetupOutput is Setup, writing logs to w.
+func SetupOutput(w io.Writer) error {
+	var level slog.Level
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL %q: %v", value, err)
+		}
+	}
+
+	redact = !strings.EqualFold(os.Getenv("LOG_REDACT"), "false")
+
+	handler, err := newHandler(w, os.Getenv("LOG_FORMAT"), &slog.HandlerOptions{Level: level})
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+func newHandler(w io.Writer, format string, opts *slog.HandlerOptions) (slog.Handler, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return slog.NewJSONHandler(w, opts), nil
+	case "text":
+		return slog.NewTextHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q", format)
+	}
+}
+
+// WithLogger returns ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger,
+// annotated with the trace and span IDs of the current span.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := baseLogger(ctx)
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	return logger
+}
+
+func baseLogger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// Middleware assigns every request an ID, taken from the X-Request-ID header
+// when the caller supplies one, echoes it in the response, attaches a logger
+// carrying it to the request context and logs the request once served.
+func Middleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+
+		requestID := context.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+		context.Header(RequestIDHeader, requestID)
+
+		ctx := withRequestID(context.Request.Context(), requestID)
+		ctx = WithLogger(ctx, baseLogger(ctx).With(slog.String("request_id", requestID)))
+		context.Request = context.Request.WithContext(ctx)
+
+		context.Next()
+
+		level := slog.LevelInfo
+		if context.Writer.Status() >= 500 {
+			level = slog.LevelError
+		}
+		FromContext(ctx).Log(ctx, level, "request served",
+			slog.String("method", context.Request.Method),
+			slog.String("route", context.FullPath()),
+			slog.Int("status", context.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", context.ClientIP()),
+		)
+	}
+}
+
+// RequestID returns the ID of the request ctx belongs to, if any.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func withRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
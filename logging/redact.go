@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// redact is disabled with LOG_REDACT=false for local debugging.
+var redact = true
+
+// Sensitive is a value, such as a receipt handle or message body, that is
+// replaced by its length when logged.
+type Sensitive string
+
+func (s Sensitive) LogValue() slog.Value {
+	if !redact {
+		return slog.StringValue(string(s))
+	}
+	return slog.StringValue(fmt.Sprintf("[redacted %d bytes]", len(s)))
+}
+
+// Email is an email address that is logged with its local part masked.
+type Email string
+
+func (e Email) LogValue() slog.Value {
+	if !redact {
+		return slog.StringValue(string(e))
+	}
+
+	at := strings.LastIndex(string(e), "@")
+	if at < 1 {
+		return Sensitive(e).LogValue()
+	}
+	return slog.StringValue(string(e)[:1] + "***" + string(e)[at:])
+}
@@ -1,11 +1,36 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"pub-sub-service/blob"
+	"pub-sub-service/envelope"
+	"pub-sub-service/grpcserver"
+	"pub-sub-service/idempotency"
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+	"pub-sub-service/models"
+	"pub-sub-service/outbox"
+	_ "pub-sub-service/outbox/drivers"
+	"pub-sub-service/payload"
 	"pub-sub-service/routes"
+	"pub-sub-service/rpc"
+	"pub-sub-service/scheduler"
+	"pub-sub-service/server"
+	queue "pub-sub-service/sqs"
+	"pub-sub-service/store"
+	"pub-sub-service/tracing"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
@@ -100,9 +125,183 @@ func main() {
 
 	godotenv.Load()
 
-	server := gin.Default()
+	if err := logging.Setup(); err != nil {
+		log.Fatal(err)
+	}
 
-	routes.RegisterRoutes(server)
+	if isManifestCommand(os.Args) {
+		if err := runManifest(os.Args[1], os.Args[2:]); err != nil {
+			slog.Error("manifest command failed", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
 
-	server.Run(os.Getenv("PORT"))
-}
\ No newline at end of file
+	if err := run(); err != nil {
+		slog.Error("server stopped", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverConfig, err := server.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Setup(); err != nil {
+		return err
+	}
+
+	if err := blob.Setup(); err != nil {
+		return err
+	}
+
+	if err := payload.Setup(); err != nil {
+		return err
+	}
+
+	if err := envelope.Setup(); err != nil {
+		return err
+	}
+
+	if err := idempotency.Setup(); err != nil {
+		return err
+	}
+
+	if err := outbox.Setup(); err != nil {
+		return err
+	}
+
+	pollInterval := 30 * time.Second
+	if value := os.Getenv("QUEUE_DEPTH_POLL_INTERVAL"); value != "" {
+		pollInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid QUEUE_DEPTH_POLL_INTERVAL %q: %v", value, err)
+		}
+	}
+
+	schedulerInterval := time.Second
+	if value := os.Getenv("SCHEDULER_POLL_INTERVAL"); value != "" {
+		schedulerInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid SCHEDULER_POLL_INTERVAL %q: %v", value, err)
+		}
+	}
+
+	outboxInterval := time.Second
+	if value := os.Getenv("OUTBOX_POLL_INTERVAL"); value != "" {
+		outboxInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid OUTBOX_POLL_INTERVAL %q: %v", value, err)
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownTracing(flushCtx)
+	}()
+
+	// Background workers are stopped once the server has drained, before
+	// traces are flushed.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	defer func() {
+		stopWorkers()
+		workers.Wait()
+	}()
+
+	if pollInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			queue.PollQueueDepths(workerCtx, pollInterval)
+		}()
+	}
+
+	if schedulerInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			scheduler.Default().Run(workerCtx, schedulerInterval, models.DispatchScheduled)
+		}()
+	}
+
+	// Blobs that consumers did not delete, such as those of published
+	// messages, are expired once an hour
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		blob.Expire(workerCtx, time.Hour)
+	}()
+
+	if relay := outbox.Default(); relay != nil && outboxInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			defer relay.Close()
+			relay.Run(workerCtx, outboxInterval, models.DispatchOutbox)
+		}()
+	}
+
+	// Reply queues left behind by instances that did not shut down cleanly
+	// are swept every ten minutes
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		rpc.Default().SweepReplyQueues(workerCtx, 10*time.Minute)
+	}()
+
+	// The reply queue of request/reply calls is deleted once the server has
+	// drained, if a request created it
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := rpc.Default().Close(closeCtx); err != nil {
+			slog.Warn("unable to delete reply queue", slog.Any("error", err))
+		}
+	}()
+
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	engine.Use(otelgin.Middleware(tracing.ServiceName))
+	engine.Use(logging.Middleware())
+	engine.Use(metrics.Middleware())
+	engine.Use(server.BodyLimit(serverConfig.MaxBodyBytes))
+
+	routes.RegisterRoutes(engine)
+
+	if serverConfig.GRPCAddr == "" {
+		return server.Run(ctx, serverConfig, engine)
+	}
+
+	grpcOptions, err := server.GRPCCredentials(serverConfig)
+	if err != nil {
+		return err
+	}
+
+	// Either server failing stops the other
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	grpcErr := make(chan error, 1)
+	go func() {
+		defer cancel()
+		grpcErr <- server.RunGRPC(ctx, serverConfig, grpcserver.New(grpcOptions...))
+	}()
+
+	err = server.Run(ctx, serverConfig, engine)
+	cancel()
+	if grpcServerErr := <-grpcErr; err == nil {
+		err = grpcServerErr
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"pub-sub-service/manifest"
+	"syscall"
+)
+
+// runManifest runs the plan and apply subcommands, printing the plan as JSON.
+// Plan exits with status 2 when the manifest has drifted, so it can gate CI.
+func runManifest(command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	file := flags.String("f", "manifest.yaml", "manifest file, YAML or JSON")
+	prune := flags.Bool("prune", false, "delete managed resources the manifest no longer declares")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	m, err := manifest.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	options := manifest.Options{Prune: *prune}
+
+	var plan *manifest.Plan
+	var applyErr error
+	if command == "apply" {
+		plan, applyErr = manifest.Apply(ctx, m, options)
+		var changeErr *manifest.ApplyError
+		if applyErr != nil && !errors.As(applyErr, &changeErr) {
+			return applyErr
+		}
+	} else {
+		plan, err = manifest.BuildPlan(ctx, m, options)
+		if err != nil {
+			return err
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		return err
+	}
+
+	if applyErr != nil {
+		return applyErr
+	}
+	if command == "plan" && !plan.InSync {
+		os.Exit(2)
+	}
+	return nil
+}
+
+func isManifestCommand(args []string) bool {
+	return len(args) > 1 && (args[1] == "plan" || args[1] == "apply")
+}
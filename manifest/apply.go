@@ -0,0 +1,47 @@
+package manifest
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/logging"
+)
+
+// Apply converges the actual state to a manifest and returns the plan it
+// applied. It stops at the first change that fails, since later changes may
+// depend on it; the failed change carries the error.
+func Apply(ctx context.Context, m *Manifest, options Options) (*Plan, error) {
+	s, plan, err := buildPlan(ctx, m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logging.FromContext(ctx)
+	for _, change := range plan.Changes {
+		if err := s.steps[change](ctx, s); err != nil {
+			change.Error = err.Error()
+			logger.Error("could not apply manifest change",
+				slog.String("action", string(change.Action)), slog.String("kind", change.Kind), slog.String("name", change.Name), slog.Any("error", err))
+			return plan, &ApplyError{Change: change, Err: err}
+		}
+
+		change.Applied = true
+		logger.Info("applied manifest change",
+			slog.String("action", string(change.Action)), slog.String("kind", change.Kind), slog.String("name", change.Name))
+	}
+
+	return plan, nil
+}
+
+// ApplyError reports the change Apply stopped at.
+type ApplyError struct {
+	Change *Change
+	Err    error
+}
+
+func (e *ApplyError) Error() string {
+	return "could not " + string(e.Change.Action) + " " + e.Change.Kind + " " + e.Change.Name + ": " + e.Err.Error()
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+
+	"github.com/goccy/go-yaml"
+)
+
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// Manifest describes the topics, queues and subscriptions that should exist.
+// Subscriptions and dead-letter queues refer to topics and queues by name and
+// must be declared in the same manifest.
+type Manifest struct {
+	Topics        []Topic        `json:"topics"`
+	Queues        []Queue        `json:"queues"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+type Topic struct {
+	Name       string                       `json:"name"`
+	Attributes notification.TopicAttributes `json:"attributes"`
+	// Tags replace the topic's tags when set; nil leaves them unmanaged.
+	Tags map[string]string `json:"tags"`
+}
+
+type Queue struct {
+	Name string `json:"name"`
+	// Attributes may not set RedrivePolicy; use DeadLetterQueue instead.
+	Attributes      queue.QueueAttributes `json:"attributes"`
+	DeadLetterQueue *DeadLetterQueue      `json:"deadLetterQueue"`
+	// Tags replace the queue's tags when set; nil leaves them unmanaged.
+	Tags map[string]string `json:"tags"`
+}
+
+// DeadLetterQueue moves messages to the named queue after they have been
+// received MaxReceiveCount times.
+type DeadLetterQueue struct {
+	Queue           string `json:"queue"`
+	MaxReceiveCount int64  `json:"maxReceiveCount"`
+}
+
+// Subscription subscribes either a queue or an email address to a topic.
+type Subscription struct {
+	Topic              string          `json:"topic"`
+	Queue              string          `json:"queue,omitempty"`
+	Email              string          `json:"email,omitempty"`
+	FilterPolicy       json.RawMessage `json:"filterPolicy,omitempty"`
+	FilterPolicyScope  string          `json:"filterPolicyScope,omitempty"`
+	RawMessageDelivery bool            `json:"rawMessageDelivery,omitempty"`
+	// DeadLetterQueue names a queue for messages SNS could not deliver.
+	DeadLetterQueue string `json:"deadLetterQueue,omitempty"`
+}
+
+// Parse reads a manifest in YAML or JSON, rejecting unknown fields.
+func Parse(data []byte) (*Manifest, error) {
+	document, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.DisallowUnknownFields()
+
+	var m Manifest
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that names are unique and references resolve.
+func (m *Manifest) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidManifest, fmt.Sprintf(format, args...))
+	}
+
+	topics := map[string]bool{}
+	for _, topic := range m.Topics {
+		if topic.Name == "" {
+			return invalid("topics need a name")
+		}
+		if topics[topic.Name] {
+			return invalid("topic %q is declared twice", topic.Name)
+		}
+		topics[topic.Name] = true
+	}
+
+	queues := map[string]bool{}
+	for _, q := range m.Queues {
+		if q.Name == "" {
+			return invalid("queues need a name")
+		}
+		if queues[q.Name] {
+			return invalid("queue %q is declared twice", q.Name)
+		}
+		if q.Attributes.RedrivePolicy != nil {
+			return invalid("queue %q: use deadLetterQueue instead of a redrive policy", q.Name)
+		}
+		if err := queue.ValidateQueueAttributes(q.Attributes); err != nil {
+			return invalid("queue %q: %v", q.Name, err)
+		}
+		queues[q.Name] = true
+	}
+	for _, q := range m.Queues {
+		if dlq := q.DeadLetterQueue; dlq != nil {
+			if !queues[dlq.Queue] || dlq.Queue == q.Name {
+				return invalid("queue %q: dead-letter queue %q is not declared", q.Name, dlq.Queue)
+			}
+			if dlq.MaxReceiveCount < 1 || dlq.MaxReceiveCount > 1000 {
+				return invalid("queue %q: maxReceiveCount must be between 1 and 1000", q.Name)
+			}
+		}
+	}
+	if _, err := m.queueOrder(); err != nil {
+		return err
+	}
+
+	subscriptions := map[string]bool{}
+	for _, subscription := range m.Subscriptions {
+		if !topics[subscription.Topic] {
+			return invalid("subscription to undeclared topic %q", subscription.Topic)
+		}
+		if (subscription.Queue == "") == (subscription.Email == "") {
+			return invalid("subscription to %q needs either a queue or an email", subscription.Topic)
+		}
+		if subscription.Queue != "" && !queues[subscription.Queue] {
+			return invalid("subscription to %q: queue %q is not declared", subscription.Topic, subscription.Queue)
+		}
+		if subscription.DeadLetterQueue != "" && !queues[subscription.DeadLetterQueue] {
+			return invalid("subscription to %q: dead-letter queue %q is not declared", subscription.Topic, subscription.DeadLetterQueue)
+		}
+		if len(subscription.FilterPolicy) > 0 && !json.Valid(subscription.FilterPolicy) {
+			return invalid("subscription to %q: filterPolicy must be a JSON document", subscription.Topic)
+		}
+		if scope := subscription.FilterPolicyScope; scope != "" && scope != "MessageAttributes" && scope != "MessageBody" {
+			return invalid("subscription to %q: filterPolicyScope must be MessageAttributes or MessageBody", subscription.Topic)
+		}
+
+		key := subscription.key()
+		if subscriptions[key] {
+			return invalid("subscription %s is declared twice", key)
+		}
+		subscriptions[key] = true
+	}
+
+	return nil
+}
+
+// queueOrder returns the queues with every dead-letter queue before the
+// queues that use it.
+func (m *Manifest) queueOrder() ([]Queue, error) {
+	var ordered []Queue
+	placed := map[string]bool{}
+
+	for len(ordered) < len(m.Queues) {
+		progress := false
+		for _, q := range m.Queues {
+			if placed[q.Name] || (q.DeadLetterQueue != nil && !placed[q.DeadLetterQueue.Queue]) {
+				continue
+			}
+			ordered = append(ordered, q)
+			placed[q.Name] = true
+			progress = true
+		}
+		if !progress {
+			return nil, fmt.Errorf("%w: dead-letter queues form a cycle", ErrInvalidManifest)
+		}
+	}
+
+	return ordered, nil
+}
+
+func (s Subscription) key() string {
+	if s.Queue != "" {
+		return s.Topic + " -> queue " + s.Queue
+	}
+	return s.Topic + " -> email " + s.Email
+}
@@ -0,0 +1,551 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pub-sub-service/api"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+type Action = api.Action
+
+const (
+	ActionCreate = api.ActionCreate
+	ActionUpdate = api.ActionUpdate
+	ActionDelete = api.ActionDelete
+)
+
+const (
+	KindTopic        = "topic"
+	KindQueue        = "queue"
+	KindSubscription = "subscription"
+)
+
+// Diff, Change and Plan describe how a manifest converges the actual state,
+// and are shared with the client. The state a plan was built from holds the
+// step that applies each change.
+type (
+	Diff   = api.Diff
+	Change = api.Change
+	Plan   = api.Plan
+)
+
+type Options struct {
+	// Prune deletes orphaned resources and undeclared subscriptions to
+	// declared topics.
+	Prune bool
+}
+
+// BuildPlan compares a manifest with the actual state. With no changes and
+// nothing orphaned, the plan is in sync; otherwise it is a drift report.
+func BuildPlan(ctx context.Context, m *Manifest, options Options) (*Plan, error) {
+	_, plan, err := buildPlan(ctx, m, options)
+	return plan, err
+}
+
+func buildPlan(ctx context.Context, m *Manifest, options Options) (*state, *Plan, error) {
+	s, err := loadState(ctx, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan, err := planState(ctx, m, s, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, plan, nil
+}
+
+// planState compares a manifest with a loaded state, recording in s the
+// step that applies each change.
+func planState(ctx context.Context, m *Manifest, s *state, options Options) (*Plan, error) {
+	plan := &Plan{Changes: []*Change{}}
+	add := func(change *Change) {
+		if change != nil {
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+
+	queues, err := m.queueOrder()
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range queues {
+		add(planQueue(s, q))
+	}
+	for _, topic := range m.Topics {
+		add(planTopic(s, topic))
+	}
+
+	declared := map[string]bool{}
+	for _, subscription := range m.Subscriptions {
+		change, matched := planSubscription(ctx, s, subscription)
+		add(change)
+		if matched != "" {
+			declared[matched] = true
+		}
+	}
+
+	var deletions []*Change
+	for _, topic := range m.Topics {
+		for _, subscription := range s.subscriptions[topic.Name] {
+			subscriptionARN := aws.StringValue(subscription.SubscriptionArn)
+			if declared[subscriptionARN] || !strings.HasPrefix(subscriptionARN, "arn:") {
+				continue
+			}
+			name := fmt.Sprintf("%s -> %s %s", topic.Name, aws.StringValue(subscription.Protocol), aws.StringValue(subscription.Endpoint))
+			topicARN := s.topicARNs[topic.Name]
+			deletions = append(deletions, s.step(&Change{
+				Action: ActionDelete,
+				Kind:   KindSubscription,
+				Name:   name,
+			}, func(ctx context.Context, s *state) error {
+				_, err := notification.UnsubscribeFromTopic(ctx, &subscriptionARN, &topicARN)
+				return err
+			}))
+		}
+	}
+
+	sort.Strings(s.orphanedTopics)
+	for _, name := range s.orphanedTopics {
+		topicARN := s.topicARNs[name]
+		deletions = append(deletions, s.step(&Change{
+			Action: ActionDelete,
+			Kind:   KindTopic,
+			Name:   name,
+		}, func(ctx context.Context, s *state) error {
+			_, err := notification.DeleteTopic(ctx, topicARN)
+			return err
+		}))
+	}
+
+	sort.Strings(s.orphanedQueues)
+	for _, name := range s.orphanedQueues {
+		deletions = append(deletions, s.step(&Change{
+			Action: ActionDelete,
+			Kind:   KindQueue,
+			Name:   name,
+		}, func(ctx context.Context, s *state) error {
+			_, err := queue.DeleteQueue(ctx, name)
+			return err
+		}))
+	}
+
+	for _, deletion := range deletions {
+		if options.Prune {
+			plan.Changes = append(plan.Changes, deletion)
+		} else {
+			plan.Orphaned = append(plan.Orphaned, deletion.Kind+" "+deletion.Name)
+		}
+	}
+
+	plan.InSync = len(plan.Changes) == 0 && len(plan.Orphaned) == 0
+	return plan, nil
+}
+
+func planQueue(s *state, q Queue) *Change {
+	desiredTags := withManagedTag(q.Tags)
+
+	current, exists := s.queues[q.Name]
+	if !exists {
+		return s.step(&Change{
+			Action: ActionCreate,
+			Kind:   KindQueue,
+			Name:   q.Name,
+		}, func(ctx context.Context, s *state) error {
+			attributes := q.Attributes
+			if q.DeadLetterQueue != nil {
+				redrivePolicy, err := redrivePolicy(ctx, s, q.DeadLetterQueue)
+				if err != nil {
+					return err
+				}
+				attributes.RedrivePolicy = redrivePolicy
+			}
+
+			if _, err := queue.CreateQueue(ctx, q.Name, attributes); err != nil {
+				return err
+			}
+			_, err := queue.TagQueue(ctx, q.Name, desiredTags)
+			return err
+		})
+	}
+
+	var diffs []Diff
+	var update queue.QueueAttributes
+
+	compareString := func(field string, desired, current *string, set **string) {
+		if desired != nil && aws.StringValue(desired) != aws.StringValue(current) {
+			diffs = append(diffs, Diff{Field: field, Current: aws.StringValue(current), Desired: *desired})
+			*set = desired
+		}
+	}
+	compareInt := func(field string, desired, current *int64, set **int64) {
+		if desired != nil && (current == nil || *desired != *current) {
+			diffs = append(diffs, Diff{Field: field, Current: current, Desired: *desired})
+			*set = desired
+		}
+	}
+	compareBool := func(field string, desired, current *bool, set **bool) {
+		if desired != nil && *desired != aws.BoolValue(current) {
+			diffs = append(diffs, Diff{Field: field, Current: aws.BoolValue(current), Desired: *desired})
+			*set = desired
+		}
+	}
+
+	desired := q.Attributes
+	compareString("kmsMasterKeyId", desired.KMSMasterKeyID, current.KMSMasterKeyID, &update.KMSMasterKeyID)
+	compareInt("kmsDataKeyReusePeriodSeconds", desired.KMSDataKeyReusePeriodSeconds, current.KMSDataKeyReusePeriodSeconds, &update.KMSDataKeyReusePeriodSeconds)
+	compareBool("sqsManagedSseEnabled", desired.SQSManagedSSEEnabled, current.SQSManagedSSEEnabled, &update.SQSManagedSSEEnabled)
+	compareInt("delaySeconds", desired.DelaySeconds, current.DelaySeconds, &update.DelaySeconds)
+	compareInt("maximumMessageSize", desired.MaximumMessageSize, current.MaximumMessageSize, &update.MaximumMessageSize)
+	compareInt("messageRetentionPeriod", desired.MessageRetentionPeriod, current.MessageRetentionPeriod, &update.MessageRetentionPeriod)
+	compareInt("receiveMessageWaitTimeSeconds", desired.ReceiveMessageWaitTimeSeconds, current.ReceiveMessageWaitTimeSeconds, &update.ReceiveMessageWaitTimeSeconds)
+	compareInt("visibilityTimeout", desired.VisibilityTimeout, current.VisibilityTimeout, &update.VisibilityTimeout)
+
+	if dlq := q.DeadLetterQueue; dlq != nil {
+		var currentTarget string
+		var currentCount int64
+		if current.RedrivePolicy != nil {
+			currentTarget = current.RedrivePolicy.DeadLetterTargetARN
+			currentCount = current.RedrivePolicy.MaxReceiveCount
+		}
+
+		desiredTarget := dlq.Queue
+		if description, ok := s.queues[dlq.Queue]; ok {
+			desiredTarget = description.QueueARN
+		}
+		if desiredTarget != currentTarget || dlq.MaxReceiveCount != currentCount {
+			diffs = append(diffs, Diff{
+				Field:   "deadLetterQueue",
+				Current: current.RedrivePolicy,
+				Desired: dlq,
+			})
+		}
+	}
+
+	tagDiffs, setTags, removeTags := compareTags(q.Tags, s.queueTags[q.Name])
+	diffs = append(diffs, tagDiffs...)
+
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	return s.step(&Change{
+		Action: ActionUpdate,
+		Kind:   KindQueue,
+		Name:   q.Name,
+		Diffs:  diffs,
+	}, func(ctx context.Context, s *state) error {
+		if q.DeadLetterQueue != nil {
+			redrivePolicy, err := redrivePolicy(ctx, s, q.DeadLetterQueue)
+			if err != nil {
+				return err
+			}
+			update.RedrivePolicy = redrivePolicy
+		}
+
+		if _, err := queue.SetQueueAttributes(ctx, q.Name, update); err != nil {
+			return err
+		}
+		if len(setTags) > 0 {
+			if _, err := queue.TagQueue(ctx, q.Name, setTags); err != nil {
+				return err
+			}
+		}
+		if len(removeTags) > 0 {
+			if _, err := queue.UntagQueue(ctx, q.Name, removeTags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func redrivePolicy(ctx context.Context, s *state, dlq *DeadLetterQueue) (*queue.RedrivePolicy, error) {
+	target, err := s.queueARN(ctx, dlq.Queue)
+	if err != nil {
+		return nil, err
+	}
+	return &queue.RedrivePolicy{DeadLetterTargetARN: target, MaxReceiveCount: dlq.MaxReceiveCount}, nil
+}
+
+func planTopic(s *state, topic Topic) *Change {
+	desiredTags := withManagedTag(topic.Tags)
+
+	current, exists := s.topics[topic.Name]
+	if !exists {
+		return s.step(&Change{
+			Action: ActionCreate,
+			Kind:   KindTopic,
+			Name:   topic.Name,
+		}, func(ctx context.Context, s *state) error {
+			result, err := notification.CreateTopic(ctx, topic.Name, topic.Attributes)
+			if err != nil {
+				return err
+			}
+			s.topicARNs[topic.Name] = aws.StringValue(result.TopicArn)
+
+			_, err = notification.TagTopic(ctx, s.topicARNs[topic.Name], desiredTags)
+			return err
+		})
+	}
+
+	var diffs []Diff
+	var update notification.TopicAttributes
+
+	desired := topic.Attributes
+	if desired.DisplayName != nil && *desired.DisplayName != aws.StringValue(current.DisplayName) {
+		diffs = append(diffs, Diff{Field: "displayName", Current: aws.StringValue(current.DisplayName), Desired: *desired.DisplayName})
+		update.DisplayName = desired.DisplayName
+	}
+	if desired.KMSMasterKeyID != nil && *desired.KMSMasterKeyID != aws.StringValue(current.KMSMasterKeyID) {
+		diffs = append(diffs, Diff{Field: "kmsMasterKeyId", Current: aws.StringValue(current.KMSMasterKeyID), Desired: *desired.KMSMasterKeyID})
+		update.KMSMasterKeyID = desired.KMSMasterKeyID
+	}
+	if desired.DeliveryPolicy != nil && !equalJSON(desired.DeliveryPolicy, current.DeliveryPolicy) {
+		diffs = append(diffs, Diff{Field: "deliveryPolicy", Current: current.DeliveryPolicy, Desired: desired.DeliveryPolicy})
+		update.DeliveryPolicy = desired.DeliveryPolicy
+	}
+	if desired.Policy != nil && !equalJSON(desired.Policy, current.Policy) {
+		diffs = append(diffs, Diff{Field: "policy", Current: current.Policy, Desired: desired.Policy})
+		update.Policy = desired.Policy
+	}
+	if desired.ContentBasedDeduplication != nil && *desired.ContentBasedDeduplication != aws.BoolValue(current.ContentBasedDeduplication) {
+		diffs = append(diffs, Diff{Field: "contentBasedDeduplication", Current: aws.BoolValue(current.ContentBasedDeduplication), Desired: *desired.ContentBasedDeduplication})
+		update.ContentBasedDeduplication = desired.ContentBasedDeduplication
+	}
+
+	immutable := desired.FifoTopic != nil && *desired.FifoTopic != aws.BoolValue(current.FifoTopic)
+	if immutable {
+		diffs = append(diffs, Diff{Field: "fifoTopic", Current: aws.BoolValue(current.FifoTopic), Desired: *desired.FifoTopic})
+	}
+
+	tagDiffs, setTags, removeTags := compareTags(topic.Tags, s.topicTags[topic.Name])
+	diffs = append(diffs, tagDiffs...)
+
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	topicARN := s.topicARNs[topic.Name]
+	return s.step(&Change{
+		Action: ActionUpdate,
+		Kind:   KindTopic,
+		Name:   topic.Name,
+		Diffs:  diffs,
+	}, func(ctx context.Context, s *state) error {
+		if immutable {
+			return fmt.Errorf("fifoTopic can only be set at creation; delete the topic to recreate it")
+		}
+
+		if _, err := notification.SetTopicAttributes(ctx, topicARN, update); err != nil {
+			return err
+		}
+		if len(setTags) > 0 {
+			if _, err := notification.TagTopic(ctx, topicARN, setTags); err != nil {
+				return err
+			}
+		}
+		if len(removeTags) > 0 {
+			if _, err := notification.UntagTopic(ctx, topicARN, removeTags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// planSubscription returns the change for a declared subscription, if any, and
+// the ARN of the existing subscription it matched.
+func planSubscription(ctx context.Context, s *state, subscription Subscription) (*Change, string) {
+	protocol, endpoint := "email", subscription.Email
+	if subscription.Queue != "" {
+		protocol, endpoint = "sqs", ""
+		if description, ok := s.queues[subscription.Queue]; ok {
+			endpoint = description.QueueARN
+		}
+	}
+
+	desired := notification.SubscriptionAttributes{
+		FilterPolicy:       subscription.FilterPolicy,
+		FilterPolicyScope:  aws.String(subscription.FilterPolicyScope),
+		RawMessageDelivery: aws.Bool(subscription.RawMessageDelivery),
+	}
+	if len(desired.FilterPolicy) == 0 {
+		desired.FilterPolicy = json.RawMessage("{}")
+	}
+	if subscription.FilterPolicyScope == "" {
+		desired.FilterPolicyScope = aws.String("MessageAttributes")
+	}
+
+	var matched string
+	for _, existing := range s.subscriptions[subscription.Topic] {
+		if endpoint != "" && aws.StringValue(existing.Protocol) == protocol && aws.StringValue(existing.Endpoint) == endpoint {
+			matched = aws.StringValue(existing.SubscriptionArn)
+			break
+		}
+	}
+
+	if matched == "" {
+		return s.step(&Change{
+			Action: ActionCreate,
+			Kind:   KindSubscription,
+			Name:   subscription.key(),
+		}, func(ctx context.Context, s *state) error {
+			attributes := desired
+			if subscription.DeadLetterQueue != "" {
+				redrivePolicy, err := subscriptionRedrivePolicy(ctx, s, subscription.DeadLetterQueue)
+				if err != nil {
+					return err
+				}
+				attributes.RedrivePolicy = redrivePolicy
+			}
+
+			topicARN := s.topicARNs[subscription.Topic]
+			if subscription.Queue != "" {
+				_, err := notification.SubscribeQueueToTopicWithAttributes(ctx, subscription.Queue, topicARN, attributes)
+				return err
+			}
+			_, err := notification.Subscribe(ctx, topicARN, protocol, endpoint, attributes)
+			return err
+		}), ""
+	}
+
+	// Subscriptions pending confirmation have no attributes to compare yet
+	if !strings.HasPrefix(matched, "arn:") {
+		return nil, matched
+	}
+
+	current, err := notification.GetSubscriptionAttributes(ctx, matched)
+	if err != nil {
+		return s.step(&Change{
+			Action: ActionUpdate,
+			Kind:   KindSubscription,
+			Name:   subscription.key(),
+			Error:  err.Error(),
+		}, func(ctx context.Context, s *state) error {
+			return err
+		}), matched
+	}
+
+	var diffs []Diff
+	var update notification.SubscriptionAttributes
+	if !equalJSON(desired.FilterPolicy, current.FilterPolicy) {
+		diffs = append(diffs, Diff{Field: "filterPolicy", Current: current.FilterPolicy, Desired: desired.FilterPolicy})
+		update.FilterPolicy = desired.FilterPolicy
+		update.FilterPolicyScope = desired.FilterPolicyScope
+	}
+	if aws.StringValue(desired.FilterPolicyScope) != aws.StringValue(current.FilterPolicyScope) {
+		diffs = append(diffs, Diff{Field: "filterPolicyScope", Current: aws.StringValue(current.FilterPolicyScope), Desired: *desired.FilterPolicyScope})
+		update.FilterPolicyScope = desired.FilterPolicyScope
+	}
+	if aws.BoolValue(desired.RawMessageDelivery) != aws.BoolValue(current.RawMessageDelivery) {
+		diffs = append(diffs, Diff{Field: "rawMessageDelivery", Current: aws.BoolValue(current.RawMessageDelivery), Desired: *desired.RawMessageDelivery})
+		update.RawMessageDelivery = desired.RawMessageDelivery
+	}
+	if subscription.DeadLetterQueue != "" {
+		var currentPolicy struct {
+			DeadLetterTargetARN string `json:"deadLetterTargetArn"`
+		}
+		json.Unmarshal(current.RedrivePolicy, &currentPolicy)
+
+		desiredTarget := subscription.DeadLetterQueue
+		if description, ok := s.queues[subscription.DeadLetterQueue]; ok {
+			desiredTarget = description.QueueARN
+		}
+		if currentPolicy.DeadLetterTargetARN != desiredTarget {
+			diffs = append(diffs, Diff{Field: "deadLetterQueue", Current: currentPolicy.DeadLetterTargetARN, Desired: subscription.DeadLetterQueue})
+		}
+	}
+
+	if len(diffs) == 0 {
+		return nil, matched
+	}
+
+	return s.step(&Change{
+		Action: ActionUpdate,
+		Kind:   KindSubscription,
+		Name:   subscription.key(),
+		Diffs:  diffs,
+	}, func(ctx context.Context, s *state) error {
+		if subscription.DeadLetterQueue != "" {
+			redrivePolicy, err := subscriptionRedrivePolicy(ctx, s, subscription.DeadLetterQueue)
+			if err != nil {
+				return err
+			}
+			update.RedrivePolicy = redrivePolicy
+		}
+
+		_, err := notification.SetSubscriptionAttributes(ctx, matched, update)
+		return err
+	}), matched
+}
+
+func subscriptionRedrivePolicy(ctx context.Context, s *state, queueName string) (json.RawMessage, error) {
+	target, err := s.queueARN(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]string{"deadLetterTargetArn": target})
+}
+
+// compareTags compares declared tags, plus the managed tag, with the current
+// ones, returning the tags to set and the keys to remove. Undeclared tags are
+// left alone.
+func compareTags(declared, current map[string]string) ([]Diff, map[string]string, []string) {
+	desired := withManagedTag(declared)
+
+	set := map[string]string{}
+	for key, value := range desired {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			set[key] = value
+		}
+	}
+
+	var remove []string
+	if declared != nil {
+		for key := range current {
+			if _, ok := desired[key]; !ok {
+				remove = append(remove, key)
+			}
+		}
+		sort.Strings(remove)
+	}
+
+	if len(set) == 0 && len(remove) == 0 {
+		return nil, nil, nil
+	}
+	return []Diff{{Field: "tags", Current: current, Desired: desired}}, set, remove
+}
+
+func withManagedTag(tags map[string]string) map[string]string {
+	withManaged := map[string]string{ManagedTagKey: ManagedTagValue}
+	for key, value := range tags {
+		withManaged[key] = value
+	}
+	return withManaged
+}
+
+// equalJSON compares JSON documents by value, treating a missing document as
+// an empty object.
+func equalJSON(a, b json.RawMessage) bool {
+	var x, y any = map[string]any{}, map[string]any{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &x); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &y); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(x, y)
+}
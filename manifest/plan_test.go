@@ -0,0 +1,234 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+const (
+	testTopicPrefix = "arn:aws:sns:us-east-1:000000000000:"
+	testQueuePrefix = "arn:aws:sqs:us-east-1:000000000000:"
+)
+
+// addTopic records a declared topic as existing in s
+func addTopic(s *state, name string, description *notification.TopicDescription, tags map[string]string) {
+	s.topicARNs[name] = testTopicPrefix + name
+	description.TopicARN = testTopicPrefix + name
+	s.topics[name] = description
+	s.topicTags[name] = tags
+}
+
+// addQueue records a declared queue as existing in s
+func addQueue(s *state, name string, description *queue.QueueDescription, tags map[string]string) {
+	s.queueURLs[name] = "https://sqs.us-east-1.amazonaws.com/000000000000/" + name
+	description.QueueARN = testQueuePrefix + name
+	s.queues[name] = description
+	s.queueTags[name] = tags
+}
+
+// describe summarizes a change as its action, kind, name and changed fields
+func describe(change *Change) string {
+	summary := string(change.Action) + " " + change.Kind + " " + change.Name
+	var fields []string
+	for _, diff := range change.Diffs {
+		fields = append(fields, diff.Field)
+	}
+	if len(fields) > 0 {
+		summary += " (" + strings.Join(fields, ", ") + ")"
+	}
+	return summary
+}
+
+func TestPlanState(t *testing.T) {
+	orders := Queue{
+		Name:            "orders",
+		Attributes:      queue.QueueAttributes{VisibilityTimeout: aws.Int64(30)},
+		DeadLetterQueue: &DeadLetterQueue{Queue: "orders-dlq", MaxReceiveCount: 5},
+	}
+	ordersDLQ := Queue{Name: "orders-dlq"}
+	events := Topic{Name: "events", Attributes: notification.TopicAttributes{DisplayName: aws.String("Events")}}
+
+	// inSync records the manifest's topic and queues as they are declared
+	inSync := func(s *state) {
+		addQueue(s, "orders-dlq", &queue.QueueDescription{}, withManagedTag(nil))
+		addQueue(s, "orders", &queue.QueueDescription{
+			QueueAttributes: queue.QueueAttributes{
+				VisibilityTimeout: aws.Int64(30),
+				RedrivePolicy:     &queue.RedrivePolicy{DeadLetterTargetARN: testQueuePrefix + "orders-dlq", MaxReceiveCount: 5},
+			},
+		}, withManagedTag(nil))
+		addTopic(s, "events", &notification.TopicDescription{
+			TopicAttributes: notification.TopicAttributes{DisplayName: aws.String("Events")},
+		}, withManagedTag(nil))
+	}
+
+	tests := []struct {
+		name     string
+		manifest Manifest
+		setup    func(s *state)
+		options  Options
+
+		wantChanges  []string
+		wantOrphaned []string
+	}{
+		{
+			name: "creates everything, dead-letter queues first",
+			manifest: Manifest{
+				Topics:        []Topic{events},
+				Queues:        []Queue{orders, ordersDLQ},
+				Subscriptions: []Subscription{{Topic: "events", Queue: "orders"}},
+			},
+			wantChanges: []string{
+				"create queue orders-dlq",
+				"create queue orders",
+				"create topic events",
+				"create subscription events -> queue orders",
+			},
+		},
+		{
+			name:     "in sync",
+			manifest: Manifest{Topics: []Topic{events}, Queues: []Queue{orders, ordersDLQ}},
+			setup:    inSync,
+		},
+		{
+			name:     "attributes drifted",
+			manifest: Manifest{Topics: []Topic{events}, Queues: []Queue{orders, ordersDLQ}},
+			setup: func(s *state) {
+				inSync(s)
+				s.queues["orders"].VisibilityTimeout = aws.Int64(60)
+				s.queues["orders"].RedrivePolicy.MaxReceiveCount = 10
+				s.topics["events"].DisplayName = aws.String("Old events")
+			},
+			wantChanges: []string{
+				"update queue orders (visibilityTimeout, deadLetterQueue)",
+				"update topic events (displayName)",
+			},
+		},
+		{
+			name: "declared tags drifted",
+			manifest: Manifest{
+				Topics: []Topic{{Name: "events", Tags: map[string]string{"team": "payments"}}},
+			},
+			setup: func(s *state) {
+				addTopic(s, "events", &notification.TopicDescription{}, withManagedTag(map[string]string{"team": "billing", "owner": "ops"}))
+			},
+			wantChanges: []string{"update topic events (tags)"},
+		},
+		{
+			name:     "undeclared tags are left alone",
+			manifest: Manifest{Topics: []Topic{{Name: "events"}}},
+			setup: func(s *state) {
+				addTopic(s, "events", &notification.TopicDescription{}, withManagedTag(map[string]string{"owner": "ops"}))
+			},
+		},
+		{
+			name: "fifo cannot change",
+			manifest: Manifest{
+				Topics: []Topic{{Name: "events", Attributes: notification.TopicAttributes{FifoTopic: aws.Bool(true)}}},
+			},
+			setup: func(s *state) {
+				addTopic(s, "events", &notification.TopicDescription{}, withManagedTag(nil))
+			},
+			wantChanges: []string{"update topic events (fifoTopic)"},
+		},
+		{
+			name:     "orphans are reported",
+			manifest: Manifest{Topics: []Topic{events}, Queues: []Queue{orders, ordersDLQ}},
+			setup: func(s *state) {
+				inSync(s)
+				s.orphanedTopics = []string{"old-events"}
+				s.orphanedQueues = []string{"old-orders"}
+				s.subscriptions["events"] = []*sns.Subscription{
+					{SubscriptionArn: aws.String(testTopicPrefix + "events:1"), Protocol: aws.String("email"), Endpoint: aws.String("ops@example.com")},
+					{SubscriptionArn: aws.String("PendingConfirmation"), Protocol: aws.String("email"), Endpoint: aws.String("new@example.com")},
+				}
+			},
+			wantOrphaned: []string{
+				"subscription events -> email ops@example.com",
+				"topic old-events",
+				"queue old-orders",
+			},
+		},
+		{
+			name:     "orphans are pruned",
+			manifest: Manifest{Topics: []Topic{events}, Queues: []Queue{orders, ordersDLQ}},
+			setup: func(s *state) {
+				inSync(s)
+				s.orphanedTopics = []string{"old-events"}
+				s.orphanedQueues = []string{"old-orders"}
+				s.subscriptions["events"] = []*sns.Subscription{
+					{SubscriptionArn: aws.String(testTopicPrefix + "events:1"), Protocol: aws.String("email"), Endpoint: aws.String("ops@example.com")},
+				}
+			},
+			options: Options{Prune: true},
+			wantChanges: []string{
+				"delete subscription events -> email ops@example.com",
+				"delete topic old-events",
+				"delete queue old-orders",
+			},
+		},
+		{
+			name: "subscriptions pending confirmation are matched",
+			manifest: Manifest{
+				Topics:        []Topic{events},
+				Subscriptions: []Subscription{{Topic: "events", Email: "new@example.com"}},
+			},
+			setup: func(s *state) {
+				inSync(s)
+				s.subscriptions["events"] = []*sns.Subscription{
+					{SubscriptionArn: aws.String("PendingConfirmation"), Protocol: aws.String("email"), Endpoint: aws.String("new@example.com")},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newState()
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			plan, err := planState(context.Background(), &tt.manifest, s, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var changes []string
+			for _, change := range plan.Changes {
+				changes = append(changes, describe(change))
+				if s.steps[change] == nil {
+					t.Errorf("%s has no step", describe(change))
+				}
+			}
+			if !slices.Equal(changes, tt.wantChanges) {
+				t.Errorf("changes are %q, want %q", changes, tt.wantChanges)
+			}
+			if !slices.Equal(plan.Orphaned, tt.wantOrphaned) {
+				t.Errorf("orphaned are %q, want %q", plan.Orphaned, tt.wantOrphaned)
+			}
+			if wantInSync := len(tt.wantChanges) == 0 && len(tt.wantOrphaned) == 0; plan.InSync != wantInSync {
+				t.Errorf("in sync is %v, want %v", plan.InSync, wantInSync)
+			}
+		})
+	}
+}
+
+func TestPlanStateDeadLetterCycle(t *testing.T) {
+	m := &Manifest{Queues: []Queue{
+		{Name: "a", DeadLetterQueue: &DeadLetterQueue{Queue: "b", MaxReceiveCount: 1}},
+		{Name: "b", DeadLetterQueue: &DeadLetterQueue{Queue: "a", MaxReceiveCount: 1}},
+	}}
+	if _, err := planState(context.Background(), m, newState(), Options{}); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("planState() = %v, want ErrInvalidManifest", err)
+	}
+}
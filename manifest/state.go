@@ -0,0 +1,158 @@
+package manifest
+
+import (
+	"context"
+	"path"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+// ManagedTagKey tags the topics and queues a manifest has applied, so pruning
+// never deletes resources that were created some other way.
+const (
+	ManagedTagKey   = "pub-sub-service:managed"
+	ManagedTagValue = "true"
+)
+
+// state is the actual state of the resources a manifest refers to.
+type state struct {
+	// Every topic and queue, by name
+	topicARNs map[string]string
+	queueURLs map[string]string
+
+	// Declared topics and queues that exist
+	topics        map[string]*notification.TopicDescription
+	topicTags     map[string]map[string]string
+	queues        map[string]*queue.QueueDescription
+	queueTags     map[string]map[string]string
+	subscriptions map[string][]*sns.Subscription
+
+	// Undeclared topics and queues carrying the managed tag
+	orphanedTopics []string
+	orphanedQueues []string
+
+	// What applies each planned change
+	steps map[*Change]func(ctx context.Context, s *state) error
+}
+
+// step records apply as the step that applies change, and returns change.
+func (s *state) step(change *Change, apply func(ctx context.Context, s *state) error) *Change {
+	s.steps[change] = apply
+	return change
+}
+
+func newState() *state {
+	return &state{
+		topicARNs:     map[string]string{},
+		queueURLs:     map[string]string{},
+		topics:        map[string]*notification.TopicDescription{},
+		topicTags:     map[string]map[string]string{},
+		queues:        map[string]*queue.QueueDescription{},
+		queueTags:     map[string]map[string]string{},
+		subscriptions: map[string][]*sns.Subscription{},
+		steps:         map[*Change]func(ctx context.Context, s *state) error{},
+	}
+}
+
+func loadState(ctx context.Context, m *Manifest) (*state, error) {
+	s := newState()
+
+	topics, err := notification.ListTopics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		topicARN := aws.StringValue(topic.TopicArn)
+		s.topicARNs[notification.TopicName(topicARN)] = topicARN
+	}
+
+	queueURLs, err := queue.ListQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, queueURL := range queueURLs {
+		s.queueURLs[path.Base(queueURL)] = queueURL
+	}
+
+	declaredTopics := map[string]bool{}
+	for _, topic := range m.Topics {
+		declaredTopics[topic.Name] = true
+
+		topicARN, ok := s.topicARNs[topic.Name]
+		if !ok {
+			continue
+		}
+
+		if s.topics[topic.Name], err = notification.GetTopicAttributes(ctx, topicARN); err != nil {
+			return nil, err
+		}
+		if s.topicTags[topic.Name], err = notification.ListTopicTags(ctx, topicARN); err != nil {
+			return nil, err
+		}
+		if s.subscriptions[topic.Name], err = notification.ListSubscriptions(ctx, &topicARN); err != nil {
+			return nil, err
+		}
+	}
+
+	declaredQueues := map[string]bool{}
+	for _, q := range m.Queues {
+		declaredQueues[q.Name] = true
+
+		if _, ok := s.queueURLs[q.Name]; !ok {
+			continue
+		}
+
+		if s.queues[q.Name], err = queue.GetQueueAttributes(ctx, q.Name); err != nil {
+			return nil, err
+		}
+		if s.queueTags[q.Name], err = queue.ListQueueTags(ctx, q.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, topicARN := range s.topicARNs {
+		if declaredTopics[name] {
+			continue
+		}
+		tags, err := notification.ListTopicTags(ctx, topicARN)
+		if err != nil {
+			return nil, err
+		}
+		if tags[ManagedTagKey] == ManagedTagValue {
+			s.orphanedTopics = append(s.orphanedTopics, name)
+		}
+	}
+
+	for name := range s.queueURLs {
+		if declaredQueues[name] {
+			continue
+		}
+		tags, err := queue.ListQueueTags(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if tags[ManagedTagKey] == ManagedTagValue {
+			s.orphanedQueues = append(s.orphanedQueues, name)
+		}
+	}
+
+	return s, nil
+}
+
+// queueARN returns the ARN of an existing queue, looking it up if it was
+// created after the state was loaded.
+func (s *state) queueARN(ctx context.Context, name string) (string, error) {
+	if description, ok := s.queues[name]; ok {
+		return description.QueueARN, nil
+	}
+
+	description, err := queue.GetQueueAttributes(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	s.queues[name] = description
+	return description.QueueARN, nil
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// InstrumentAWS adds handlers that count and time every SNS and SQS call made
+// with the given handlers, along with SDK retries and throttling errors.
+func InstrumentAWS(handlers *request.Handlers) {
+	handlers.AfterRetry.PushBackNamed(request.NamedHandler{
+		Name: "pubsub.metrics.AfterRetry",
+		Fn:   observeRetry,
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "pubsub.metrics.Complete",
+		Fn:   observeRequest,
+	})
+}
+
+func observeRetry(r *request.Request) {
+	service, operation := r.ClientInfo.ServiceName, r.Operation.Name
+
+	if r.IsErrorThrottle() {
+		awsThrottles.WithLabelValues(service, operation).Inc()
+	}
+	if r.WillRetry() {
+		awsRetries.WithLabelValues(service, operation).Inc()
+	}
+}
+
+func observeRequest(r *request.Request) {
+	operation := r.Operation.Name
+	elapsed := time.Since(r.Time).Seconds()
+
+	outcome := "success"
+	if r.Error != nil {
+		outcome = "error"
+	}
+
+	switch r.ClientInfo.ServiceName {
+	case sns.ServiceName:
+		topic := TopicLabel(paramValue(r, "TopicArn"))
+		topicOperations.WithLabelValues(operation, topic, outcome).Inc()
+		topicOperationDuration.WithLabelValues(operation, topic).Observe(elapsed)
+	case sqs.ServiceName:
+		queue := paramValue(r, "QueueName")
+		if queue == "" {
+			queue = QueueLabel(paramValue(r, "QueueUrl"))
+		}
+		queueOperations.WithLabelValues(operation, queue, outcome).Inc()
+		queueOperationDuration.WithLabelValues(operation, queue).Observe(elapsed)
+
+		if out, ok := r.Data.(*sqs.ReceiveMessageOutput); ok && r.Error == nil {
+			messagesReceived.WithLabelValues(queue).Add(float64(len(out.Messages)))
+		}
+	}
+}
+
+// TopicLabel shortens a topic ARN to the topic name.
+func TopicLabel(topicARN string) string {
+	return topicARN[strings.LastIndex(topicARN, ":")+1:]
+}
+
+// QueueLabel shortens a queue URL to the queue name.
+func QueueLabel(queueURL string) string {
+	return queueURL[strings.LastIndex(queueURL, "/")+1:]
+}
+
+func paramValue(r *request.Request, name string) string {
+	values, err := awsutil.ValuesAtPath(r.Params, name)
+	if err != nil || len(values) == 0 {
+		return ""
+	}
+
+	if value, ok := values[0].(*string); ok && value != nil {
+		return *value
+	}
+
+	return ""
+}
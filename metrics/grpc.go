@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor records call counts and latencies for gRPC unary
+// calls, as Middleware does for HTTP.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		observeCall(info.FullMethod, err, start)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor records call counts and durations for gRPC
+// streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		observeCall(info.FullMethod, err, start)
+		return err
+	}
+}
+
+func observeCall(method string, err error, start time.Time) {
+	grpcCalls.WithLabelValues(method, status.Code(err).String()).Inc()
+	grpcCallDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "pubsub"
+
+var (
+	topicOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "topic_operations_total",
+		Help:      "SNS operations by operation, topic and outcome.",
+	}, []string{"operation", "topic", "outcome"})
+
+	topicOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "topic_operation_duration_seconds",
+		Help:      "Latency of SNS operations by operation and topic.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "topic"})
+
+	queueOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_operations_total",
+		Help:      "SQS operations by operation, queue and outcome.",
+	}, []string{"operation", "queue", "outcome"})
+
+	queueOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_operation_duration_seconds",
+		Help:      "Latency of SQS operations by operation and queue.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "queue"})
+
+	messagesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_messages_received_total",
+		Help:      "Messages received from SQS by queue.",
+	}, []string{"queue"})
+
+	awsRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "aws_retries_total",
+		Help:      "AWS SDK request retries by service and operation.",
+	}, []string{"service", "operation"})
+
+	awsThrottles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "aws_throttles_total",
+		Help:      "Throttling errors returned by AWS by service and operation.",
+	}, []string{"service", "operation"})
+
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_messages",
+		Help:      "Approximate number of messages in a queue by state (visible, not_visible, delayed).",
+	}, []string{"queue", "state"})
+
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	grpcCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_calls_total",
+		Help:      "gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_call_duration_seconds",
+		Help:      "Duration of gRPC calls by method; streaming calls last as long as the stream.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	scheduledDispatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduled_messages_dispatched_total",
+		Help:      "Dispatches of scheduled messages by target kind and outcome (delivered, retried, failed).",
+	}, []string{"kind", "outcome"})
+
+	recurringRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recurring_runs_total",
+		Help:      "Runs of recurring schedules by schedule and status (delivered, failed, skipped).",
+	}, []string{"schedule", "status"})
+
+	duplicateMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_messages_skipped_total",
+		Help:      "Redelivered messages skipped by consumer-side deduplication, by queue.",
+	}, []string{"queue"})
+
+	outboxRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_rows_total",
+		Help:      "Outbox rows relayed by target kind and outcome (sent, retried, failed).",
+	}, []string{"kind", "outcome"})
+)
+
+// SetQueueDepth records the ApproximateNumberOfMessages* attributes of a queue.
+func SetQueueDepth(queue string, visible, notVisible, delayed float64) {
+	queueDepth.WithLabelValues(queue, "visible").Set(visible)
+	queueDepth.WithLabelValues(queue, "not_visible").Set(notVisible)
+	queueDepth.WithLabelValues(queue, "delayed").Set(delayed)
+}
+
+// DeleteQueueDepth drops the depth gauges of a queue that no longer exists.
+func DeleteQueueDepth(queue string) {
+	queueDepth.DeletePartialMatch(prometheus.Labels{"queue": queue})
+}
+
+// ObserveScheduledDispatch counts a dispatch of a scheduled message.
+func ObserveScheduledDispatch(kind, outcome string) {
+	scheduledDispatches.WithLabelValues(kind, outcome).Inc()
+}
+
+// ObserveRecurringRun counts a run of a recurring schedule.
+func ObserveRecurringRun(schedule, status string) {
+	recurringRuns.WithLabelValues(schedule, status).Inc()
+}
+
+// ObserveDuplicateMessage counts a redelivered message skipped by a consumer.
+func ObserveDuplicateMessage(queue string) {
+	duplicateMessages.WithLabelValues(queue).Inc()
+}
+
+// ObserveOutboxRow counts an attempt at relaying an outbox row.
+func ObserveOutboxRow(kind, outcome string) {
+	outboxRows.WithLabelValues(kind, outcome).Inc()
+}
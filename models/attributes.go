@@ -0,0 +1,172 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+)
+
+type TagInput = api.TagInput
+
+func GetTopicAttributes(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := notification.GetTopicAttributes(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get topic attributes", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetTopicAttributes(ctx context.Context, topicARN string, topicAttributes notification.TopicAttributes) (*Response, error) {
+	res, err := notification.SetTopicAttributes(ctx, topicARN, topicAttributes)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not set topic attributes", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func ListTopicTags(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := notification.ListTopicTags(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list topic tags", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func TagTopic(ctx context.Context, topicARN string, tagInput TagInput) (*Response, error) {
+	res, err := notification.TagTopic(ctx, topicARN, tagInput.Tags)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not tag topic", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func UntagTopic(ctx context.Context, topicARN string, keys []string) (*Response, error) {
+	res, err := notification.UntagTopic(ctx, topicARN, keys)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not untag topic", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetQueueAttributes(ctx context.Context, queueName string) (*Response, error) {
+	res, err := queue.GetQueueAttributes(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue attributes", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetQueueAttributes(ctx context.Context, queueName string, queueAttributes queue.QueueAttributes) (*Response, error) {
+	res, err := queue.SetQueueAttributes(ctx, queueName, queueAttributes)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not set queue attributes", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func ListQueueTags(ctx context.Context, queueName string) (*Response, error) {
+	res, err := queue.ListQueueTags(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list queue tags", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func TagQueue(ctx context.Context, queueName string, tagInput TagInput) (*Response, error) {
+	res, err := queue.TagQueue(ctx, queueName, tagInput.Tags)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not tag queue", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func UntagQueue(ctx context.Context, queueName string, keys []string) (*Response, error) {
+	res, err := queue.UntagQueue(ctx, queueName, keys)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not untag queue", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/cloudevents"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+	"pub-sub-service/settings"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+)
+
+var ErrCloudEventRequired = errors.New("topic only accepts CloudEvents")
+
+// ReceivedCloudEvent is a received queue message returned as a CloudEvent.
+type ReceivedCloudEvent = api.ReceivedCloudEvent
+
+// PublishCloudEvent publishes the data of a CloudEvent to a topic, with its
+// context attributes mapped to ce-* message attributes. Events with more
+// attributes than a message may carry are rejected with
+// notification.ErrInvalidAttributes before anything is published; the
+// attributes the service adds are counted once they are known.
+func PublishCloudEvent(ctx context.Context, topicARN string, event *cloudevents.Event) (*Response, error) {
+	attributes := map[string]string{}
+	cloudevents.ToAttributes(event, attributes)
+	if event.DataContentType != "" {
+		attributes[payload.ContentTypeAttribute] = event.DataContentType
+	}
+	if len(attributes) > notification.MaxMessageAttributes {
+		logging.FromContext(ctx).Warn("CloudEvent has too many attributes", slog.Int("attributes", len(attributes)))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, fmt.Errorf("%w: the CloudEvent maps to %d message attributes, over the limit of %d",
+			notification.ErrInvalidAttributes, len(attributes), notification.MaxMessageAttributes)
+	}
+
+	topicSettings, err := settings.GetTopic(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get topic settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return publish(ctx, topicARN, topicSettings, event.Payload(), attributes)
+}
+
+// toCloudEvent returns a received message as a CloudEvent. Messages that were
+// not published as CloudEvents get one built from the message itself.
+func toCloudEvent(queueName string, message *queue.ReceivedMessage) *cloudevents.Event {
+	event, ok := cloudevents.FromAttributes(message.Attributes)
+	if !ok {
+		event = &cloudevents.Event{
+			SpecVersion: cloudevents.SpecVersion,
+			ID:          message.MessageID,
+			Source:      "/queues/" + queueName,
+			Type:        "com.amazonaws.sqs.message",
+			Subject:     message.Attributes["Subject"],
+		}
+		if message.TopicARN != "" {
+			event.Source = message.TopicARN
+			event.Type = "com.amazonaws.sns.notification"
+		}
+		if !message.SentAt.IsZero() {
+			sentAt := message.SentAt
+			event.Time = &sentAt
+		}
+	}
+
+	contentType := message.ContentType
+	if contentType == "" && message.Data == nil {
+		contentType = "text/plain"
+		if json.Valid([]byte(message.Body)) {
+			contentType = payload.ContentTypeJSON
+		}
+	}
+	event.SetData(contentType, message.Payload())
+
+	return event
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/logging"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+)
+
+func GetTopicEncryption(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := notification.GetTopicEncryption(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get topic encryption", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetTopicEncryption(ctx context.Context, topicARN string, topicEncryption notification.TopicEncryption) (*Response, error) {
+	res, err := notification.SetTopicEncryption(ctx, topicARN, topicEncryption)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not set topic encryption", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetQueueEncryption(ctx context.Context, queueName string) (*Response, error) {
+	res, err := queue.GetQueueEncryption(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue encryption", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetQueueEncryption(ctx context.Context, queueName string, queueEncryption queue.QueueEncryption) (*Response, error) {
+	res, err := queue.SetQueueEncryption(ctx, queueName, queueEncryption)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not set queue encryption", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
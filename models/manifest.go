@@ -0,0 +1,60 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/logging"
+	"pub-sub-service/manifest"
+)
+
+func PlanManifest(ctx context.Context, data []byte, options manifest.Options) (*Response, error) {
+	m, err := manifest.Parse(data)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not parse manifest", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	res, err := manifest.BuildPlan(ctx, m, options)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not plan manifest", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+// ApplyManifest converges the manifest. When a change fails, the response
+// still carries the plan, showing which changes were applied.
+func ApplyManifest(ctx context.Context, data []byte, options manifest.Options) (*Response, error) {
+	m, err := manifest.Parse(data)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not parse manifest", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	res, err := manifest.Apply(ctx, m, options)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not apply manifest", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: res,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pub-sub-service/outbox"
+	"pub-sub-service/payload"
+	"pub-sub-service/schema"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+	"pub-sub-service/validation"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// DispatchOutbox publishes or sends an outbox row the way the publish and
+// send routes do, including delays. Topics may be given by name. Rows that
+// are not valid requests, or that their topic rejects, are outbox.ErrPoison.
+func DispatchOutbox(ctx context.Context, row *outbox.Row) error {
+	switch row.Kind {
+	case outbox.KindTopic:
+		var input PublishMessageInput
+		if err := decodeOutboxPayload(row.Payload, &input); err != nil {
+			return err
+		}
+
+		topicARN := row.Target
+		if !validation.TopicARN(topicARN) {
+			var err error
+			topicARN, err = notification.ResolveTopicARN(ctx, row.Target)
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if input.Delay() > 0 {
+			_, err = SchedulePublish(ctx, topicARN, input)
+		} else {
+			_, err = PublishMessageToAllTopicSubscribers(ctx, topicARN, input)
+		}
+		return outboxError(err)
+	case outbox.KindQueue:
+		var input SendMessageInput
+		if err := decodeOutboxPayload(row.Payload, &input); err != nil {
+			return err
+		}
+
+		var err error
+		if input.Delay() > queue.MaxDelay {
+			_, err = ScheduleSend(ctx, row.Target, input)
+		} else {
+			_, err = SendMessage(ctx, row.Target, input)
+		}
+		return outboxError(err)
+	default:
+		return fmt.Errorf("%w: unknown kind %q", outbox.ErrPoison, row.Kind)
+	}
+}
+
+// decodeOutboxPayload reads and validates a request written by an
+// application, which has not been through the routes' validation
+func decodeOutboxPayload(data json.RawMessage, input any) error {
+	validation.Register()
+
+	if err := json.Unmarshal(data, input); err != nil {
+		return fmt.Errorf("%w: %v", outbox.ErrPoison, err)
+	}
+	if err := binding.Validator.ValidateStruct(input); err != nil {
+		return fmt.Errorf("%w: %v", outbox.ErrPoison, err)
+	}
+	return nil
+}
+
+// outboxError marks the errors that a retry would get again as poison
+func outboxError(err error) error {
+	var validationErr *schema.ValidationError
+	if errors.Is(err, ErrCloudEventRequired) || errors.Is(err, payload.ErrUnknownCompression) || errors.Is(err, queue.ErrInvalidAttributes) || errors.Is(err, notification.ErrInvalidAttributes) || errors.As(err, &validationErr) {
+		return fmt.Errorf("%w: %w", outbox.ErrPoison, err)
+	}
+	return err
+}
@@ -1,34 +1,63 @@
 package models
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+	"pub-sub-service/schema"
+	"pub-sub-service/settings"
 	notification "pub-sub-service/sns"
+
+	"github.com/aws/aws-sdk-go/aws"
 )
 
-type CreateTopicInput struct {
-	TopicName string `json:"topicName"`
-}
+// Topic request bodies, shared with the client
+type (
+	CreateTopicInput           = api.CreateTopicInput
+	SubscribeEmailToTopicInput = api.SubscribeEmailToTopicInput
+	SubscribeQueueToTopicInput = api.SubscribeQueueToTopicInput
+	UnsubscribeFromTopicInput  = api.UnsubscribeFromTopicInput
+	PublishMessageInput        = api.PublishMessageInput
+)
 
-type SubscribeEmailToTopicInput struct {
-	Email string `json:"email"`
-}
+func ListTopics(ctx context.Context) (*Response, error) {
+	res, err := notification.ListTopics(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list topics", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
 
-type SubscribeQueueToTopicInput struct {
-	QueueName string `json:"queueName"`
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
 }
 
-type UnsubscribeFromTopicInput struct {
-	SubscriptionID string `json:"subscriptionID"`
-}
+func CreateTopic(ctx context.Context, createTopicInput CreateTopicInput) (*Response, error) {
+	res, err := notification.CreateTopic(ctx, createTopicInput.TopicName, createTopicInput.Attributes)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not create topic", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
 
-type PublishMessageInput struct {
-	Message string `json:"message"`
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
 }
 
-func ListTopics() (*Response, error) {
-	res, err := notification.ListTopics()
+func ListSubscriptions(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := notification.ListSubscriptions(ctx, &topicARN)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not list subscriptions to topic", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
@@ -41,10 +70,10 @@ func ListTopics() (*Response, error) {
 	}, nil
 }
 
-func CreateTopic(createTopicInput CreateTopicInput) (*Response, error) {
-	res, err := notification.CreateTopic(createTopicInput.TopicName)
+func SubscribeEmailToTopic(ctx context.Context, topicARN string, subscribeEmailToTopicInput SubscribeEmailToTopicInput) (*Response, error) {
+	res, err := notification.SubscribeEmailToTopic(ctx, &subscribeEmailToTopicInput.Email, &topicARN)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not subscribe email to topic", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
@@ -57,10 +86,10 @@ func CreateTopic(createTopicInput CreateTopicInput) (*Response, error) {
 	}, nil
 }
 
-func ListSubscriptions(topicARN string) (*Response, error) {
-	res, err := notification.ListSubscriptions(&topicARN)
+func SubscribeQueueToTopic(ctx context.Context, topicARN string, subscribeQueueToTopicInput SubscribeQueueToTopicInput) (*Response, error) {
+	res, err := notification.SubscribeQueueToTopic(ctx, subscribeQueueToTopicInput.QueueName, &topicARN)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not subscribe queue to topic", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
@@ -73,10 +102,10 @@ func ListSubscriptions(topicARN string) (*Response, error) {
 	}, nil
 }
 
-func SubscribeEmailToTopic(topicARN string, subscribeEmailToTopicInput SubscribeEmailToTopicInput) (*Response, error) {
-	res, err := notification.SubscribeEmailToTopic(&subscribeEmailToTopicInput.Email, &topicARN)
+func UnsubscribeFromTopic(ctx context.Context, topicARN string, unsubscribeFromTopicInput UnsubscribeFromTopicInput) (*Response, error) {
+	res, err := notification.UnsubscribeFromTopic(ctx, &unsubscribeFromTopicInput.SubscriptionID, &topicARN)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not unsubscribe from topic", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
@@ -89,10 +118,66 @@ func SubscribeEmailToTopic(topicARN string, subscribeEmailToTopicInput Subscribe
 	}, nil
 }
 
-func SubscribeQueueToTopic(topicARN string, subscribeQueueToTopicInput SubscribeQueueToTopicInput) (*Response, error) {
-	res, err := notification.SubscribeQueueToTopic(subscribeQueueToTopicInput.QueueName, &topicARN)
+func PublishMessageToAllTopicSubscribers(ctx context.Context, topicARN string, message PublishMessageInput) (*Response, error) {
+	return publishMessage(ctx, topicARN, message, map[string]string{})
+}
+
+// publishMessage publishes a message with the given attributes, to which its
+// content type is added.
+func publishMessage(ctx context.Context, topicARN string, message PublishMessageInput, attributes map[string]string) (*Response, error) {
+	body := []byte(message.Message)
+	if message.Data != nil {
+		body = message.Data
+	}
+
+	topicSettings, err := settings.GetTopic(ctx, topicARN)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not get topic settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+	if topicSettings.CloudEvents {
+		logging.FromContext(ctx).Warn("message rejected by CloudEvents topic", slog.String("topic", topicARN))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, ErrCloudEventRequired
+	}
+
+	if message.ContentType != "" {
+		attributes[payload.ContentTypeAttribute] = message.ContentType
+	}
+
+	return publish(payload.WithCompression(ctx, message.Compression), topicARN, topicSettings, body, attributes)
+}
+
+// publish validates body against the topic schema and publishes it with the
+// given attributes, encrypted if the topic settings ask for it.
+func publish(ctx context.Context, topicARN string, topicSettings settings.Topic, body []byte, attributes map[string]string) (*Response, error) {
+	ctx = payload.WithEncryption(ctx, topicSettings.Encrypt)
+
+	// Messages to topics with a registered schema must match the active version
+	active, err := schema.Default().Validate(ctx, topicARN, body)
+	if err != nil {
+		logging.FromContext(ctx).Warn("message rejected by topic schema", slog.String("topic", topicARN), slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+	if active != nil {
+		attributes[schema.Attribute] = active.Stamp()
+		if attributes[payload.ContentTypeAttribute] == "" {
+			attributes[payload.ContentTypeAttribute] = schema.ContentType(active.Format)
+		}
+	}
+
+	messageBody := string(body)
+	res, err := notification.PublishMessageToAllTopicSubscribers(ctx, &messageBody, &topicARN, attributes)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not publish message", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
@@ -105,26 +190,35 @@ func SubscribeQueueToTopic(topicARN string, subscribeQueueToTopicInput Subscribe
 	}, nil
 }
 
-func UnsubscribeFromTopic(topicARN string, unsubscribeFromTopicInput UnsubscribeFromTopicInput) (*Response, error) {
-	res, err := notification.UnsubscribeFromTopic(&unsubscribeFromTopicInput.SubscriptionID, &topicARN)
+// Topic is a topic by name, as addressed by the /v1 routes.
+type Topic = api.Topic
+
+func ListNamedTopics(ctx context.Context) (*Response, error) {
+	topics, err := notification.ListTopics(ctx)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not list topics", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
 		}, err
 	}
 
+	res := make([]Topic, 0, len(topics))
+	for _, topic := range topics {
+		arn := aws.StringValue(topic.TopicArn)
+		res = append(res, Topic{Name: notification.TopicName(arn), ARN: arn})
+	}
+
 	return &Response{
 		Ok: true,
 		Response: res,
 	}, nil
 }
 
-func PublishMessageToAllTopicSubscribers(topicARN string, message PublishMessageInput) (*Response, error) {
-	res, err := notification.PublishMessageToAllTopicSubscribers(&message.Message, &topicARN)
+func DeleteTopic(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := notification.DeleteTopic(ctx, topicARN)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx).Error("could not delete topic", slog.Any("error", err))
 		return &Response{
 			Ok: false,
 			Response: nil,
@@ -135,4 +229,4 @@ func PublishMessageToAllTopicSubscribers(topicARN string, message PublishMessage
 		Ok: true,
 		Response: res,
 	}, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+	"pub-sub-service/schema"
+	"pub-sub-service/settings"
+	queue "pub-sub-service/sqs"
+	"time"
+)
+
+// Queue request and response bodies, shared with the client
+type (
+	CreateQueueInput             = api.CreateQueueInput
+	SendMessageInput             = api.SendMessageInput
+	ReceiveMessageInput          = api.ReceiveMessageInput
+	DeleteMessageInput           = api.DeleteMessageInput
+	ChangeMessageVisibilityInput = api.ChangeMessageVisibilityInput
+	ReceivedMessage              = api.ReceivedMessage
+)
+
+func ListQueues(ctx context.Context) (*Response, error) {
+	res, err := queue.ListQueues(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list queues", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func CreateQueue(ctx context.Context, createQueueInput CreateQueueInput) (*Response, error) {
+	res, err := queue.CreateQueue(ctx, createQueueInput.QueueName, createQueueInput.Attributes)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not create queue", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetQueueURL(ctx context.Context, queueName string) (*Response, error) {
+	res, err := queue.GetQueueURL(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue URL", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func DeleteQueue(ctx context.Context, queueName string) (*Response, error) {
+	res, err := queue.DeleteQueue(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not delete queue", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SendMessage(ctx context.Context, queueName string, sendMessageInput SendMessageInput) (*Response, error) {
+	return sendMessage(ctx, queueName, sendMessageInput, "")
+}
+
+// sendMessage sends a message, asking for replies to be sent to replyTo if
+// it is a request
+func sendMessage(ctx context.Context, queueName string, sendMessageInput SendMessageInput, replyTo string) (*Response, error) {
+	message := queue.Message{
+		Subject:          sendMessageInput.Subject,
+		Body:             sendMessageInput.Body,
+		Timestamp:        time.Now(),
+		ContentType:      sendMessageInput.ContentType,
+		Attributes:       sendMessageInput.Attributes,
+		BinaryAttributes: sendMessageInput.BinaryAttributes,
+		Delay:            sendMessageInput.Delay(),
+		CorrelationID:    sendMessageInput.CorrelationID,
+		ReplyTo:          replyTo,
+	}
+	if sendMessageInput.Data != nil {
+		message.Body = string(sendMessageInput.Data)
+	}
+
+	queueSettings, err := settings.GetQueue(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	ctx = payload.WithCompression(ctx, sendMessageInput.Compression)
+	ctx = payload.WithEncryption(ctx, queueSettings.Encrypt)
+
+	res, err := queue.SendMessage(ctx, queueName, message)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not send message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func ReceiveMessage(ctx context.Context, queueName string, receiveMessageInput ReceiveMessageInput) (*Response, error) {
+	res, err := queue.ReceiveMessage(ctx, queueName, receiveMessageInput.VisibilityTimeout)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not receive message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+	if res == nil {
+		return &Response{
+			Ok: true,
+			Response: nil,
+		}, nil
+	}
+
+	if receiveMessageInput.CloudEvents {
+		return &Response{
+			Ok: true,
+			Response: ReceivedCloudEvent{
+				ReceiptHandle: res.ReceiptHandle,
+				Event:         toCloudEvent(queueName, res),
+			},
+		}, nil
+	}
+
+	message := ReceivedMessage{QueueMessage: res}
+
+	// Decode binary payloads to JSON with the schema they were written with
+	if schemaID := schema.StampedID(res.Attributes); receiveMessageInput.Decode && schemaID != "" {
+		decoded, err := schema.Default().Decode(ctx, schemaID, res.Payload())
+		if err != nil {
+			logging.FromContext(ctx).Warn("could not decode message", slog.String("schema_id", schemaID), slog.Any("error", err))
+		} else {
+			message.Decoded = decoded
+		}
+	}
+
+	return &Response{
+		Ok: true,
+		Response: message,
+	}, nil
+}
+
+func DeleteMessage(ctx context.Context, queueName string, deleteMessageInput DeleteMessageInput) (*Response, error) {
+	res, err := queue.DeleteMessage(ctx, queueName, deleteMessageInput.ReceiptHandle)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not delete message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func ChangeMessageVisibility(ctx context.Context, queueName string, changeMessageVisibilityInput ChangeMessageVisibilityInput) (*Response, error) {
+	res, err := queue.ConfigureVisibilityTimeout(ctx, queueName, changeMessageVisibilityInput.ReceiptHandle, changeMessageVisibilityInput.VisibilityTimeout)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not change message visibility", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
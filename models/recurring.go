@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/scheduler"
+	"pub-sub-service/settings"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+)
+
+// RecurringInput describes a recurring schedule, shared with the client.
+type RecurringInput = api.RecurringInput
+
+func ListRecurring(ctx context.Context) (*Response, error) {
+	res, err := scheduler.Default().ListRecurring(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list recurring schedules", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetRecurring(ctx context.Context, name string) (*Response, error) {
+	res, err := scheduler.Default().GetRecurring(ctx, name)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not get recurring schedule", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+// PutRecurring creates or replaces a recurring schedule, reporting whether
+// it was created. Topic names are resolved to ARNs when the schedule is
+// saved, and schedules whose runs could never be delivered, such as those
+// publishing to a topic that only accepts CloudEvents, are rejected with
+// scheduler.ErrInvalidSchedule.
+func PutRecurring(ctx context.Context, name string, recurringInput RecurringInput) (*Response, bool, error) {
+	if err := queue.ValidateAttributes(recurringInput.Attributes, nil); err != nil {
+		logging.FromContext(ctx).Warn("invalid recurring schedule attributes", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, false, fmt.Errorf("%w: %w", scheduler.ErrInvalidSchedule, err)
+	}
+
+	recurring := scheduler.Recurring{
+		Name:        name,
+		Cron:        recurringInput.Cron,
+		TimeZone:    recurringInput.TimeZone,
+		Kind:        scheduler.KindQueue,
+		Target:      recurringInput.Queue,
+		Template:    recurringInput.Template,
+		ContentType: recurringInput.ContentType,
+		Subject:     recurringInput.Subject,
+		Attributes:  recurringInput.Attributes,
+		MissedRuns:  recurringInput.MissedRuns,
+		Paused:      recurringInput.Paused,
+	}
+
+	if recurringInput.Topic != "" {
+		topicARN, err := notification.ResolveTopicARN(ctx, recurringInput.Topic)
+		if err != nil {
+			logging.FromContext(ctx).Warn("could not resolve topic", slog.String("topic", recurringInput.Topic), slog.Any("error", err))
+			return &Response{
+				Ok: false,
+				Response: nil,
+			}, false, err
+		}
+		recurring.Kind = scheduler.KindTopic
+		recurring.Target = topicARN
+
+		topicSettings, err := settings.GetTopic(ctx, topicARN)
+		if err != nil {
+			logging.FromContext(ctx).Error("could not get topic settings", slog.Any("error", err))
+			return &Response{
+				Ok: false,
+				Response: nil,
+			}, false, err
+		}
+		// Runs publish plain messages, which such a topic rejects
+		if topicSettings.CloudEvents {
+			logging.FromContext(ctx).Warn("recurring schedule rejected by CloudEvents topic", slog.String("topic", topicARN))
+			return &Response{
+				Ok: false,
+				Response: nil,
+			}, false, fmt.Errorf("%w: %w", scheduler.ErrInvalidSchedule, ErrCloudEventRequired)
+		}
+	}
+
+	res, created, err := scheduler.Default().PutRecurring(ctx, recurring)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not save recurring schedule", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, false, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, created, nil
+}
+
+func DeleteRecurring(ctx context.Context, name string) (*Response, error) {
+	err := scheduler.Default().DeleteRecurring(ctx, name)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not delete recurring schedule", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: nil,
+	}, nil
+}
+
+func RecurringHistory(ctx context.Context, name string, limit int) (*Response, error) {
+	res, err := scheduler.Default().History(ctx, name, limit)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not get recurring schedule history", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"pub-sub-service/rpc"
+	queue "pub-sub-service/sqs"
+	"time"
+)
+
+// Bounds of how long a request waits for its reply
+const (
+	DefaultRequestTimeout = 10 * time.Second
+	MaxRequestTimeout     = 60 * time.Second
+)
+
+// PublishRequest publishes a request to a topic, with a CorrelationId and a
+// ReplyTo queue, and waits up to timeout for the reply.
+func PublishRequest(ctx context.Context, topicARN string, message PublishMessageInput, timeout time.Duration) (*Response, error) {
+	reply, err := rpc.Default().Request(ctx, timeout, func(ctx context.Context, attributes map[string]string) error {
+		_, err := publishMessage(ctx, topicARN, message, attributes)
+		return err
+	})
+	return replyResponse(reply, err)
+}
+
+// SendRequest sends a request to a queue, with a CorrelationId and a ReplyTo
+// queue, and waits up to timeout for the reply.
+func SendRequest(ctx context.Context, queueName string, sendMessageInput SendMessageInput, timeout time.Duration) (*Response, error) {
+	reply, err := rpc.Default().Request(ctx, timeout, func(ctx context.Context, attributes map[string]string) error {
+		sendMessageInput.CorrelationID = attributes[rpc.CorrelationIDAttribute]
+
+		_, err := sendMessage(ctx, queueName, sendMessageInput, attributes[rpc.ReplyToAttribute])
+		return err
+	})
+	return replyResponse(reply, err)
+}
+
+func replyResponse(reply *queue.ReceivedMessage, err error) (*Response, error) {
+	if err != nil {
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: ReceivedMessage{QueueMessage: reply},
+	}, nil
+}
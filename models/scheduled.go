@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pub-sub-service/logging"
+	"pub-sub-service/scheduler"
+	"pub-sub-service/schema"
+	"pub-sub-service/settings"
+	queue "pub-sub-service/sqs"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// deliveryTime returns when a message with the given delay is due.
+func deliveryTime(deliverAt *time.Time, delaySeconds int64) time.Time {
+	if deliverAt != nil {
+		return *deliverAt
+	}
+	return time.Now().Add(time.Duration(delaySeconds) * time.Second)
+}
+
+// SchedulePublish holds a message for publishing to a topic after its delay.
+// The message is checked against the topic settings and schema now, so that
+// it is not accepted only to fail when it is due.
+func SchedulePublish(ctx context.Context, topicARN string, message PublishMessageInput) (*Response, error) {
+	deliverAt := deliveryTime(message.DeliverAt, message.DelaySeconds)
+	message.DeliverAt = nil
+	message.DelaySeconds = 0
+
+	body := []byte(message.Message)
+	if message.Data != nil {
+		body = message.Data
+	}
+
+	topicSettings, err := settings.GetTopic(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get topic settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+	if topicSettings.CloudEvents {
+		logging.FromContext(ctx).Warn("message rejected by CloudEvents topic", slog.String("topic", topicARN))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, ErrCloudEventRequired
+	}
+	if _, err := schema.Default().Validate(ctx, topicARN, body); err != nil {
+		logging.FromContext(ctx).Warn("message rejected by topic schema", slog.String("topic", topicARN), slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	res, err := scheduler.Default().Schedule(ctx, scheduler.KindTopic, topicARN, deliverAt, message, topicSettings.Encrypt)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not schedule message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+// ScheduleSend holds a message for sending to a queue after its delay. The
+// queue must exist and the message attributes be valid now, so that the
+// message is not accepted only to fail when it is due.
+func ScheduleSend(ctx context.Context, queueName string, sendMessageInput SendMessageInput) (*Response, error) {
+	deliverAt := deliveryTime(sendMessageInput.DeliverAt, sendMessageInput.DelaySeconds)
+	sendMessageInput.DeliverAt = nil
+	sendMessageInput.DelaySeconds = 0
+
+	if err := queue.ValidateAttributes(sendMessageInput.Attributes, sendMessageInput.BinaryAttributes); err != nil {
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	queueSettings, err := settings.GetQueue(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	if _, err := queue.GetQueueURL(ctx, queueName); err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == sqs.ErrCodeQueueDoesNotExist {
+			err = fmt.Errorf("%w: %s", queue.ErrQueueNotFound, queueName)
+		}
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	res, err := scheduler.Default().Schedule(ctx, scheduler.KindQueue, queueName, deliverAt, sendMessageInput, queueSettings.Encrypt)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not schedule message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func ListScheduled(ctx context.Context) (*Response, error) {
+	res, err := scheduler.Default().List(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list scheduled messages", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetScheduled(ctx context.Context, id string) (*Response, error) {
+	res, err := scheduler.Default().Get(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not get scheduled message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func CancelScheduled(ctx context.Context, id string) (*Response, error) {
+	err := scheduler.Default().Cancel(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not cancel scheduled message", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: nil,
+	}, nil
+}
+
+// DispatchScheduled publishes or sends a scheduled message that is due. It
+// is the scheduler.DispatchFunc run by the service.
+func DispatchScheduled(ctx context.Context, message *scheduler.Message) error {
+	request, err := scheduler.Plaintext(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	switch message.Kind {
+	case scheduler.KindTopic:
+		var input PublishMessageInput
+		if err := json.Unmarshal(request, &input); err != nil {
+			return err
+		}
+		_, err := PublishMessageToAllTopicSubscribers(ctx, message.Target, input)
+		return err
+	case scheduler.KindQueue:
+		var input SendMessageInput
+		if err := json.Unmarshal(request, &input); err != nil {
+			return err
+		}
+		_, err := SendMessage(ctx, message.Target, input)
+		return err
+	default:
+		return fmt.Errorf("unknown scheduled message kind %q", message.Kind)
+	}
+}
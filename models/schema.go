@@ -0,0 +1,108 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/schema"
+)
+
+type (
+	RegisterSchemaInput = api.RegisterSchemaInput
+	SchemaConfigInput   = api.SchemaConfigInput
+)
+
+func ListSchemas(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := schema.Default().List(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list schemas", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func RegisterSchema(ctx context.Context, topicARN string, registerSchemaInput RegisterSchemaInput) (*Response, error) {
+	res, err := schema.Default().Register(ctx, topicARN, registerSchemaInput.Format, registerSchemaInput.Definition)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not register schema", slog.String("topic", topicARN), slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetSchema(ctx context.Context, topicARN string, version int) (*Response, error) {
+	res, err := schema.Default().Get(ctx, topicARN, version)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not get schema", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func GetSchemaConfig(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := schema.Default().Config(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get schema config", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetSchemaConfig(ctx context.Context, topicARN string, schemaConfigInput SchemaConfigInput) (*Response, error) {
+	config, err := schema.Default().Config(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get schema config", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	if schemaConfigInput.Compatibility != "" {
+		config.Compatibility = schemaConfigInput.Compatibility
+	}
+	config.ActiveVersion = schemaConfigInput.ActiveVersion
+
+	err = schema.Default().SetConfig(ctx, topicARN, config)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not set schema config", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: config,
+	}, nil
+}
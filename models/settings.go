@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/envelope"
+	"pub-sub-service/logging"
+	"pub-sub-service/settings"
+)
+
+// TopicSettingsInput and QueueSettingsInput update the settings that are
+// set, leaving the others unchanged.
+type (
+	TopicSettingsInput = api.TopicSettingsInput
+	QueueSettingsInput = api.QueueSettingsInput
+)
+
+func GetTopicSettings(ctx context.Context, topicARN string) (*Response, error) {
+	res, err := settings.GetTopic(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get topic settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetTopicSettings(ctx context.Context, topicARN string, topicSettingsInput TopicSettingsInput) (*Response, error) {
+	topicSettings, err := settings.GetTopic(ctx, topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get topic settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	if topicSettingsInput.CloudEvents != nil {
+		topicSettings.CloudEvents = *topicSettingsInput.CloudEvents
+	}
+	if topicSettingsInput.Encrypt != nil {
+		topicSettings.Encrypt = *topicSettingsInput.Encrypt
+	}
+
+	// Opting in without a keyring would make every publish fail
+	if topicSettings.Encrypt && envelope.Default() == nil {
+		logging.FromContext(ctx).Warn("could not enable topic encryption", slog.Any("error", envelope.ErrNotConfigured))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, envelope.ErrNotConfigured
+	}
+
+	err = settings.SetTopic(ctx, topicARN, topicSettings)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not set topic settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: topicSettings,
+	}, nil
+}
+
+func GetQueueSettings(ctx context.Context, queueName string) (*Response, error) {
+	res, err := settings.GetQueue(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func SetQueueSettings(ctx context.Context, queueName string, queueSettingsInput QueueSettingsInput) (*Response, error) {
+	queueSettings, err := settings.GetQueue(ctx, queueName)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not get queue settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	if queueSettingsInput.Encrypt != nil {
+		queueSettings.Encrypt = *queueSettingsInput.Encrypt
+	}
+
+	if queueSettings.Encrypt && envelope.Default() == nil {
+		logging.FromContext(ctx).Warn("could not enable queue encryption", slog.Any("error", envelope.ErrNotConfigured))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, envelope.ErrNotConfigured
+	}
+
+	err = settings.SetQueue(ctx, queueName, queueSettings)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not set queue settings", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: queueSettings,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	notification "pub-sub-service/sns"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// SubscribeInput subscribes either an email address or a queue to a topic,
+// and Subscription is a topic subscription as returned by the /v1 routes.
+type (
+	SubscribeInput = api.SubscribeInput
+	Subscription   = api.Subscription
+)
+
+func newSubscription(arn, protocol, endpoint string) Subscription {
+	subscription := Subscription{ARN: arn, Protocol: protocol, Endpoint: endpoint}
+	if strings.HasPrefix(arn, "arn:") {
+		subscription.ID = arn[strings.LastIndex(arn, ":")+1:]
+	} else {
+		subscription.Pending = true
+	}
+	return subscription
+}
+
+// SubscriptionARN returns the ARN of a subscription to a topic from its ID.
+func SubscriptionARN(topicARN, subscriptionID string) string {
+	return topicARN + ":" + subscriptionID
+}
+
+func ListTopicSubscriptions(ctx context.Context, topicARN string) (*Response, error) {
+	subscriptions, err := notification.ListSubscriptions(ctx, &topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not list subscriptions to topic", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	res := make([]Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		res = append(res, newSubscription(
+			aws.StringValue(subscription.SubscriptionArn),
+			aws.StringValue(subscription.Protocol),
+			aws.StringValue(subscription.Endpoint),
+		))
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
+
+func Subscribe(ctx context.Context, topicARN string, subscribeInput SubscribeInput) (*Response, error) {
+	var subscription Subscription
+	if subscribeInput.QueueName != "" {
+		arn, err := notification.SubscribeQueueToTopicWithAttributes(ctx, subscribeInput.QueueName, topicARN, subscribeInput.Attributes)
+		if err != nil {
+			logging.FromContext(ctx).Error("could not subscribe queue to topic", slog.Any("error", err))
+			return &Response{
+				Ok: false,
+				Response: nil,
+			}, err
+		}
+		subscription = newSubscription(arn, "sqs", subscribeInput.QueueName)
+	} else {
+		arn, err := notification.Subscribe(ctx, topicARN, "email", subscribeInput.Email, subscribeInput.Attributes)
+		if err != nil {
+			logging.FromContext(ctx).Error("could not subscribe email to topic", slog.Any("error", err))
+			return &Response{
+				Ok: false,
+				Response: nil,
+			}, err
+		}
+		subscription = newSubscription(arn, "email", subscribeInput.Email)
+	}
+
+	return &Response{
+		Ok: true,
+		Response: subscription,
+	}, nil
+}
+
+func Unsubscribe(ctx context.Context, topicARN, subscriptionID string) (*Response, error) {
+	subscriptionARN := SubscriptionARN(topicARN, subscriptionID)
+
+	res, err := notification.UnsubscribeFromTopic(ctx, &subscriptionARN, &topicARN)
+	if err != nil {
+		logging.FromContext(ctx).Error("could not unsubscribe from topic", slog.Any("error", err))
+		return &Response{
+			Ok: false,
+			Response: nil,
+		}, err
+	}
+
+	return &Response{
+		Ok: true,
+		Response: res,
+	}, nil
+}
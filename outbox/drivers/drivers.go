@@ -0,0 +1,6 @@
+// Package drivers registers the database/sql drivers the service's outbox
+// relay supports. Import it for its side effects.
+package drivers
+
+// The outbox relay reaches Postgres through lib/pq, which is pure Go
+import _ "github.com/lib/pq"
@@ -0,0 +1,6 @@
+//go:build cgo
+
+package drivers
+
+// SQLite outboxes need cgo, so builds without it relay Postgres only
+import _ "github.com/mattn/go-sqlite3"
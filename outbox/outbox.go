@@ -0,0 +1,204 @@
+// Package outbox relays messages that applications write to an outbox table
+// in their own database, in the same transaction as the changes they
+// announce, so that a message is published if and only if the changes are
+// committed. The relay reads pending rows in order, publishes or sends them
+// and marks them sent; rows that keep failing are marked failed instead of
+// holding back the rows after them forever.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrPoison marks a dispatch error that retrying cannot fix, such as a row
+// whose payload is not a valid request. The row is failed at once.
+var ErrPoison = errors.New("outbox row cannot be delivered")
+
+// Kinds of outbox row targets
+const (
+	KindTopic = "topic"
+	KindQueue = "queue"
+)
+
+// Statuses of outbox rows
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// Drivers supported for outbox tables. The package does not register them;
+// applications import the one they use, as the service does.
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// Row is a message in the outbox. Target is a topic ARN or name, or a queue
+// name, and Payload the publish or send request, as given to the API.
+type Row struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	Target    string          `json:"target"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts,omitempty"`
+	RetryAt   *time.Time      `json:"retryAt,omitempty"`
+	LastError string          `json:"lastError,omitempty"`
+	SentAt    *time.Time      `json:"sentAt,omitempty"`
+}
+
+// Execer runs statements, such as the *sql.Tx that writes the changes a
+// message announces.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Table is an outbox table in a database reached through Driver.
+type Table struct {
+	Driver string
+	Name   string
+}
+
+func NewTable(driver, name string) (Table, error) {
+	if driver != DriverSQLite && driver != DriverPostgres {
+		return Table{}, fmt.Errorf("unsupported outbox driver %q", driver)
+	}
+	if !tableNamePattern.MatchString(name) {
+		return Table{}, fmt.Errorf("invalid outbox table name %q", name)
+	}
+	return Table{Driver: driver, Name: name}, nil
+}
+
+// Create creates the table and its index if they do not exist.
+func (t Table) Create(ctx context.Context, db Execer) error {
+	id, timestamp := "INTEGER PRIMARY KEY AUTOINCREMENT", "TIMESTAMP"
+	if t.Driver == DriverPostgres {
+		id, timestamp = "BIGSERIAL PRIMARY KEY", "TIMESTAMPTZ"
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ` + t.Name + ` (
+			id ` + id + `,
+			kind TEXT NOT NULL,
+			target TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			created_at ` + timestamp + ` NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			retry_at ` + timestamp + `,
+			last_error TEXT,
+			sent_at ` + timestamp + `
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + t.Name + `_status_id ON ` + t.Name + ` (status, id)`,
+		`CREATE INDEX IF NOT EXISTS ` + t.Name + `_target ON ` + t.Name + ` (kind, target, status)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enqueue adds a message to the outbox through tx, the transaction that
+// writes the changes it announces. payload is the publish or send request,
+// e.g. a api.PublishMessageInput.
+func (t Table) Enqueue(ctx context.Context, tx Execer, kind, target string, payload any) error {
+	if kind != KindTopic && kind != KindQueue {
+		return fmt.Errorf("unknown outbox row kind %q", kind)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, t.bind(`INSERT INTO `+t.Name+` (kind, target, payload, created_at) VALUES (?, ?, ?, ?)`),
+		kind, target, string(data), time.Now().UTC())
+	return err
+}
+
+// pending returns up to limit pending rows in order, leaving out the targets
+// held back by a row waiting for its retry at now, so that they cannot fill
+// the batch and starve the others.
+func (t Table) pending(ctx context.Context, db *sql.DB, now time.Time, limit int) ([]*Row, error) {
+	rows, err := db.QueryContext(ctx, t.bind(`SELECT id, kind, target, payload, created_at, attempts, retry_at, last_error
+		FROM `+t.Name+` o WHERE status = ? AND NOT EXISTS (
+			SELECT 1 FROM `+t.Name+` h
+			WHERE h.kind = o.kind AND h.target = o.target AND h.status = ? AND h.retry_at > ?
+		) ORDER BY id LIMIT ?`), StatusPending, StatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []*Row
+	for rows.Next() {
+		var (
+			row       Row
+			payload   string
+			retryAt   sql.NullTime
+			lastError sql.NullString
+		)
+		if err := rows.Scan(&row.ID, &row.Kind, &row.Target, &payload, &row.CreatedAt, &row.Attempts, &retryAt, &lastError); err != nil {
+			return nil, err
+		}
+		row.Payload = json.RawMessage(payload)
+		row.Status = StatusPending
+		if retryAt.Valid {
+			row.RetryAt = &retryAt.Time
+		}
+		row.LastError = lastError.String
+		pending = append(pending, &row)
+	}
+	return pending, rows.Err()
+}
+
+func (t Table) markSent(ctx context.Context, db *sql.DB, row *Row) error {
+	_, err := db.ExecContext(ctx, t.bind(`UPDATE `+t.Name+` SET status = ?, attempts = ?, sent_at = ?, retry_at = NULL WHERE id = ?`),
+		StatusSent, row.Attempts, time.Now().UTC(), row.ID)
+	return err
+}
+
+func (t Table) markRetry(ctx context.Context, db *sql.DB, row *Row) error {
+	_, err := db.ExecContext(ctx, t.bind(`UPDATE `+t.Name+` SET attempts = ?, retry_at = ?, last_error = ? WHERE id = ?`),
+		row.Attempts, *row.RetryAt, row.LastError, row.ID)
+	return err
+}
+
+func (t Table) markFailed(ctx context.Context, db *sql.DB, row *Row) error {
+	_, err := db.ExecContext(ctx, t.bind(`UPDATE `+t.Name+` SET status = ?, attempts = ?, retry_at = NULL, last_error = ? WHERE id = ?`),
+		StatusFailed, row.Attempts, row.LastError, row.ID)
+	return err
+}
+
+// bind rewrites ? placeholders as $1, $2, ... for Postgres
+func (t Table) bind(query string) string {
+	if t.Driver != DriverPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
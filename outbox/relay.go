@@ -0,0 +1,275 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+	"pub-sub-service/store"
+	"pub-sub-service/worker"
+)
+
+const (
+	leaderPrefix = "outbox/leader/"
+
+	defaultBatchSize   = 100
+	defaultMaxAttempts = 10
+
+	// maxRetryDelay caps the backoff between attempts at a row
+	maxRetryDelay = 5 * time.Minute
+	minLeaseTTL   = 15 * time.Second
+)
+
+// DispatchFunc publishes or sends the message in a row.
+type DispatchFunc func(ctx context.Context, row *Row) error
+
+// Options configures a Relay.
+type Options struct {
+	// Store holds the leader lease; store.Default() when nil
+	Store store.Store
+	// BatchSize is how many pending rows are read at a time
+	BatchSize int
+	// MaxAttempts is how often a row is tried before it is failed
+	MaxAttempts int
+}
+
+// Relay publishes the pending rows of an outbox table. Rows for the same
+// topic or queue are delivered in order: a row waiting for a retry holds
+// back the rows after it for that target, but not other targets. One
+// instance relays at a time, through a leader lease in the store; a relay
+// that stops between delivering a row and marking it sent delivers it again,
+// so consumers should deduplicate.
+type Relay struct {
+	db          *sql.DB
+	table       Table
+	lease       *worker.Lease
+	batchSize   int
+	maxAttempts int
+}
+
+func New(db *sql.DB, table Table, options Options) *Relay {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "outbox"
+	}
+
+	s := options.Store
+	if s == nil {
+		s = store.Default()
+	}
+
+	r := &Relay{
+		db:          db,
+		table:       table,
+		lease:       worker.NewLease(s, leaderPrefix+table.Name, hostname+"-"+hex.EncodeToString(suffix)),
+		batchSize:   options.BatchSize,
+		maxAttempts: options.MaxAttempts,
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+	if r.maxAttempts <= 0 {
+		r.maxAttempts = defaultMaxAttempts
+	}
+	return r
+}
+
+var (
+	mu           sync.Mutex
+	defaultRelay *Relay
+)
+
+// Setup builds the default relay from OUTBOX_DSN, which enables it, and
+// OUTBOX_DRIVER ("sqlite3" or "postgres"), OUTBOX_TABLE (default "outbox"),
+// OUTBOX_BATCH_SIZE (default 100) and OUTBOX_MAX_ATTEMPTS (default 10).
+func Setup() error {
+	dsn := os.Getenv("OUTBOX_DSN")
+	if dsn == "" {
+		return nil
+	}
+
+	driver := os.Getenv("OUTBOX_DRIVER")
+	if driver == "" {
+		return errors.New("OUTBOX_DRIVER is required with OUTBOX_DSN")
+	}
+	name := os.Getenv("OUTBOX_TABLE")
+	if name == "" {
+		name = "outbox"
+	}
+	table, err := NewTable(driver, name)
+	if err != nil {
+		return err
+	}
+
+	var options Options
+	if value := os.Getenv("OUTBOX_BATCH_SIZE"); value != "" {
+		options.BatchSize, err = strconv.Atoi(value)
+		if err != nil || options.BatchSize <= 0 {
+			return fmt.Errorf("invalid OUTBOX_BATCH_SIZE %q", value)
+		}
+	}
+	if value := os.Getenv("OUTBOX_MAX_ATTEMPTS"); value != "" {
+		options.MaxAttempts, err = strconv.Atoi(value)
+		if err != nil || options.MaxAttempts <= 0 {
+			return fmt.Errorf("invalid OUTBOX_MAX_ATTEMPTS %q", value)
+		}
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultRelay = New(db, table, options)
+	return nil
+}
+
+// Default returns the relay configured by Setup, or nil if there is none.
+func Default() *Relay {
+	mu.Lock()
+	defer mu.Unlock()
+	return defaultRelay
+}
+
+// Close closes the relay's database.
+func (r *Relay) Close() error {
+	return r.db.Close()
+}
+
+// Ping verifies that the relay's database is reachable.
+func (r *Relay) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// Run creates the outbox table if needed and relays pending rows on each
+// interval, while this instance holds the leader lease, until ctx is
+// cancelled.
+func (r *Relay) Run(ctx context.Context, interval time.Duration, dispatch DispatchFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	leaseTTL := max(3*interval, minLeaseTTL)
+	created := false
+
+	for {
+		if !created {
+			err := r.table.Create(ctx, r.db)
+			if err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Warn("unable to create outbox table", slog.String("table", r.table.Name), slog.Any("error", err))
+			}
+			created = err == nil
+		}
+
+		// Full batches are followed by the next one straight away
+		for created && ctx.Err() == nil {
+			delivered, err := r.RelayPending(ctx, leaseTTL, dispatch)
+			if err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Warn("unable to relay outbox", slog.String("table", r.table.Name), slog.Any("error", err))
+			}
+			if err != nil || delivered < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending delivers a batch of pending rows, if this instance holds the
+// leader lease, and reports how many were sent or failed. The batch stops if
+// the lease is lost.
+func (r *Relay) RelayPending(ctx context.Context, leaseTTL time.Duration, dispatch DispatchFunc) (int, error) {
+	leader, err := r.lease.Acquire(ctx, leaseTTL)
+	if err != nil || !leader {
+		return 0, err
+	}
+
+	now := time.Now()
+	rows, err := r.table.pending(ctx, r.db, now, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	held := map[string]bool{}
+	delivered := 0
+	for _, row := range rows {
+		if ctx.Err() != nil || !r.lease.Held(ctx, leaseTTL) {
+			break
+		}
+
+		target := row.Kind + ":" + row.Target
+		if held[target] {
+			continue
+		}
+		if row.RetryAt != nil && row.RetryAt.After(now) {
+			held[target] = true
+			continue
+		}
+
+		if r.deliver(ctx, row, dispatch) {
+			delivered++
+		} else {
+			held[target] = true
+		}
+	}
+	return delivered, nil
+}
+
+// deliver dispatches a row and records the outcome, reporting whether the
+// row is done with, sent or failed, so that later rows for its target may
+// follow.
+func (r *Relay) deliver(ctx context.Context, row *Row, dispatch DispatchFunc) bool {
+	logger := logging.FromContext(ctx).With(slog.Int64("id", row.ID), slog.String("kind", row.Kind), slog.String("target", row.Target))
+
+	row.Attempts++
+	err := dispatch(ctx, row)
+	if err == nil {
+		metrics.ObserveOutboxRow(row.Kind, "sent")
+		if err := r.table.markSent(ctx, r.db, row); err != nil {
+			logger.Error("unable to mark outbox row sent", slog.Any("error", err))
+			return false
+		}
+		return true
+	}
+
+	row.LastError = err.Error()
+
+	if errors.Is(err, ErrPoison) || row.Attempts >= r.maxAttempts {
+		metrics.ObserveOutboxRow(row.Kind, "failed")
+		logger.Error("outbox row failed", slog.Int("attempts", row.Attempts), slog.Any("error", err))
+		if err := r.table.markFailed(ctx, r.db, row); err != nil {
+			logger.Error("unable to mark outbox row failed", slog.Any("error", err))
+			return false
+		}
+		return true
+	}
+
+	metrics.ObserveOutboxRow(row.Kind, "retried")
+	logger.Warn("unable to deliver outbox row, will retry", slog.Int("attempts", row.Attempts), slog.Any("error", err))
+
+	retryAt := time.Now().UTC().Add(worker.RetryDelay(row.Attempts, maxRetryDelay))
+	row.RetryAt = &retryAt
+	if err := r.table.markRetry(ctx, r.db, row); err != nil {
+		logger.Error("unable to store outbox row retry", slog.Any("error", err))
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+//go:build cgo
+
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"pub-sub-service/store"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestRelay(t *testing.T, options Options) (*Relay, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open(DriverSQLite, "file:"+filepath.Join(t.TempDir(), "outbox.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	table, err := NewTable(DriverSQLite, "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Create(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	if options.Store == nil {
+		options.Store = store.NewMemory()
+	}
+	return New(db, table, options), db
+}
+
+func enqueue(t *testing.T, relay *Relay, target string, body string) {
+	t.Helper()
+
+	err := relay.table.Enqueue(context.Background(), relay.db, KindQueue, target, map[string]string{"body": body})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rowByID(t *testing.T, db *sql.DB, id int64) Row {
+	t.Helper()
+
+	var (
+		row       Row
+		retryAt   sql.NullTime
+		lastError sql.NullString
+	)
+	err := db.QueryRow(`SELECT id, status, attempts, retry_at, last_error FROM outbox WHERE id = ?`, id).
+		Scan(&row.ID, &row.Status, &row.Attempts, &retryAt, &lastError)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retryAt.Valid {
+		row.RetryAt = &retryAt.Time
+	}
+	row.LastError = lastError.String
+	return row
+}
+
+// recorder dispatches rows by recording their IDs, failing those in fail
+type recorder struct {
+	delivered []int64
+	fail      map[int64]error
+}
+
+func (r *recorder) dispatch(ctx context.Context, row *Row) error {
+	if err := r.fail[row.ID]; err != nil {
+		return err
+	}
+	r.delivered = append(r.delivered, row.ID)
+	return nil
+}
+
+func TestRelayPendingDeliversInOrder(t *testing.T) {
+	relay, db := newTestRelay(t, Options{})
+	for _, target := range []string{"a", "b", "a", "b", "a"} {
+		enqueue(t, relay, target, target)
+	}
+
+	var rec recorder
+	delivered, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 5 {
+		t.Fatalf("delivered %d rows, want 5", delivered)
+	}
+	if want := []int64{1, 2, 3, 4, 5}; !slices.Equal(rec.delivered, want) {
+		t.Fatalf("delivered %v, want %v", rec.delivered, want)
+	}
+	for id := int64(1); id <= 5; id++ {
+		if row := rowByID(t, db, id); row.Status != StatusSent || row.Attempts != 1 {
+			t.Errorf("row %d is %s after %d attempts, want sent after 1", id, row.Status, row.Attempts)
+		}
+	}
+}
+
+func TestRelayPendingRetryHoldsBackTarget(t *testing.T) {
+	relay, db := newTestRelay(t, Options{})
+	enqueue(t, relay, "a", "first")
+	enqueue(t, relay, "b", "other")
+	enqueue(t, relay, "a", "second")
+
+	rec := recorder{fail: map[int64]error{1: errors.New("unavailable")}}
+	if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{2}; !slices.Equal(rec.delivered, want) {
+		t.Fatalf("delivered %v, want %v", rec.delivered, want)
+	}
+
+	row := rowByID(t, db, 1)
+	if row.Status != StatusPending || row.Attempts != 1 || row.RetryAt == nil || row.LastError != "unavailable" {
+		t.Fatalf("failed row is %+v, want pending with a retry", row)
+	}
+	if row := rowByID(t, db, 3); row.Status != StatusPending || row.Attempts != 0 {
+		t.Fatalf("row behind the failed one is %+v, want untried", row)
+	}
+
+	// Nothing is delivered for the target until the retry is due
+	rec = recorder{}
+	if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.delivered) != 0 {
+		t.Fatalf("delivered %v before the retry was due", rec.delivered)
+	}
+
+	if _, err := db.Exec(`UPDATE outbox SET retry_at = ? WHERE id = 1`, time.Now().UTC().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{1, 3}; !slices.Equal(rec.delivered, want) {
+		t.Fatalf("delivered %v, want %v", rec.delivered, want)
+	}
+	if row := rowByID(t, db, 1); row.Status != StatusSent || row.Attempts != 2 || row.RetryAt != nil {
+		t.Fatalf("retried row is %+v, want sent after 2 attempts", row)
+	}
+}
+
+func TestRelayPendingHeldTargetDoesNotStarveOthers(t *testing.T) {
+	relay, _ := newTestRelay(t, Options{BatchSize: 2})
+	enqueue(t, relay, "a", "first")
+	enqueue(t, relay, "a", "second")
+	enqueue(t, relay, "a", "third")
+	enqueue(t, relay, "b", "other")
+
+	rec := recorder{fail: map[int64]error{1: errors.New("unavailable")}}
+	if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{4}; !slices.Equal(rec.delivered, want) {
+		t.Fatalf("delivered %v, want %v", rec.delivered, want)
+	}
+}
+
+func TestRelayPendingFailsPoisonRows(t *testing.T) {
+	relay, db := newTestRelay(t, Options{})
+	enqueue(t, relay, "a", "poison")
+	enqueue(t, relay, "a", "next")
+
+	rec := recorder{fail: map[int64]error{1: fmt.Errorf("%w: invalid payload", ErrPoison)}}
+	delivered, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 2 {
+		t.Fatalf("delivered %d rows, want the failed and the next one", delivered)
+	}
+	if want := []int64{2}; !slices.Equal(rec.delivered, want) {
+		t.Fatalf("delivered %v, want %v", rec.delivered, want)
+	}
+
+	row := rowByID(t, db, 1)
+	if row.Status != StatusFailed || row.Attempts != 1 || row.RetryAt != nil || row.LastError == "" {
+		t.Fatalf("poison row is %+v, want failed with its error", row)
+	}
+}
+
+func TestRelayPendingFailsRowsAfterMaxAttempts(t *testing.T) {
+	relay, db := newTestRelay(t, Options{MaxAttempts: 2})
+	enqueue(t, relay, "a", "failing")
+
+	rec := recorder{fail: map[int64]error{1: errors.New("unavailable")}}
+	for range 2 {
+		if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`UPDATE outbox SET retry_at = ? WHERE id = 1`, time.Now().UTC().Add(-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if row := rowByID(t, db, 1); row.Status != StatusFailed || row.Attempts != 2 {
+		t.Fatalf("row is %+v, want failed after 2 attempts", row)
+	}
+}
+
+func TestRelayPendingOnlyWhileLeading(t *testing.T) {
+	leases := store.NewMemory()
+	relay, _ := newTestRelay(t, Options{Store: leases})
+	enqueue(t, relay, "a", "first")
+
+	// other takes the lease first, so relay leaves the table to it
+	other := New(relay.db, relay.table, Options{Store: leases})
+	var rec recorder
+	if _, err := other.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{1}; !slices.Equal(rec.delivered, want) {
+		t.Fatalf("delivered %v, want %v once", rec.delivered, want)
+	}
+
+	enqueue(t, relay, "a", "second")
+	delivered, err := relay.RelayPending(context.Background(), time.Minute, rec.dispatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 0 {
+		t.Fatalf("relay that is not leading delivered %d rows", delivered)
+	}
+}
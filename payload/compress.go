@@ -0,0 +1,132 @@
+package payload
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ContentEncodingAttribute names the compression applied to a payload.
+const ContentEncodingAttribute = "ContentEncoding"
+
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+var (
+	ErrUnknownCompression = errors.New("unknown compression")
+	ErrTooLarge           = errors.New("decompressed payload too large")
+)
+
+type compressionKey struct{}
+
+// ValidCompression reports whether name is a supported compression.
+func ValidCompression(name string) bool {
+	switch name {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithCompression returns ctx with the compression used by Encode overridden.
+// An empty name keeps the configured default.
+func WithCompression(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, compressionKey{}, name)
+}
+
+// compress compresses payloads of at least the compression threshold, keeping
+// them as they are if compression does not make the message body smaller.
+func compress(ctx context.Context, data []byte, attributes map[string]string) ([]byte, error) {
+	config := currentConfig()
+
+	name := config.compression
+	if override, ok := ctx.Value(compressionKey{}).(string); ok {
+		name = override
+	}
+	if name == CompressionNone || len(data) < config.compressionThreshold {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch name {
+	case CompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case CompressionZstd:
+		var err error
+		if w, err = zstd.NewWriter(&buf); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownCompression, name)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	// Compressed bodies travel base64 encoded, which a payload that would
+	// otherwise go out as it is does not pay for
+	size := buf.Len()
+	if !base64Encoded(ctx, data, attributes) {
+		size = base64.StdEncoding.EncodedLen(size)
+	}
+	if size >= len(data) {
+		return data, nil
+	}
+	attributes[ContentEncodingAttribute] = name
+	return buf.Bytes(), nil
+}
+
+// decompress reverses compress, refusing payloads that expand beyond the
+// configured maximum.
+func decompress(data []byte, attributes map[string]string) ([]byte, error) {
+	var r io.Reader
+	switch encoding := attributes[ContentEncodingAttribute]; encoding {
+	case "":
+		return data, nil
+	case CompressionGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress message: %v", err)
+		}
+		defer gr.Close()
+		r = gr
+	case CompressionZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress message: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unknown content encoding %q", encoding)
+	}
+
+	limit := currentConfig().maxDecompressedSize
+	decompressed, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress message: %v", err)
+	}
+	if len(decompressed) > limit {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrTooLarge, limit)
+	}
+	delete(attributes, ContentEncodingAttribute)
+	return decompressed, nil
+}
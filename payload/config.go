@@ -0,0 +1,96 @@
+package payload
+
+import (
+	"fmt"
+	"os"
+	"pub-sub-service/blob"
+	"strconv"
+	"sync"
+)
+
+const (
+	// DefaultOffloadThreshold leaves room under the 256 KB SNS/SQS limit for
+	// message attributes. It applies only with a blob store shared between
+	// instances; otherwise offloading is off unless configured.
+	DefaultOffloadThreshold = 192 * 1024
+
+	DefaultCompressionThreshold = 1024
+
+	// DefaultMaxDecompressedSize bounds what a compressed payload may expand
+	// to, well above any body the service accepts.
+	DefaultMaxDecompressedSize = 16 * 1024 * 1024
+)
+
+type config struct {
+	offloadThreshold     int
+	compression          string
+	compressionThreshold int
+	maxDecompressedSize  int
+}
+
+var (
+	mu            sync.Mutex
+	defaultConfig = config{
+		compression:          CompressionNone,
+		compressionThreshold: DefaultCompressionThreshold,
+		maxDecompressedSize:  DefaultMaxDecompressedSize,
+	}
+)
+
+// Setup reads the payload settings:
+//   - PAYLOAD_OFFLOAD_THRESHOLD, the message body size in bytes above which
+//     payloads are offloaded to the blob store (0 disables offloading). It
+//     defaults to DefaultOffloadThreshold when the blob store is shared, and
+//     to 0 otherwise, as another instance could not read the blob.
+//   - PAYLOAD_COMPRESSION, the default compression: "none", "gzip" or "zstd".
+//   - PAYLOAD_COMPRESSION_THRESHOLD, the payload size in bytes below which
+//     payloads are not compressed.
+//   - PAYLOAD_MAX_DECOMPRESSED_SIZE, the size in bytes that received payloads
+//     may decompress to.
+func Setup() error {
+	c := defaultConfig
+	if blob.Shared() {
+		c.offloadThreshold = DefaultOffloadThreshold
+	}
+
+	var err error
+	if c.offloadThreshold, err = sizeFromEnv("PAYLOAD_OFFLOAD_THRESHOLD", c.offloadThreshold); err != nil {
+		return err
+	}
+	if c.compressionThreshold, err = sizeFromEnv("PAYLOAD_COMPRESSION_THRESHOLD", c.compressionThreshold); err != nil {
+		return err
+	}
+	if c.maxDecompressedSize, err = sizeFromEnv("PAYLOAD_MAX_DECOMPRESSED_SIZE", c.maxDecompressedSize); err != nil {
+		return err
+	}
+	if value := os.Getenv("PAYLOAD_COMPRESSION"); value != "" {
+		if !ValidCompression(value) {
+			return fmt.Errorf("invalid PAYLOAD_COMPRESSION %q", value)
+		}
+		c.compression = value
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultConfig = c
+	return nil
+}
+
+func currentConfig() config {
+	mu.Lock()
+	defer mu.Unlock()
+	return defaultConfig
+}
+
+func sizeFromEnv(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return size, nil
+}
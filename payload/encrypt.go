@@ -0,0 +1,97 @@
+package payload
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"pub-sub-service/envelope"
+)
+
+// EncryptionAttribute marks encrypted payloads with the data key, wrapped by
+// the master key and base64 encoded, and the ID of the master key, as
+// "<data key>:<key ID>". One attribute carries both, as SQS allows only ten.
+const EncryptionAttribute = "Encryption"
+
+// Message attributes that carried the key ID and data key of messages
+// encrypted by earlier versions, which are still decrypted and which callers
+// still may not set. Since SQS keeps messages for at most 14 days, the
+// fallback is removed in the first release made 14 days or more after the
+// one that introduced EncryptionAttribute.
+const (
+	EncryptionKeyIDAttribute  = "EncryptionKeyId"
+	EncryptedDataKeyAttribute = "EncryptedDataKey"
+)
+
+type encryptKey struct{}
+
+// WithEncryption returns ctx with envelope encryption of payloads by Encode
+// turned on or off.
+func WithEncryption(ctx context.Context, encrypt bool) context.Context {
+	return context.WithValue(ctx, encryptKey{}, encrypt)
+}
+
+// encrypting reports whether Encode encrypts payloads under ctx.
+func encrypting(ctx context.Context) bool {
+	on, _ := ctx.Value(encryptKey{}).(bool)
+	return on
+}
+
+func encrypt(ctx context.Context, data []byte, attributes map[string]string) ([]byte, error) {
+	if !encrypting(ctx) {
+		return data, nil
+	}
+
+	ciphertext, wrapped, keyID, err := envelope.Encrypt(ctx, envelope.Default(), data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt message: %w", err)
+	}
+
+	attributes[EncryptionAttribute] = base64.StdEncoding.EncodeToString(wrapped) + ":" + keyID
+	return ciphertext, nil
+}
+
+// decrypt reverses encrypt.
+func decrypt(ctx context.Context, data []byte, attributes map[string]string) ([]byte, error) {
+	// Base64 has no colons, so the key ID is everything after the first
+	encodedKey, keyID, _ := strings.Cut(attributes[EncryptionAttribute], ":")
+	if keyID == "" {
+		encodedKey, keyID = attributes[EncryptedDataKeyAttribute], attributes[EncryptionKeyIDAttribute]
+	}
+	if keyID == "" {
+		return data, nil
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted data key: %v", err)
+	}
+
+	plaintext, err := envelope.Decrypt(ctx, envelope.Default(), data, wrapped, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt message: %w", err)
+	}
+
+	delete(attributes, EncryptionAttribute)
+	delete(attributes, EncryptionKeyIDAttribute)
+	delete(attributes, EncryptedDataKeyAttribute)
+	return plaintext, nil
+}
+
+// Seal encrypts data kept outside a message, such as a scheduled message
+// waiting in the store, returning the ciphertext and the value of
+// EncryptionAttribute that Open needs.
+func Seal(ctx context.Context, data []byte) ([]byte, string, error) {
+	attributes := map[string]string{}
+	ciphertext, err := encrypt(WithEncryption(ctx, true), data, attributes)
+	if err != nil {
+		return nil, "", err
+	}
+	return ciphertext, attributes[EncryptionAttribute], nil
+}
+
+// Open reverses Seal.
+func Open(ctx context.Context, data []byte, encryption string) ([]byte, error) {
+	return decrypt(ctx, data, map[string]string{EncryptionAttribute: encryption})
+}
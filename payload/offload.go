@@ -0,0 +1,55 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"pub-sub-service/blob"
+)
+
+// BlobAttribute holds the blob store key of a payload that was too large for
+// the message body. The body then carries a pointer for readers that do not
+// know about offloading.
+const BlobAttribute = "PayloadBlob"
+
+type blobPointer struct {
+	PayloadBlob string `json:"payloadBlob"`
+	Size        int    `json:"size"`
+}
+
+// offload stores a body above the threshold in the blob store and returns the
+// pointer to send in its place.
+func offload(ctx context.Context, body string, attributes map[string]string) (string, error) {
+	threshold := currentConfig().offloadThreshold
+	if threshold == 0 || len(body) <= threshold {
+		return body, nil
+	}
+
+	key := blob.NewKey()
+	if err := blob.Default().Put(ctx, key, []byte(body)); err != nil {
+		return "", fmt.Errorf("unable to offload message body: %v", err)
+	}
+	attributes[BlobAttribute] = key
+
+	pointer, err := json.Marshal(blobPointer{PayloadBlob: key, Size: len(body)})
+	if err != nil {
+		return "", err
+	}
+	return string(pointer), nil
+}
+
+// fetch reverses offload, returning the body stored in the blob store.
+func fetch(ctx context.Context, body string, attributes map[string]string) (string, error) {
+	key := attributes[BlobAttribute]
+	if key == "" {
+		return body, nil
+	}
+
+	data, err := blob.Default().Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch offloaded message body %s: %v", key, err)
+	}
+	delete(attributes, BlobAttribute)
+	return string(data), nil
+}
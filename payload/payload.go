@@ -0,0 +1,115 @@
+package payload
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message attributes describing how a payload was turned into a message body.
+const (
+	ContentTypeAttribute  = "ContentType"
+	BodyEncodingAttribute = "BodyEncoding"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeAvro     = "application/avro"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeBinary   = "application/octet-stream"
+)
+
+const EncodingBase64 = "base64"
+
+// reservedAttributes are set by the service to describe how a payload is
+// carried, and are not accepted from callers
+var reservedAttributes = map[string]bool{
+	ContentTypeAttribute:      true,
+	BodyEncodingAttribute:     true,
+	ContentEncodingAttribute:  true,
+	EncryptionAttribute:       true,
+	EncryptionKeyIDAttribute:  true,
+	EncryptedDataKeyAttribute: true,
+	BlobAttribute:             true,
+}
+
+// Reserved reports whether a message attribute is set by the service and may
+// not be given by callers.
+func Reserved(name string) bool {
+	return reservedAttributes[name]
+}
+
+// IsBinary reports whether payloads of a content type are binary and must be
+// base64 encoded to travel in an SNS or SQS message body.
+func IsBinary(contentType string) bool {
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case ContentTypeAvro, "avro/binary", ContentTypeProtobuf, "application/protobuf", ContentTypeBinary:
+		return true
+	default:
+		return false
+	}
+}
+
+// Encode turns a payload into a message body. Payloads are compressed when
+// configured and encrypted when requested with WithEncryption, then binary
+// payloads, as declared by the ContentType attribute
+// or detected from invalid UTF-8, are base64 encoded, and bodies above the
+// offload threshold are replaced by a pointer to the blob store. Attributes
+// are updated with what Decode needs to reverse it.
+func Encode(ctx context.Context, data []byte, attributes map[string]string) (string, error) {
+	data, err := compress(ctx, data, attributes)
+	if err != nil {
+		return "", err
+	}
+
+	data, err = encrypt(ctx, data, attributes)
+	if err != nil {
+		return "", err
+	}
+
+	body := string(data)
+	if attributes[ContentEncodingAttribute] != "" || attributes[EncryptionAttribute] != "" || IsBinary(attributes[ContentTypeAttribute]) || !utf8.Valid(data) {
+		attributes[BodyEncodingAttribute] = EncodingBase64
+		body = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return offload(ctx, body, attributes)
+}
+
+// base64Encoded reports whether Encode base64 encodes data even if it is not
+// compressed: when it is encrypted, binary or not valid UTF-8.
+func base64Encoded(ctx context.Context, data []byte, attributes map[string]string) bool {
+	return encrypting(ctx) || IsBinary(attributes[ContentTypeAttribute]) || !utf8.Valid(data)
+}
+
+// Decode reverses Encode using the attributes the message was sent with, and
+// removes the attributes it consumed.
+func Decode(ctx context.Context, body string, attributes map[string]string) ([]byte, error) {
+	body, err := fetch(ctx, body, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch encoding := attributes[BodyEncodingAttribute]; encoding {
+	case "":
+		data = []byte(body)
+	case EncodingBase64:
+		data, err = base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode base64 message body: %v", err)
+		}
+		delete(attributes, BodyEncodingAttribute)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %q", encoding)
+	}
+
+	data, err = decrypt(ctx, data, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return decompress(data, attributes)
+}
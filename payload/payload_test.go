@@ -0,0 +1,278 @@
+package payload
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"maps"
+	mathrand "math/rand/v2"
+	"strings"
+	"testing"
+
+	"pub-sub-service/blob"
+	"pub-sub-service/envelope"
+)
+
+// setupPayload configures payloads as c, with a local keyring and a blob
+// store in a temporary directory.
+func setupPayload(t *testing.T, c config) {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ENCRYPTION_KEYRING", "local")
+	t.Setenv("ENCRYPTION_LOCAL_KEYS", "test:"+base64.StdEncoding.EncodeToString(key))
+	if err := envelope.Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("BLOB_DIR", t.TempDir())
+	if err := blob.Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.compression == "" {
+		c.compression = CompressionNone
+	}
+	if c.maxDecompressedSize == 0 {
+		c.maxDecompressedSize = DefaultMaxDecompressedSize
+	}
+
+	mu.Lock()
+	previous := defaultConfig
+	defaultConfig = c
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		defaultConfig = previous
+		mu.Unlock()
+	})
+}
+
+// randomText returns deterministic JSON with runs of random base64
+// characters, which compresses to about three quarters of its size: a gain
+// that base64 encoding the compressed body loses again.
+func randomText(size int) []byte {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+	r := mathrand.New(mathrand.NewPCG(1, 2))
+	var b strings.Builder
+	for b.Len() < size {
+		b.WriteString(`{"id":"`)
+		for range 256 {
+			b.WriteByte(alphabet[r.IntN(len(alphabet))])
+		}
+		b.WriteString(`"},`)
+	}
+	return []byte(b.String())
+}
+
+func TestEncodeDecode(t *testing.T) {
+	text := []byte(strings.Repeat(`{"greeting": "hello, world"}`, 100))
+	binary := make([]byte, 4096)
+	if _, err := rand.Read(binary); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		config      config
+		compression string
+		encrypt     bool
+		contentType string
+		data        []byte
+
+		wantCompression string
+		wantBase64      bool
+		wantOffloaded   bool
+	}{
+		{
+			name: "text as it is",
+			data: text,
+		},
+		{
+			name:        "binary content type",
+			contentType: ContentTypeAvro,
+			data:        []byte("\x02\x10hello"),
+			wantBase64:  true,
+		},
+		{
+			name:       "invalid UTF-8",
+			data:       []byte{0xff, 0xfe, 'h', 'i'},
+			wantBase64: true,
+		},
+		{
+			name:            "gzip",
+			config:          config{compression: CompressionGzip, compressionThreshold: 1024},
+			data:            text,
+			wantCompression: CompressionGzip,
+			wantBase64:      true,
+		},
+		{
+			name:            "zstd from the context",
+			config:          config{compressionThreshold: 1024},
+			compression:     CompressionZstd,
+			data:            text,
+			wantCompression: CompressionZstd,
+			wantBase64:      true,
+		},
+		{
+			name:   "below the compression threshold",
+			config: config{compression: CompressionGzip, compressionThreshold: 1 << 20},
+			data:   text,
+		},
+		{
+			name:        "compression turned off by the context",
+			config:      config{compression: CompressionGzip, compressionThreshold: 1024},
+			compression: CompressionNone,
+			data:        text,
+		},
+		{
+			name:        "incompressible",
+			config:      config{compression: CompressionGzip, compressionThreshold: 1024},
+			contentType: ContentTypeBinary,
+			data:        binary,
+			wantBase64:  true,
+		},
+		{
+			name:   "gain lost to base64",
+			config: config{compression: CompressionGzip, compressionThreshold: 1024},
+			data:   randomText(4096),
+		},
+		{
+			name:            "gain kept when base64 encoded anyway",
+			config:          config{compression: CompressionGzip, compressionThreshold: 1024},
+			contentType:     ContentTypeBinary,
+			data:            randomText(4096),
+			wantCompression: CompressionGzip,
+			wantBase64:      true,
+		},
+		{
+			name:            "gain kept when encrypted",
+			config:          config{compression: CompressionGzip, compressionThreshold: 1024},
+			encrypt:         true,
+			data:            randomText(4096),
+			wantCompression: CompressionGzip,
+			wantBase64:      true,
+		},
+		{
+			name:       "encrypted",
+			encrypt:    true,
+			data:       text,
+			wantBase64: true,
+		},
+		{
+			name:          "offloaded",
+			config:        config{offloadThreshold: 1024},
+			data:          text,
+			wantOffloaded: true,
+		},
+		{
+			name:            "compressed, encrypted and offloaded",
+			config:          config{offloadThreshold: 64, compression: CompressionZstd, compressionThreshold: 1024},
+			encrypt:         true,
+			data:            text,
+			wantCompression: CompressionZstd,
+			wantBase64:      true,
+			wantOffloaded:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupPayload(t, tt.config)
+			ctx := WithEncryption(WithCompression(context.Background(), tt.compression), tt.encrypt)
+
+			attributes := map[string]string{}
+			if tt.contentType != "" {
+				attributes[ContentTypeAttribute] = tt.contentType
+			}
+			body, err := Encode(ctx, tt.data, attributes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := attributes[ContentEncodingAttribute]; got != tt.wantCompression {
+				t.Errorf("ContentEncoding is %q, want %q", got, tt.wantCompression)
+			}
+			if got := attributes[BodyEncodingAttribute] == EncodingBase64; got != tt.wantBase64 {
+				t.Errorf("base64 encoded is %v, want %v", got, tt.wantBase64)
+			}
+			if got := attributes[EncryptionAttribute] != ""; got != tt.encrypt {
+				t.Errorf("encrypted is %v, want %v", got, tt.encrypt)
+			}
+			if got := attributes[BlobAttribute] != ""; got != tt.wantOffloaded {
+				t.Errorf("offloaded is %v, want %v", got, tt.wantOffloaded)
+			}
+			if len(attributes) == 0 && body != string(tt.data) {
+				t.Errorf("body is %q, want the payload as it is", body)
+			}
+
+			data, err := Decode(context.Background(), body, attributes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Fatalf("Decode() = %q, want %q", data, tt.data)
+			}
+
+			// Only the attributes the caller gave are left
+			want := map[string]string{}
+			if tt.contentType != "" {
+				want[ContentTypeAttribute] = tt.contentType
+			}
+			if !maps.Equal(attributes, want) {
+				t.Fatalf("attributes after Decode are %v, want %v", attributes, want)
+			}
+		})
+	}
+}
+
+func TestDecodeLegacyEncryptionAttributes(t *testing.T) {
+	setupPayload(t, config{})
+	data := []byte(`{"greeting": "hello, world"}`)
+
+	attributes := map[string]string{}
+	body, err := Encode(WithEncryption(context.Background(), true), data, attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Earlier versions carried the data key and key ID in two attributes
+	encodedKey, keyID, _ := strings.Cut(attributes[EncryptionAttribute], ":")
+	delete(attributes, EncryptionAttribute)
+	attributes[EncryptedDataKeyAttribute] = encodedKey
+	attributes[EncryptionKeyIDAttribute] = keyID
+
+	decoded, err := Decode(context.Background(), body, attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Fatalf("Decode() = %q, want %q", decoded, data)
+	}
+	if len(attributes) != 0 {
+		t.Fatalf("attributes after Decode are %v, want none", attributes)
+	}
+}
+
+func TestDecodeRefusesOversizedPayloads(t *testing.T) {
+	setupPayload(t, config{compression: CompressionGzip, compressionThreshold: 1024, maxDecompressedSize: 1024})
+
+	attributes := map[string]string{}
+	body, err := Encode(context.Background(), []byte(strings.Repeat("a", 4096)), attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attributes[ContentEncodingAttribute] != CompressionGzip {
+		t.Fatalf("payload was not compressed: %v", attributes)
+	}
+
+	if _, err := Decode(context.Background(), body, attributes); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Decode() = %v, want ErrTooLarge", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package pubsubv1 holds the generated protobuf and gRPC code for the PubSub
+// service defined in pubsub.proto.
+package pubsubv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative pubsub/v1/pubsub.proto
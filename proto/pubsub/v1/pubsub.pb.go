@@ -0,0 +1,2474 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: pubsub/v1/pubsub.proto
+
+package pubsubv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Topic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn      string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Topic) Reset() {
+	*x = Topic{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Topic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Topic) ProtoMessage() {}
+
+func (x *Topic) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Topic.ProtoReflect.Descriptor instead.
+func (*Topic) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Topic) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+type TopicAttributes struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName *string                `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3,oneof" json:"display_name,omitempty"`
+	// JSON delivery policy
+	DeliveryPolicy string `protobuf:"bytes,2,opt,name=delivery_policy,json=deliveryPolicy,proto3" json:"delivery_policy,omitempty"`
+	// JSON access policy
+	Policy                    string  `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	KmsMasterKeyId            *string `protobuf:"bytes,4,opt,name=kms_master_key_id,json=kmsMasterKeyId,proto3,oneof" json:"kms_master_key_id,omitempty"`
+	FifoTopic                 *bool   `protobuf:"varint,5,opt,name=fifo_topic,json=fifoTopic,proto3,oneof" json:"fifo_topic,omitempty"`
+	ContentBasedDeduplication *bool   `protobuf:"varint,6,opt,name=content_based_deduplication,json=contentBasedDeduplication,proto3,oneof" json:"content_based_deduplication,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *TopicAttributes) Reset() {
+	*x = TopicAttributes{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopicAttributes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopicAttributes) ProtoMessage() {}
+
+func (x *TopicAttributes) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopicAttributes.ProtoReflect.Descriptor instead.
+func (*TopicAttributes) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TopicAttributes) GetDisplayName() string {
+	if x != nil && x.DisplayName != nil {
+		return *x.DisplayName
+	}
+	return ""
+}
+
+func (x *TopicAttributes) GetDeliveryPolicy() string {
+	if x != nil {
+		return x.DeliveryPolicy
+	}
+	return ""
+}
+
+func (x *TopicAttributes) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *TopicAttributes) GetKmsMasterKeyId() string {
+	if x != nil && x.KmsMasterKeyId != nil {
+		return *x.KmsMasterKeyId
+	}
+	return ""
+}
+
+func (x *TopicAttributes) GetFifoTopic() bool {
+	if x != nil && x.FifoTopic != nil {
+		return *x.FifoTopic
+	}
+	return false
+}
+
+func (x *TopicAttributes) GetContentBasedDeduplication() bool {
+	if x != nil && x.ContentBasedDeduplication != nil {
+		return *x.ContentBasedDeduplication
+	}
+	return false
+}
+
+type ListTopicsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTopicsRequest) Reset() {
+	*x = ListTopicsRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTopicsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTopicsRequest) ProtoMessage() {}
+
+func (x *ListTopicsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTopicsRequest.ProtoReflect.Descriptor instead.
+func (*ListTopicsRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{2}
+}
+
+type ListTopicsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topics        []*Topic               `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTopicsResponse) Reset() {
+	*x = ListTopicsResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTopicsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTopicsResponse) ProtoMessage() {}
+
+func (x *ListTopicsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTopicsResponse.ProtoReflect.Descriptor instead.
+func (*ListTopicsResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTopicsResponse) GetTopics() []*Topic {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+type CreateTopicRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicName     string                 `protobuf:"bytes,1,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	Attributes    *TopicAttributes       `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTopicRequest) Reset() {
+	*x = CreateTopicRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTopicRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTopicRequest) ProtoMessage() {}
+
+func (x *CreateTopicRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTopicRequest.ProtoReflect.Descriptor instead.
+func (*CreateTopicRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateTopicRequest) GetTopicName() string {
+	if x != nil {
+		return x.TopicName
+	}
+	return ""
+}
+
+func (x *CreateTopicRequest) GetAttributes() *TopicAttributes {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type CreateTopicResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn      string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTopicResponse) Reset() {
+	*x = CreateTopicResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTopicResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTopicResponse) ProtoMessage() {}
+
+func (x *CreateTopicResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTopicResponse.ProtoReflect.Descriptor instead.
+func (*CreateTopicResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateTopicResponse) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+type Subscription struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionArn string                 `protobuf:"bytes,1,opt,name=subscription_arn,json=subscriptionArn,proto3" json:"subscription_arn,omitempty"`
+	Protocol        string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Endpoint        string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Owner           string                 `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	TopicArn        string                 `protobuf:"bytes,5,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Subscription) GetSubscriptionArn() string {
+	if x != nil {
+		return x.SubscriptionArn
+	}
+	return ""
+}
+
+func (x *Subscription) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *Subscription) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *Subscription) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Subscription) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn      string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSubscriptionsRequest) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type SubscribeEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn      string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeEmailRequest) Reset() {
+	*x = SubscribeEmailRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeEmailRequest) ProtoMessage() {}
+
+func (x *SubscribeEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeEmailRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeEmailRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SubscribeEmailRequest) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+func (x *SubscribeEmailRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type SubscribeEmailResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionArn string                 `protobuf:"bytes,1,opt,name=subscription_arn,json=subscriptionArn,proto3" json:"subscription_arn,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SubscribeEmailResponse) Reset() {
+	*x = SubscribeEmailResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeEmailResponse) ProtoMessage() {}
+
+func (x *SubscribeEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeEmailResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeEmailResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubscribeEmailResponse) GetSubscriptionArn() string {
+	if x != nil {
+		return x.SubscriptionArn
+	}
+	return ""
+}
+
+type SubscribeQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn      string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	QueueName     string                 `protobuf:"bytes,2,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeQueueRequest) Reset() {
+	*x = SubscribeQueueRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeQueueRequest) ProtoMessage() {}
+
+func (x *SubscribeQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeQueueRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeQueueRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubscribeQueueRequest) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+func (x *SubscribeQueueRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+type SubscribeQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeQueueResponse) Reset() {
+	*x = SubscribeQueueResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeQueueResponse) ProtoMessage() {}
+
+func (x *SubscribeQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeQueueResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeQueueResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{12}
+}
+
+type UnsubscribeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn        string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	SubscriptionArn string                 `protobuf:"bytes,2,opt,name=subscription_arn,json=subscriptionArn,proto3" json:"subscription_arn,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UnsubscribeRequest) Reset() {
+	*x = UnsubscribeRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeRequest) ProtoMessage() {}
+
+func (x *UnsubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeRequest.ProtoReflect.Descriptor instead.
+func (*UnsubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UnsubscribeRequest) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+func (x *UnsubscribeRequest) GetSubscriptionArn() string {
+	if x != nil {
+		return x.SubscriptionArn
+	}
+	return ""
+}
+
+type UnsubscribeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnsubscribeResponse) Reset() {
+	*x = UnsubscribeResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeResponse) ProtoMessage() {}
+
+func (x *UnsubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeResponse.ProtoReflect.Descriptor instead.
+func (*UnsubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{14}
+}
+
+type PublishRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TopicArn string                 `protobuf:"bytes,1,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	// A text message, or binary data described by content_type
+	//
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*PublishRequest_Message
+	//	*PublishRequest_Data
+	Payload     isPublishRequest_Payload `protobuf_oneof:"payload"`
+	ContentType string                   `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// none, gzip or zstd; defaults to the service's configuration
+	Compression   string `protobuf:"bytes,5,opt,name=compression,proto3" json:"compression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishRequest) Reset() {
+	*x = PublishRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequest) ProtoMessage() {}
+
+func (x *PublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PublishRequest) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetPayload() isPublishRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetMessage() string {
+	if x != nil {
+		if x, ok := x.Payload.(*PublishRequest_Message); ok {
+			return x.Message
+		}
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*PublishRequest_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+type isPublishRequest_Payload interface {
+	isPublishRequest_Payload()
+}
+
+type PublishRequest_Message struct {
+	Message string `protobuf:"bytes,2,opt,name=message,proto3,oneof"`
+}
+
+type PublishRequest_Data struct {
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3,oneof"`
+}
+
+func (*PublishRequest_Message) isPublishRequest_Payload() {}
+
+func (*PublishRequest_Data) isPublishRequest_Payload() {}
+
+type PublishResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishResponse) Reset() {
+	*x = PublishResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResponse) ProtoMessage() {}
+
+func (x *PublishResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResponse.ProtoReflect.Descriptor instead.
+func (*PublishResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PublishResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type PublishResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Set when the message could not be published
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishResult) Reset() {
+	*x = PublishResult{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResult) ProtoMessage() {}
+
+func (x *PublishResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResult.ProtoReflect.Descriptor instead.
+func (*PublishResult) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PublishResult) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *PublishResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PublishStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per message, in the order they were sent
+	Results       []*PublishResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishStreamResponse) Reset() {
+	*x = PublishStreamResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishStreamResponse) ProtoMessage() {}
+
+func (x *PublishStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishStreamResponse.ProtoReflect.Descriptor instead.
+func (*PublishStreamResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PublishStreamResponse) GetResults() []*PublishResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type QueueAttributes struct {
+	state                         protoimpl.MessageState `protogen:"open.v1"`
+	DelaySeconds                  *int64                 `protobuf:"varint,1,opt,name=delay_seconds,json=delaySeconds,proto3,oneof" json:"delay_seconds,omitempty"`
+	MaximumMessageSize            *int64                 `protobuf:"varint,2,opt,name=maximum_message_size,json=maximumMessageSize,proto3,oneof" json:"maximum_message_size,omitempty"`
+	MessageRetentionPeriod        *int64                 `protobuf:"varint,3,opt,name=message_retention_period,json=messageRetentionPeriod,proto3,oneof" json:"message_retention_period,omitempty"`
+	ReceiveMessageWaitTimeSeconds *int64                 `protobuf:"varint,4,opt,name=receive_message_wait_time_seconds,json=receiveMessageWaitTimeSeconds,proto3,oneof" json:"receive_message_wait_time_seconds,omitempty"`
+	VisibilityTimeout             *int64                 `protobuf:"varint,5,opt,name=visibility_timeout,json=visibilityTimeout,proto3,oneof" json:"visibility_timeout,omitempty"`
+	KmsMasterKeyId                *string                `protobuf:"bytes,6,opt,name=kms_master_key_id,json=kmsMasterKeyId,proto3,oneof" json:"kms_master_key_id,omitempty"`
+	KmsDataKeyReusePeriodSeconds  *int64                 `protobuf:"varint,7,opt,name=kms_data_key_reuse_period_seconds,json=kmsDataKeyReusePeriodSeconds,proto3,oneof" json:"kms_data_key_reuse_period_seconds,omitempty"`
+	SqsManagedSseEnabled          *bool                  `protobuf:"varint,8,opt,name=sqs_managed_sse_enabled,json=sqsManagedSseEnabled,proto3,oneof" json:"sqs_managed_sse_enabled,omitempty"`
+	unknownFields                 protoimpl.UnknownFields
+	sizeCache                     protoimpl.SizeCache
+}
+
+func (x *QueueAttributes) Reset() {
+	*x = QueueAttributes{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueAttributes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueAttributes) ProtoMessage() {}
+
+func (x *QueueAttributes) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueAttributes.ProtoReflect.Descriptor instead.
+func (*QueueAttributes) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *QueueAttributes) GetDelaySeconds() int64 {
+	if x != nil && x.DelaySeconds != nil {
+		return *x.DelaySeconds
+	}
+	return 0
+}
+
+func (x *QueueAttributes) GetMaximumMessageSize() int64 {
+	if x != nil && x.MaximumMessageSize != nil {
+		return *x.MaximumMessageSize
+	}
+	return 0
+}
+
+func (x *QueueAttributes) GetMessageRetentionPeriod() int64 {
+	if x != nil && x.MessageRetentionPeriod != nil {
+		return *x.MessageRetentionPeriod
+	}
+	return 0
+}
+
+func (x *QueueAttributes) GetReceiveMessageWaitTimeSeconds() int64 {
+	if x != nil && x.ReceiveMessageWaitTimeSeconds != nil {
+		return *x.ReceiveMessageWaitTimeSeconds
+	}
+	return 0
+}
+
+func (x *QueueAttributes) GetVisibilityTimeout() int64 {
+	if x != nil && x.VisibilityTimeout != nil {
+		return *x.VisibilityTimeout
+	}
+	return 0
+}
+
+func (x *QueueAttributes) GetKmsMasterKeyId() string {
+	if x != nil && x.KmsMasterKeyId != nil {
+		return *x.KmsMasterKeyId
+	}
+	return ""
+}
+
+func (x *QueueAttributes) GetKmsDataKeyReusePeriodSeconds() int64 {
+	if x != nil && x.KmsDataKeyReusePeriodSeconds != nil {
+		return *x.KmsDataKeyReusePeriodSeconds
+	}
+	return 0
+}
+
+func (x *QueueAttributes) GetSqsManagedSseEnabled() bool {
+	if x != nil && x.SqsManagedSseEnabled != nil {
+		return *x.SqsManagedSseEnabled
+	}
+	return false
+}
+
+type ListQueuesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueuesRequest) Reset() {
+	*x = ListQueuesRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueuesRequest) ProtoMessage() {}
+
+func (x *ListQueuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueuesRequest.ProtoReflect.Descriptor instead.
+func (*ListQueuesRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{20}
+}
+
+type ListQueuesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueUrls     []string               `protobuf:"bytes,1,rep,name=queue_urls,json=queueUrls,proto3" json:"queue_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueuesResponse) Reset() {
+	*x = ListQueuesResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueuesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueuesResponse) ProtoMessage() {}
+
+func (x *ListQueuesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueuesResponse.ProtoReflect.Descriptor instead.
+func (*ListQueuesResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListQueuesResponse) GetQueueUrls() []string {
+	if x != nil {
+		return x.QueueUrls
+	}
+	return nil
+}
+
+type CreateQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueName     string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	Attributes    *QueueAttributes       `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateQueueRequest) Reset() {
+	*x = CreateQueueRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQueueRequest) ProtoMessage() {}
+
+func (x *CreateQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQueueRequest.ProtoReflect.Descriptor instead.
+func (*CreateQueueRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreateQueueRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *CreateQueueRequest) GetAttributes() *QueueAttributes {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type CreateQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateQueueResponse) Reset() {
+	*x = CreateQueueResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQueueResponse) ProtoMessage() {}
+
+func (x *CreateQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQueueResponse.ProtoReflect.Descriptor instead.
+func (*CreateQueueResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{23}
+}
+
+type GetQueueUrlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueName     string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQueueUrlRequest) Reset() {
+	*x = GetQueueUrlRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQueueUrlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQueueUrlRequest) ProtoMessage() {}
+
+func (x *GetQueueUrlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQueueUrlRequest.ProtoReflect.Descriptor instead.
+func (*GetQueueUrlRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetQueueUrlRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+type GetQueueUrlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueUrl      string                 `protobuf:"bytes,1,opt,name=queue_url,json=queueUrl,proto3" json:"queue_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQueueUrlResponse) Reset() {
+	*x = GetQueueUrlResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQueueUrlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQueueUrlResponse) ProtoMessage() {}
+
+func (x *GetQueueUrlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQueueUrlResponse.ProtoReflect.Descriptor instead.
+func (*GetQueueUrlResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetQueueUrlResponse) GetQueueUrl() string {
+	if x != nil {
+		return x.QueueUrl
+	}
+	return ""
+}
+
+type DeleteQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueName     string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteQueueRequest) Reset() {
+	*x = DeleteQueueRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteQueueRequest) ProtoMessage() {}
+
+func (x *DeleteQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteQueueRequest.ProtoReflect.Descriptor instead.
+func (*DeleteQueueRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteQueueRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+type DeleteQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteQueueResponse) Reset() {
+	*x = DeleteQueueResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteQueueResponse) ProtoMessage() {}
+
+func (x *DeleteQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteQueueResponse.ProtoReflect.Descriptor instead.
+func (*DeleteQueueResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{27}
+}
+
+type SendMessageRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	QueueName string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	Subject   string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// A text body, or binary data described by content_type
+	//
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*SendMessageRequest_Body
+	//	*SendMessageRequest_Data
+	Payload          isSendMessageRequest_Payload `protobuf_oneof:"payload"`
+	ContentType      string                       `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Attributes       map[string]string            `protobuf:"bytes,6,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	BinaryAttributes map[string][]byte            `protobuf:"bytes,7,rep,name=binary_attributes,json=binaryAttributes,proto3" json:"binary_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// none, gzip or zstd; defaults to the service's configuration
+	Compression   string `protobuf:"bytes,8,opt,name=compression,proto3" json:"compression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageRequest) Reset() {
+	*x = SendMessageRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageRequest) ProtoMessage() {}
+
+func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendMessageRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SendMessageRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetPayload() isSendMessageRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetBody() string {
+	if x != nil {
+		if x, ok := x.Payload.(*SendMessageRequest_Body); ok {
+			return x.Body
+		}
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*SendMessageRequest_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetBinaryAttributes() map[string][]byte {
+	if x != nil {
+		return x.BinaryAttributes
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+type isSendMessageRequest_Payload interface {
+	isSendMessageRequest_Payload()
+}
+
+type SendMessageRequest_Body struct {
+	Body string `protobuf:"bytes,3,opt,name=body,proto3,oneof"`
+}
+
+type SendMessageRequest_Data struct {
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3,oneof"`
+}
+
+func (*SendMessageRequest_Body) isSendMessageRequest_Payload() {}
+
+func (*SendMessageRequest_Data) isSendMessageRequest_Payload() {}
+
+type SendMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageResponse) ProtoMessage() {}
+
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{29}
+}
+
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ReceiptHandle string                 `protobuf:"bytes,2,opt,name=receipt_handle,json=receiptHandle,proto3" json:"receipt_handle,omitempty"`
+	// Set for messages fanned out from a topic
+	TopicArn    string `protobuf:"bytes,3,opt,name=topic_arn,json=topicArn,proto3" json:"topic_arn,omitempty"`
+	ContentType string `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// A text payload, or binary data
+	//
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Message_Body
+	//	*Message_Data
+	Payload          isMessage_Payload      `protobuf_oneof:"payload"`
+	Attributes       map[string]string      `protobuf:"bytes,7,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	BinaryAttributes map[string][]byte      `protobuf:"bytes,8,rep,name=binary_attributes,json=binaryAttributes,proto3" json:"binary_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SentAt           *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	// The payload decoded to JSON, when requested and its schema is known
+	Decoded       string `protobuf:"bytes,10,opt,name=decoded,proto3" json:"decoded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Message) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *Message) GetReceiptHandle() string {
+	if x != nil {
+		return x.ReceiptHandle
+	}
+	return ""
+}
+
+func (x *Message) GetTopicArn() string {
+	if x != nil {
+		return x.TopicArn
+	}
+	return ""
+}
+
+func (x *Message) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Message) GetPayload() isMessage_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Message) GetBody() string {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_Body); ok {
+			return x.Body
+		}
+	}
+	return ""
+}
+
+func (x *Message) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Message) GetBinaryAttributes() map[string][]byte {
+	if x != nil {
+		return x.BinaryAttributes
+	}
+	return nil
+}
+
+func (x *Message) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *Message) GetDecoded() string {
+	if x != nil {
+		return x.Decoded
+	}
+	return ""
+}
+
+type isMessage_Payload interface {
+	isMessage_Payload()
+}
+
+type Message_Body struct {
+	Body string `protobuf:"bytes,5,opt,name=body,proto3,oneof"`
+}
+
+type Message_Data struct {
+	Data []byte `protobuf:"bytes,6,opt,name=data,proto3,oneof"`
+}
+
+func (*Message_Body) isMessage_Payload() {}
+
+func (*Message_Data) isMessage_Payload() {}
+
+type ReceiveMessageRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	QueueName         string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	VisibilityTimeout int32                  `protobuf:"varint,2,opt,name=visibility_timeout,json=visibilityTimeout,proto3" json:"visibility_timeout,omitempty"`
+	// Decode schema-encoded payloads to JSON
+	Decode        bool `protobuf:"varint,3,opt,name=decode,proto3" json:"decode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveMessageRequest) Reset() {
+	*x = ReceiveMessageRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveMessageRequest) ProtoMessage() {}
+
+func (x *ReceiveMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveMessageRequest.ProtoReflect.Descriptor instead.
+func (*ReceiveMessageRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReceiveMessageRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *ReceiveMessageRequest) GetVisibilityTimeout() int32 {
+	if x != nil {
+		return x.VisibilityTimeout
+	}
+	return 0
+}
+
+func (x *ReceiveMessageRequest) GetDecode() bool {
+	if x != nil {
+		return x.Decode
+	}
+	return false
+}
+
+type ReceiveMessageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset when the queue is empty
+	Message       *Message `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveMessageResponse) Reset() {
+	*x = ReceiveMessageResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveMessageResponse) ProtoMessage() {}
+
+func (x *ReceiveMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveMessageResponse.ProtoReflect.Descriptor instead.
+func (*ReceiveMessageResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReceiveMessageResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type StreamMessagesRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	QueueName         string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	VisibilityTimeout int32                  `protobuf:"varint,2,opt,name=visibility_timeout,json=visibilityTimeout,proto3" json:"visibility_timeout,omitempty"`
+	Decode            bool                   `protobuf:"varint,3,opt,name=decode,proto3" json:"decode,omitempty"`
+	// How long to wait when the queue is empty; defaults to one second
+	PollIntervalMs int32 `protobuf:"varint,4,opt,name=poll_interval_ms,json=pollIntervalMs,proto3" json:"poll_interval_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StreamMessagesRequest) Reset() {
+	*x = StreamMessagesRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMessagesRequest) ProtoMessage() {}
+
+func (x *StreamMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMessagesRequest.ProtoReflect.Descriptor instead.
+func (*StreamMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *StreamMessagesRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *StreamMessagesRequest) GetVisibilityTimeout() int32 {
+	if x != nil {
+		return x.VisibilityTimeout
+	}
+	return 0
+}
+
+func (x *StreamMessagesRequest) GetDecode() bool {
+	if x != nil {
+		return x.Decode
+	}
+	return false
+}
+
+func (x *StreamMessagesRequest) GetPollIntervalMs() int32 {
+	if x != nil {
+		return x.PollIntervalMs
+	}
+	return 0
+}
+
+type DeleteMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueName     string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	ReceiptHandle string                 `protobuf:"bytes,2,opt,name=receipt_handle,json=receiptHandle,proto3" json:"receipt_handle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMessageRequest) Reset() {
+	*x = DeleteMessageRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageRequest) ProtoMessage() {}
+
+func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMessageRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DeleteMessageRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *DeleteMessageRequest) GetReceiptHandle() string {
+	if x != nil {
+		return x.ReceiptHandle
+	}
+	return ""
+}
+
+type DeleteMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMessageResponse) Reset() {
+	*x = DeleteMessageResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageResponse) ProtoMessage() {}
+
+func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMessageResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{35}
+}
+
+type ChangeMessageVisibilityRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	QueueName         string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	ReceiptHandle     string                 `protobuf:"bytes,2,opt,name=receipt_handle,json=receiptHandle,proto3" json:"receipt_handle,omitempty"`
+	VisibilityTimeout int32                  `protobuf:"varint,3,opt,name=visibility_timeout,json=visibilityTimeout,proto3" json:"visibility_timeout,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChangeMessageVisibilityRequest) Reset() {
+	*x = ChangeMessageVisibilityRequest{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeMessageVisibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeMessageVisibilityRequest) ProtoMessage() {}
+
+func (x *ChangeMessageVisibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeMessageVisibilityRequest.ProtoReflect.Descriptor instead.
+func (*ChangeMessageVisibilityRequest) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ChangeMessageVisibilityRequest) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *ChangeMessageVisibilityRequest) GetReceiptHandle() string {
+	if x != nil {
+		return x.ReceiptHandle
+	}
+	return ""
+}
+
+func (x *ChangeMessageVisibilityRequest) GetVisibilityTimeout() int32 {
+	if x != nil {
+		return x.VisibilityTimeout
+	}
+	return 0
+}
+
+type ChangeMessageVisibilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeMessageVisibilityResponse) Reset() {
+	*x = ChangeMessageVisibilityResponse{}
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeMessageVisibilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeMessageVisibilityResponse) ProtoMessage() {}
+
+func (x *ChangeMessageVisibilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pubsub_v1_pubsub_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeMessageVisibilityResponse.ProtoReflect.Descriptor instead.
+func (*ChangeMessageVisibilityResponse) Descriptor() ([]byte, []int) {
+	return file_pubsub_v1_pubsub_proto_rawDescGZIP(), []int{37}
+}
+
+var File_pubsub_v1_pubsub_proto protoreflect.FileDescriptor
+
+const file_pubsub_v1_pubsub_proto_rawDesc = "" +
+	"\n" +
+	"\x16pubsub/v1/pubsub.proto\x12\tpubsub.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"$\n" +
+	"\x05Topic\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\"\xe9\x02\n" +
+	"\x0fTopicAttributes\x12&\n" +
+	"\fdisplay_name\x18\x01 \x01(\tH\x00R\vdisplayName\x88\x01\x01\x12'\n" +
+	"\x0fdelivery_policy\x18\x02 \x01(\tR\x0edeliveryPolicy\x12\x16\n" +
+	"\x06policy\x18\x03 \x01(\tR\x06policy\x12.\n" +
+	"\x11kms_master_key_id\x18\x04 \x01(\tH\x01R\x0ekmsMasterKeyId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"fifo_topic\x18\x05 \x01(\bH\x02R\tfifoTopic\x88\x01\x01\x12C\n" +
+	"\x1bcontent_based_deduplication\x18\x06 \x01(\bH\x03R\x19contentBasedDeduplication\x88\x01\x01B\x0f\n" +
+	"\r_display_nameB\x14\n" +
+	"\x12_kms_master_key_idB\r\n" +
+	"\v_fifo_topicB\x1e\n" +
+	"\x1c_content_based_deduplication\"\x13\n" +
+	"\x11ListTopicsRequest\">\n" +
+	"\x12ListTopicsResponse\x12(\n" +
+	"\x06topics\x18\x01 \x03(\v2\x10.pubsub.v1.TopicR\x06topics\"o\n" +
+	"\x12CreateTopicRequest\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x01 \x01(\tR\ttopicName\x12:\n" +
+	"\n" +
+	"attributes\x18\x02 \x01(\v2\x1a.pubsub.v1.TopicAttributesR\n" +
+	"attributes\"2\n" +
+	"\x13CreateTopicResponse\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\"\xa4\x01\n" +
+	"\fSubscription\x12)\n" +
+	"\x10subscription_arn\x18\x01 \x01(\tR\x0fsubscriptionArn\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\x12\x1b\n" +
+	"\ttopic_arn\x18\x05 \x01(\tR\btopicArn\"7\n" +
+	"\x18ListSubscriptionsRequest\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\"Z\n" +
+	"\x19ListSubscriptionsResponse\x12=\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x17.pubsub.v1.SubscriptionR\rsubscriptions\"J\n" +
+	"\x15SubscribeEmailRequest\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"C\n" +
+	"\x16SubscribeEmailResponse\x12)\n" +
+	"\x10subscription_arn\x18\x01 \x01(\tR\x0fsubscriptionArn\"S\n" +
+	"\x15SubscribeQueueRequest\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x02 \x01(\tR\tqueueName\"\x18\n" +
+	"\x16SubscribeQueueResponse\"\\\n" +
+	"\x12UnsubscribeRequest\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\x12)\n" +
+	"\x10subscription_arn\x18\x02 \x01(\tR\x0fsubscriptionArn\"\x15\n" +
+	"\x13UnsubscribeResponse\"\xaf\x01\n" +
+	"\x0ePublishRequest\x12\x1b\n" +
+	"\ttopic_arn\x18\x01 \x01(\tR\btopicArn\x12\x1a\n" +
+	"\amessage\x18\x02 \x01(\tH\x00R\amessage\x12\x14\n" +
+	"\x04data\x18\x03 \x01(\fH\x00R\x04data\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12 \n" +
+	"\vcompression\x18\x05 \x01(\tR\vcompressionB\t\n" +
+	"\apayload\"0\n" +
+	"\x0fPublishResponse\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"D\n" +
+	"\rPublishResult\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"K\n" +
+	"\x15PublishStreamResponse\x122\n" +
+	"\aresults\x18\x01 \x03(\v2\x18.pubsub.v1.PublishResultR\aresults\"\xcb\x05\n" +
+	"\x0fQueueAttributes\x12(\n" +
+	"\rdelay_seconds\x18\x01 \x01(\x03H\x00R\fdelaySeconds\x88\x01\x01\x125\n" +
+	"\x14maximum_message_size\x18\x02 \x01(\x03H\x01R\x12maximumMessageSize\x88\x01\x01\x12=\n" +
+	"\x18message_retention_period\x18\x03 \x01(\x03H\x02R\x16messageRetentionPeriod\x88\x01\x01\x12M\n" +
+	"!receive_message_wait_time_seconds\x18\x04 \x01(\x03H\x03R\x1dreceiveMessageWaitTimeSeconds\x88\x01\x01\x122\n" +
+	"\x12visibility_timeout\x18\x05 \x01(\x03H\x04R\x11visibilityTimeout\x88\x01\x01\x12.\n" +
+	"\x11kms_master_key_id\x18\x06 \x01(\tH\x05R\x0ekmsMasterKeyId\x88\x01\x01\x12L\n" +
+	"!kms_data_key_reuse_period_seconds\x18\a \x01(\x03H\x06R\x1ckmsDataKeyReusePeriodSeconds\x88\x01\x01\x12:\n" +
+	"\x17sqs_managed_sse_enabled\x18\b \x01(\bH\aR\x14sqsManagedSseEnabled\x88\x01\x01B\x10\n" +
+	"\x0e_delay_secondsB\x17\n" +
+	"\x15_maximum_message_sizeB\x1b\n" +
+	"\x19_message_retention_periodB$\n" +
+	"\"_receive_message_wait_time_secondsB\x15\n" +
+	"\x13_visibility_timeoutB\x14\n" +
+	"\x12_kms_master_key_idB$\n" +
+	"\"_kms_data_key_reuse_period_secondsB\x1a\n" +
+	"\x18_sqs_managed_sse_enabled\"\x13\n" +
+	"\x11ListQueuesRequest\"3\n" +
+	"\x12ListQueuesResponse\x12\x1d\n" +
+	"\n" +
+	"queue_urls\x18\x01 \x03(\tR\tqueueUrls\"o\n" +
+	"\x12CreateQueueRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12:\n" +
+	"\n" +
+	"attributes\x18\x02 \x01(\v2\x1a.pubsub.v1.QueueAttributesR\n" +
+	"attributes\"\x15\n" +
+	"\x13CreateQueueResponse\"3\n" +
+	"\x12GetQueueUrlRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\"2\n" +
+	"\x13GetQueueUrlResponse\x12\x1b\n" +
+	"\tqueue_url\x18\x01 \x01(\tR\bqueueUrl\"3\n" +
+	"\x12DeleteQueueRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\"\x15\n" +
+	"\x13DeleteQueueResponse\"\xfe\x03\n" +
+	"\x12SendMessageRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x14\n" +
+	"\x04body\x18\x03 \x01(\tH\x00R\x04body\x12\x14\n" +
+	"\x04data\x18\x04 \x01(\fH\x00R\x04data\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x12M\n" +
+	"\n" +
+	"attributes\x18\x06 \x03(\v2-.pubsub.v1.SendMessageRequest.AttributesEntryR\n" +
+	"attributes\x12`\n" +
+	"\x11binary_attributes\x18\a \x03(\v23.pubsub.v1.SendMessageRequest.BinaryAttributesEntryR\x10binaryAttributes\x12 \n" +
+	"\vcompression\x18\b \x01(\tR\vcompression\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15BinaryAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01B\t\n" +
+	"\apayload\"\x15\n" +
+	"\x13SendMessageResponse\"\xb4\x04\n" +
+	"\aMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12%\n" +
+	"\x0ereceipt_handle\x18\x02 \x01(\tR\rreceiptHandle\x12\x1b\n" +
+	"\ttopic_arn\x18\x03 \x01(\tR\btopicArn\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x14\n" +
+	"\x04body\x18\x05 \x01(\tH\x00R\x04body\x12\x14\n" +
+	"\x04data\x18\x06 \x01(\fH\x00R\x04data\x12B\n" +
+	"\n" +
+	"attributes\x18\a \x03(\v2\".pubsub.v1.Message.AttributesEntryR\n" +
+	"attributes\x12U\n" +
+	"\x11binary_attributes\x18\b \x03(\v2(.pubsub.v1.Message.BinaryAttributesEntryR\x10binaryAttributes\x123\n" +
+	"\asent_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x18\n" +
+	"\adecoded\x18\n" +
+	" \x01(\tR\adecoded\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15BinaryAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01B\t\n" +
+	"\apayload\"}\n" +
+	"\x15ReceiveMessageRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12-\n" +
+	"\x12visibility_timeout\x18\x02 \x01(\x05R\x11visibilityTimeout\x12\x16\n" +
+	"\x06decode\x18\x03 \x01(\bR\x06decode\"F\n" +
+	"\x16ReceiveMessageResponse\x12,\n" +
+	"\amessage\x18\x01 \x01(\v2\x12.pubsub.v1.MessageR\amessage\"\xa7\x01\n" +
+	"\x15StreamMessagesRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12-\n" +
+	"\x12visibility_timeout\x18\x02 \x01(\x05R\x11visibilityTimeout\x12\x16\n" +
+	"\x06decode\x18\x03 \x01(\bR\x06decode\x12(\n" +
+	"\x10poll_interval_ms\x18\x04 \x01(\x05R\x0epollIntervalMs\"\\\n" +
+	"\x14DeleteMessageRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12%\n" +
+	"\x0ereceipt_handle\x18\x02 \x01(\tR\rreceiptHandle\"\x17\n" +
+	"\x15DeleteMessageResponse\"\x95\x01\n" +
+	"\x1eChangeMessageVisibilityRequest\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12%\n" +
+	"\x0ereceipt_handle\x18\x02 \x01(\tR\rreceiptHandle\x12-\n" +
+	"\x12visibility_timeout\x18\x03 \x01(\x05R\x11visibilityTimeout\"!\n" +
+	"\x1fChangeMessageVisibilityResponse2\xf9\n" +
+	"\n" +
+	"\x06PubSub\x12I\n" +
+	"\n" +
+	"ListTopics\x12\x1c.pubsub.v1.ListTopicsRequest\x1a\x1d.pubsub.v1.ListTopicsResponse\x12L\n" +
+	"\vCreateTopic\x12\x1d.pubsub.v1.CreateTopicRequest\x1a\x1e.pubsub.v1.CreateTopicResponse\x12^\n" +
+	"\x11ListSubscriptions\x12#.pubsub.v1.ListSubscriptionsRequest\x1a$.pubsub.v1.ListSubscriptionsResponse\x12U\n" +
+	"\x0eSubscribeEmail\x12 .pubsub.v1.SubscribeEmailRequest\x1a!.pubsub.v1.SubscribeEmailResponse\x12U\n" +
+	"\x0eSubscribeQueue\x12 .pubsub.v1.SubscribeQueueRequest\x1a!.pubsub.v1.SubscribeQueueResponse\x12L\n" +
+	"\vUnsubscribe\x12\x1d.pubsub.v1.UnsubscribeRequest\x1a\x1e.pubsub.v1.UnsubscribeResponse\x12@\n" +
+	"\aPublish\x12\x19.pubsub.v1.PublishRequest\x1a\x1a.pubsub.v1.PublishResponse\x12N\n" +
+	"\rPublishStream\x12\x19.pubsub.v1.PublishRequest\x1a .pubsub.v1.PublishStreamResponse(\x01\x12I\n" +
+	"\n" +
+	"ListQueues\x12\x1c.pubsub.v1.ListQueuesRequest\x1a\x1d.pubsub.v1.ListQueuesResponse\x12L\n" +
+	"\vCreateQueue\x12\x1d.pubsub.v1.CreateQueueRequest\x1a\x1e.pubsub.v1.CreateQueueResponse\x12L\n" +
+	"\vGetQueueUrl\x12\x1d.pubsub.v1.GetQueueUrlRequest\x1a\x1e.pubsub.v1.GetQueueUrlResponse\x12L\n" +
+	"\vDeleteQueue\x12\x1d.pubsub.v1.DeleteQueueRequest\x1a\x1e.pubsub.v1.DeleteQueueResponse\x12L\n" +
+	"\vSendMessage\x12\x1d.pubsub.v1.SendMessageRequest\x1a\x1e.pubsub.v1.SendMessageResponse\x12U\n" +
+	"\x0eReceiveMessage\x12 .pubsub.v1.ReceiveMessageRequest\x1a!.pubsub.v1.ReceiveMessageResponse\x12H\n" +
+	"\x0eStreamMessages\x12 .pubsub.v1.StreamMessagesRequest\x1a\x12.pubsub.v1.Message0\x01\x12R\n" +
+	"\rDeleteMessage\x12\x1f.pubsub.v1.DeleteMessageRequest\x1a .pubsub.v1.DeleteMessageResponse\x12p\n" +
+	"\x17ChangeMessageVisibility\x12).pubsub.v1.ChangeMessageVisibilityRequest\x1a*.pubsub.v1.ChangeMessageVisibilityResponseB*Z(pub-sub-service/proto/pubsub/v1;pubsubv1b\x06proto3"
+
+var (
+	file_pubsub_v1_pubsub_proto_rawDescOnce sync.Once
+	file_pubsub_v1_pubsub_proto_rawDescData []byte
+)
+
+func file_pubsub_v1_pubsub_proto_rawDescGZIP() []byte {
+	file_pubsub_v1_pubsub_proto_rawDescOnce.Do(func() {
+		file_pubsub_v1_pubsub_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pubsub_v1_pubsub_proto_rawDesc), len(file_pubsub_v1_pubsub_proto_rawDesc)))
+	})
+	return file_pubsub_v1_pubsub_proto_rawDescData
+}
+
+var file_pubsub_v1_pubsub_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
+var file_pubsub_v1_pubsub_proto_goTypes = []any{
+	(*Topic)(nil),                           // 0: pubsub.v1.Topic
+	(*TopicAttributes)(nil),                 // 1: pubsub.v1.TopicAttributes
+	(*ListTopicsRequest)(nil),               // 2: pubsub.v1.ListTopicsRequest
+	(*ListTopicsResponse)(nil),              // 3: pubsub.v1.ListTopicsResponse
+	(*CreateTopicRequest)(nil),              // 4: pubsub.v1.CreateTopicRequest
+	(*CreateTopicResponse)(nil),             // 5: pubsub.v1.CreateTopicResponse
+	(*Subscription)(nil),                    // 6: pubsub.v1.Subscription
+	(*ListSubscriptionsRequest)(nil),        // 7: pubsub.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),       // 8: pubsub.v1.ListSubscriptionsResponse
+	(*SubscribeEmailRequest)(nil),           // 9: pubsub.v1.SubscribeEmailRequest
+	(*SubscribeEmailResponse)(nil),          // 10: pubsub.v1.SubscribeEmailResponse
+	(*SubscribeQueueRequest)(nil),           // 11: pubsub.v1.SubscribeQueueRequest
+	(*SubscribeQueueResponse)(nil),          // 12: pubsub.v1.SubscribeQueueResponse
+	(*UnsubscribeRequest)(nil),              // 13: pubsub.v1.UnsubscribeRequest
+	(*UnsubscribeResponse)(nil),             // 14: pubsub.v1.UnsubscribeResponse
+	(*PublishRequest)(nil),                  // 15: pubsub.v1.PublishRequest
+	(*PublishResponse)(nil),                 // 16: pubsub.v1.PublishResponse
+	(*PublishResult)(nil),                   // 17: pubsub.v1.PublishResult
+	(*PublishStreamResponse)(nil),           // 18: pubsub.v1.PublishStreamResponse
+	(*QueueAttributes)(nil),                 // 19: pubsub.v1.QueueAttributes
+	(*ListQueuesRequest)(nil),               // 20: pubsub.v1.ListQueuesRequest
+	(*ListQueuesResponse)(nil),              // 21: pubsub.v1.ListQueuesResponse
+	(*CreateQueueRequest)(nil),              // 22: pubsub.v1.CreateQueueRequest
+	(*CreateQueueResponse)(nil),             // 23: pubsub.v1.CreateQueueResponse
+	(*GetQueueUrlRequest)(nil),              // 24: pubsub.v1.GetQueueUrlRequest
+	(*GetQueueUrlResponse)(nil),             // 25: pubsub.v1.GetQueueUrlResponse
+	(*DeleteQueueRequest)(nil),              // 26: pubsub.v1.DeleteQueueRequest
+	(*DeleteQueueResponse)(nil),             // 27: pubsub.v1.DeleteQueueResponse
+	(*SendMessageRequest)(nil),              // 28: pubsub.v1.SendMessageRequest
+	(*SendMessageResponse)(nil),             // 29: pubsub.v1.SendMessageResponse
+	(*Message)(nil),                         // 30: pubsub.v1.Message
+	(*ReceiveMessageRequest)(nil),           // 31: pubsub.v1.ReceiveMessageRequest
+	(*ReceiveMessageResponse)(nil),          // 32: pubsub.v1.ReceiveMessageResponse
+	(*StreamMessagesRequest)(nil),           // 33: pubsub.v1.StreamMessagesRequest
+	(*DeleteMessageRequest)(nil),            // 34: pubsub.v1.DeleteMessageRequest
+	(*DeleteMessageResponse)(nil),           // 35: pubsub.v1.DeleteMessageResponse
+	(*ChangeMessageVisibilityRequest)(nil),  // 36: pubsub.v1.ChangeMessageVisibilityRequest
+	(*ChangeMessageVisibilityResponse)(nil), // 37: pubsub.v1.ChangeMessageVisibilityResponse
+	nil,                                     // 38: pubsub.v1.SendMessageRequest.AttributesEntry
+	nil,                                     // 39: pubsub.v1.SendMessageRequest.BinaryAttributesEntry
+	nil,                                     // 40: pubsub.v1.Message.AttributesEntry
+	nil,                                     // 41: pubsub.v1.Message.BinaryAttributesEntry
+	(*timestamppb.Timestamp)(nil),           // 42: google.protobuf.Timestamp
+}
+var file_pubsub_v1_pubsub_proto_depIdxs = []int32{
+	0,  // 0: pubsub.v1.ListTopicsResponse.topics:type_name -> pubsub.v1.Topic
+	1,  // 1: pubsub.v1.CreateTopicRequest.attributes:type_name -> pubsub.v1.TopicAttributes
+	6,  // 2: pubsub.v1.ListSubscriptionsResponse.subscriptions:type_name -> pubsub.v1.Subscription
+	17, // 3: pubsub.v1.PublishStreamResponse.results:type_name -> pubsub.v1.PublishResult
+	19, // 4: pubsub.v1.CreateQueueRequest.attributes:type_name -> pubsub.v1.QueueAttributes
+	38, // 5: pubsub.v1.SendMessageRequest.attributes:type_name -> pubsub.v1.SendMessageRequest.AttributesEntry
+	39, // 6: pubsub.v1.SendMessageRequest.binary_attributes:type_name -> pubsub.v1.SendMessageRequest.BinaryAttributesEntry
+	40, // 7: pubsub.v1.Message.attributes:type_name -> pubsub.v1.Message.AttributesEntry
+	41, // 8: pubsub.v1.Message.binary_attributes:type_name -> pubsub.v1.Message.BinaryAttributesEntry
+	42, // 9: pubsub.v1.Message.sent_at:type_name -> google.protobuf.Timestamp
+	30, // 10: pubsub.v1.ReceiveMessageResponse.message:type_name -> pubsub.v1.Message
+	2,  // 11: pubsub.v1.PubSub.ListTopics:input_type -> pubsub.v1.ListTopicsRequest
+	4,  // 12: pubsub.v1.PubSub.CreateTopic:input_type -> pubsub.v1.CreateTopicRequest
+	7,  // 13: pubsub.v1.PubSub.ListSubscriptions:input_type -> pubsub.v1.ListSubscriptionsRequest
+	9,  // 14: pubsub.v1.PubSub.SubscribeEmail:input_type -> pubsub.v1.SubscribeEmailRequest
+	11, // 15: pubsub.v1.PubSub.SubscribeQueue:input_type -> pubsub.v1.SubscribeQueueRequest
+	13, // 16: pubsub.v1.PubSub.Unsubscribe:input_type -> pubsub.v1.UnsubscribeRequest
+	15, // 17: pubsub.v1.PubSub.Publish:input_type -> pubsub.v1.PublishRequest
+	15, // 18: pubsub.v1.PubSub.PublishStream:input_type -> pubsub.v1.PublishRequest
+	20, // 19: pubsub.v1.PubSub.ListQueues:input_type -> pubsub.v1.ListQueuesRequest
+	22, // 20: pubsub.v1.PubSub.CreateQueue:input_type -> pubsub.v1.CreateQueueRequest
+	24, // 21: pubsub.v1.PubSub.GetQueueUrl:input_type -> pubsub.v1.GetQueueUrlRequest
+	26, // 22: pubsub.v1.PubSub.DeleteQueue:input_type -> pubsub.v1.DeleteQueueRequest
+	28, // 23: pubsub.v1.PubSub.SendMessage:input_type -> pubsub.v1.SendMessageRequest
+	31, // 24: pubsub.v1.PubSub.ReceiveMessage:input_type -> pubsub.v1.ReceiveMessageRequest
+	33, // 25: pubsub.v1.PubSub.StreamMessages:input_type -> pubsub.v1.StreamMessagesRequest
+	34, // 26: pubsub.v1.PubSub.DeleteMessage:input_type -> pubsub.v1.DeleteMessageRequest
+	36, // 27: pubsub.v1.PubSub.ChangeMessageVisibility:input_type -> pubsub.v1.ChangeMessageVisibilityRequest
+	3,  // 28: pubsub.v1.PubSub.ListTopics:output_type -> pubsub.v1.ListTopicsResponse
+	5,  // 29: pubsub.v1.PubSub.CreateTopic:output_type -> pubsub.v1.CreateTopicResponse
+	8,  // 30: pubsub.v1.PubSub.ListSubscriptions:output_type -> pubsub.v1.ListSubscriptionsResponse
+	10, // 31: pubsub.v1.PubSub.SubscribeEmail:output_type -> pubsub.v1.SubscribeEmailResponse
+	12, // 32: pubsub.v1.PubSub.SubscribeQueue:output_type -> pubsub.v1.SubscribeQueueResponse
+	14, // 33: pubsub.v1.PubSub.Unsubscribe:output_type -> pubsub.v1.UnsubscribeResponse
+	16, // 34: pubsub.v1.PubSub.Publish:output_type -> pubsub.v1.PublishResponse
+	18, // 35: pubsub.v1.PubSub.PublishStream:output_type -> pubsub.v1.PublishStreamResponse
+	21, // 36: pubsub.v1.PubSub.ListQueues:output_type -> pubsub.v1.ListQueuesResponse
+	23, // 37: pubsub.v1.PubSub.CreateQueue:output_type -> pubsub.v1.CreateQueueResponse
+	25, // 38: pubsub.v1.PubSub.GetQueueUrl:output_type -> pubsub.v1.GetQueueUrlResponse
+	27, // 39: pubsub.v1.PubSub.DeleteQueue:output_type -> pubsub.v1.DeleteQueueResponse
+	29, // 40: pubsub.v1.PubSub.SendMessage:output_type -> pubsub.v1.SendMessageResponse
+	32, // 41: pubsub.v1.PubSub.ReceiveMessage:output_type -> pubsub.v1.ReceiveMessageResponse
+	30, // 42: pubsub.v1.PubSub.StreamMessages:output_type -> pubsub.v1.Message
+	35, // 43: pubsub.v1.PubSub.DeleteMessage:output_type -> pubsub.v1.DeleteMessageResponse
+	37, // 44: pubsub.v1.PubSub.ChangeMessageVisibility:output_type -> pubsub.v1.ChangeMessageVisibilityResponse
+	28, // [28:45] is the sub-list for method output_type
+	11, // [11:28] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_pubsub_v1_pubsub_proto_init() }
+func file_pubsub_v1_pubsub_proto_init() {
+	if File_pubsub_v1_pubsub_proto != nil {
+		return
+	}
+	file_pubsub_v1_pubsub_proto_msgTypes[1].OneofWrappers = []any{}
+	file_pubsub_v1_pubsub_proto_msgTypes[15].OneofWrappers = []any{
+		(*PublishRequest_Message)(nil),
+		(*PublishRequest_Data)(nil),
+	}
+	file_pubsub_v1_pubsub_proto_msgTypes[19].OneofWrappers = []any{}
+	file_pubsub_v1_pubsub_proto_msgTypes[28].OneofWrappers = []any{
+		(*SendMessageRequest_Body)(nil),
+		(*SendMessageRequest_Data)(nil),
+	}
+	file_pubsub_v1_pubsub_proto_msgTypes[30].OneofWrappers = []any{
+		(*Message_Body)(nil),
+		(*Message_Data)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pubsub_v1_pubsub_proto_rawDesc), len(file_pubsub_v1_pubsub_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   42,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pubsub_v1_pubsub_proto_goTypes,
+		DependencyIndexes: file_pubsub_v1_pubsub_proto_depIdxs,
+		MessageInfos:      file_pubsub_v1_pubsub_proto_msgTypes,
+	}.Build()
+	File_pubsub_v1_pubsub_proto = out.File
+	file_pubsub_v1_pubsub_proto_goTypes = nil
+	file_pubsub_v1_pubsub_proto_depIdxs = nil
+}
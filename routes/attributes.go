@@ -0,0 +1,180 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/models"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+	"pub-sub-service/tags"
+
+	"github.com/gin-gonic/gin"
+)
+
+func getTopicAttributes(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.GetTopicAttributes(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get topic attributes"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setTopicAttributes(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var topicAttributes notification.TopicAttributes
+
+	if !bindJSON(context, &topicAttributes) {
+		return
+	}
+
+	res, err := models.SetTopicAttributes(context.Request.Context(), topicARN, topicAttributes)
+	if errors.Is(err, notification.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not set topic attributes"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func listTopicTags(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.ListTopicTags(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list topic tags"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func tagTopic(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var tagInput models.TagInput
+
+	if !bindJSON(context, &tagInput) {
+		return
+	}
+
+	res, err := models.TagTopic(context.Request.Context(), topicARN, tagInput)
+	if errors.Is(err, tags.ErrInvalidTags) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not tag topic"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func untagTopic(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.UntagTopic(context.Request.Context(), topicARN, context.QueryArray("key"))
+	if errors.Is(err, tags.ErrInvalidTags) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not untag topic"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getQueueAttributes(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.GetQueueAttributes(context.Request.Context(), queueName)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get queue attributes"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setQueueAttributes(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var queueAttributes queue.QueueAttributes
+
+	if !bindJSON(context, &queueAttributes) {
+		return
+	}
+
+	res, err := models.SetQueueAttributes(context.Request.Context(), queueName, queueAttributes)
+	if errors.Is(err, queue.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not set queue attributes"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func listQueueTags(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.ListQueueTags(context.Request.Context(), queueName)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list queue tags"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func tagQueue(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var tagInput models.TagInput
+
+	if !bindJSON(context, &tagInput) {
+		return
+	}
+
+	res, err := models.TagQueue(context.Request.Context(), queueName, tagInput)
+	if errors.Is(err, tags.ErrInvalidTags) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not tag queue"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func untagQueue(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.UntagQueue(context.Request.Context(), queueName, context.QueryArray("key"))
+	if errors.Is(err, tags.ErrInvalidTags) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not untag queue"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/models"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func getTopicEncryption(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.GetTopicEncryption(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get topic encryption"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setTopicEncryption(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var topicEncryption notification.TopicEncryption
+
+	if !bindJSON(context, &topicEncryption) {
+		return
+	}
+
+	res, err := models.SetTopicEncryption(context.Request.Context(), topicARN, topicEncryption)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not set topic encryption"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getQueueEncryption(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.GetQueueEncryption(context.Request.Context(), queueName)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get queue encryption"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setQueueEncryption(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var queueEncryption queue.QueueEncryption
+
+	if !bindJSON(context, &queueEncryption) {
+		return
+	}
+
+	res, err := models.SetQueueEncryption(context.Request.Context(), queueName, queueEncryption)
+	if errors.Is(err, queue.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not set queue encryption"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
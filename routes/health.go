@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+	"pub-sub-service/health"
+	"pub-sub-service/version"
+
+	"github.com/gin-gonic/gin"
+)
+
+func healthz(context *gin.Context) {
+	context.JSON(http.StatusOK, gin.H{"status": health.StatusOK, "version": version.Version})
+}
+
+func readyz(context *gin.Context) {
+	report := health.Readiness(context.Request.Context())
+	if report.Status != health.StatusOK {
+		context.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	context.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,152 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"pub-sub-service/idempotency"
+	"pub-sub-service/logging"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// recordingWriter keeps a copy of the response body for idempotent replays
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent makes publish and send requests with an Idempotency-Key header
+// happen once per topic or queue: a repeat of a completed request gets the
+// original response, with an Idempotent-Replayed header, instead of being
+// published or sent again. Responses with a 5xx status are not kept, so the
+// request can be retried.
+func idempotent(context *gin.Context) {
+	key := context.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		context.Next()
+		return
+	}
+	if !idempotency.ValidKey(key) {
+		context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": "Idempotency-Key must be 1 to 255 printable ASCII characters"})
+		return
+	}
+
+	body, err := io.ReadAll(context.Request.Body)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		context.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"message": "request body too large"})
+		return
+	}
+	if err != nil {
+		context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": "could not read request body"})
+		return
+	}
+	context.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	ctx := context.Request.Context()
+	logger := logging.FromContext(ctx)
+	scope := idempotencyScope(context)
+	keys := idempotency.Default()
+
+	fingerprint := requestFingerprint(context.Request, body)
+	result, err := keys.Begin(ctx, scope, key, fingerprint)
+	if errors.Is(err, idempotency.ErrInProgress) {
+		context.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": "a request with this Idempotency-Key is in progress"})
+		return
+	}
+	if errors.Is(err, idempotency.ErrMismatch) {
+		context.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"message": "Idempotency-Key was used for a different request"})
+		return
+	}
+	if err != nil {
+		logger.Error("could not check idempotency key", slog.Any("error", err))
+		context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "could not check idempotency key"})
+		return
+	}
+	if result != nil {
+		logger.Info("replaying idempotent request", slog.String("scope", scope))
+		context.Header(idempotentReplayedHeader, "true")
+		context.Data(result.StatusCode, gin.MIMEJSON, result.Body)
+		context.Abort()
+		return
+	}
+
+	writer := &recordingWriter{ResponseWriter: context.Writer}
+	context.Writer = writer
+	context.Next()
+
+	// The client may be gone once the handler has responded
+	ctx = detached(ctx)
+
+	status := writer.Status()
+	if status >= http.StatusInternalServerError {
+		if err := keys.Abandon(ctx, scope, key); err != nil {
+			logger.Warn("could not release idempotency key", slog.Any("error", err))
+		}
+		return
+	}
+
+	err = keys.Complete(ctx, scope, key, idempotency.Result{
+		Fingerprint: fingerprint,
+		StatusCode:  status,
+		Body:        writer.body.Bytes(),
+	})
+	if err != nil {
+		logger.Error("could not store idempotent result", slog.Any("error", err))
+	}
+}
+
+// detached keeps the values of ctx, such as its logger and trace, without
+// its cancellation
+func detached(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// idempotencyScope is the topic or queue a request targets, so that the
+// legacy and /v1 routes share keys
+func idempotencyScope(context *gin.Context) string {
+	if topicARN := context.Param("topicARN"); topicARN != "" {
+		return "topic:" + topicARN
+	}
+	return "queue:" + context.Param("queueName")
+}
+
+// requestFingerprint covers the body and the headers that shape how it is
+// read: its content type and, for binary CloudEvents, the ce- headers
+func requestFingerprint(request *http.Request, body []byte) string {
+	var headers []string
+	for name, values := range request.Header {
+		if strings.HasPrefix(strings.ToLower(name), "ce-") {
+			headers = append(headers, strings.ToLower(name)+"="+strings.Join(values, ","))
+		}
+	}
+	sort.Strings(headers)
+
+	return idempotency.Fingerprint(
+		[]byte(request.Method),
+		[]byte(request.Header.Get("Content-Type")),
+		[]byte(strings.Join(headers, "\n")),
+		body,
+	)
+}
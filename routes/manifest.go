@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"pub-sub-service/logging"
+	"pub-sub-service/manifest"
+	"pub-sub-service/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func manifestRequest(context *gin.Context) ([]byte, manifest.Options, bool) {
+	data, err := io.ReadAll(context.Request.Body)
+	if err != nil {
+		logging.FromContext(context.Request.Context()).Warn("could not read request body", slog.Any("error", err))
+		context.JSON(http.StatusBadRequest, gin.H{"message": "could not read request body"})
+		return nil, manifest.Options{}, false
+	}
+
+	options := manifest.Options{Prune: context.Query("prune") == "true"}
+	return data, options, true
+}
+
+func planManifest(context *gin.Context) {
+	data, options, ok := manifestRequest(context)
+	if !ok {
+		return
+	}
+
+	res, err := models.PlanManifest(context.Request.Context(), data, options)
+	if errors.Is(err, manifest.ErrInvalidManifest) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not plan manifest"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func applyManifest(context *gin.Context) {
+	data, options, ok := manifestRequest(context)
+	if !ok {
+		return
+	}
+
+	res, err := models.ApplyManifest(context.Request.Context(), data, options)
+	if errors.Is(err, manifest.ErrInvalidManifest) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var applyErr *manifest.ApplyError
+	if errors.As(err, &applyErr) {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error(), "plan": res.Response})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not apply manifest"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	_ "embed"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+)
+
+// openAPISpec documents every route; keep it in step with RegisterRoutes
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	return yaml.YAMLToJSON(openAPISpec)
+})
+
+func getOpenAPIYAML(context *gin.Context) {
+	context.Data(http.StatusOK, "application/yaml", openAPISpec)
+}
+
+func getOpenAPIJSON(context *gin.Context) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not render OpenAPI document"})
+		return
+	}
+
+	context.Data(http.StatusOK, "application/json", spec)
+}
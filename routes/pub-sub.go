@@ -1,14 +1,21 @@
 package routes
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"pub-sub-service/cloudevents"
+	"pub-sub-service/logging"
 	"pub-sub-service/models"
+	"pub-sub-service/payload"
+	"pub-sub-service/schema"
+	notification "pub-sub-service/sns"
 
 	"github.com/gin-gonic/gin"
 )
 
 func listTopics(context *gin.Context) {
-	res, err := models.ListTopics()
+	res, err := models.ListTopics(context.Request.Context())
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list topics"})
 		return
@@ -20,13 +27,15 @@ func listTopics(context *gin.Context) {
 func createTopic(context *gin.Context) {
 	var createTopicInput models.CreateTopicInput
 
-	err := context.ShouldBindJSON(&createTopicInput)
-	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse request body"})
+	if !bindJSON(context, &createTopicInput) {
 		return
 	}
 
-	res, err := models.CreateTopic(createTopicInput)
+	res, err := models.CreateTopic(context.Request.Context(), createTopicInput)
+	if errors.Is(err, notification.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not create topic"})
 		return
@@ -38,7 +47,7 @@ func createTopic(context *gin.Context) {
 func listSubscriptions(context *gin.Context) {
 	topicARN := context.Param("topicARN")
 
-	res, err := models.ListSubscriptions(topicARN)
+	res, err := models.ListSubscriptions(context.Request.Context(), topicARN)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list subscriptions to topic"})
 		return
@@ -52,13 +61,11 @@ func subscribeEmailToTopic(context *gin.Context) {
 
 	var subscribeEmailToTopicInput models.SubscribeEmailToTopicInput
 
-	err := context.ShouldBindJSON(&subscribeEmailToTopicInput)
-	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse request body"})
+	if !bindJSON(context, &subscribeEmailToTopicInput) {
 		return
 	}
 
-	res, err := models.SubscribeEmailToTopic(topicARN, subscribeEmailToTopicInput)
+	res, err := models.SubscribeEmailToTopic(context.Request.Context(), topicARN, subscribeEmailToTopicInput)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not subscribe email to topic"})
 		return
@@ -72,13 +79,11 @@ func subscribeQueueToTopic(context *gin.Context) {
 
 	var subscribeQueueToTopicInput models.SubscribeQueueToTopicInput
 
-	err := context.ShouldBindJSON(&subscribeQueueToTopicInput)
-	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse request body"})
+	if !bindJSON(context, &subscribeQueueToTopicInput) {
 		return
 	}
 
-	res, err := models.SubscribeQueueToTopic(topicARN, subscribeQueueToTopicInput)
+	res, err := models.SubscribeQueueToTopic(context.Request.Context(), topicARN, subscribeQueueToTopicInput)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not subscribe queue to topic"})
 		return
@@ -92,13 +97,11 @@ func unsubscribeFromTopic(context *gin.Context) {
 
 	var unsubscribeFromTopicInput models.UnsubscribeFromTopicInput
 
-	err := context.ShouldBindJSON(&unsubscribeFromTopicInput)
-	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse request body"})
+	if !bindJSON(context, &unsubscribeFromTopicInput) {
 		return
 	}
 
-	res, err := models.UnsubscribeFromTopic(topicARN, unsubscribeFromTopicInput)
+	res, err := models.UnsubscribeFromTopic(context.Request.Context(), topicARN, unsubscribeFromTopicInput)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not unsubscribe subscription ID from topic"})
 		return
@@ -110,19 +113,60 @@ func unsubscribeFromTopic(context *gin.Context) {
 func publishMessageToAllTopicSubscribers(context *gin.Context) {
 	topicARN := context.Param("topicARN")
 
-	var publishMessageInput models.PublishMessageInput
-
-	err := context.ShouldBindJSON(&publishMessageInput)
-	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse request body"})
+	var res *models.Response
+	var err error
+	status := http.StatusOK
+
+	// CloudEvents arrive in structured mode or in binary mode with ce-* headers
+	if cloudevents.IsCloudEvent(context.Request) {
+		event, parseErr := cloudevents.FromRequest(context.Request)
+		if parseErr != nil {
+			logging.FromContext(context.Request.Context()).Warn("could not parse CloudEvent", slog.Any("error", parseErr))
+			context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse CloudEvent: " + parseErr.Error()})
+			return
+		}
+
+		res, err = models.PublishCloudEvent(context.Request.Context(), topicARN, event)
+	} else {
+		var publishMessageInput models.PublishMessageInput
+
+		if !bindJSON(context, &publishMessageInput) {
+			return
+		}
+
+		// SNS cannot delay delivery, so any delay goes through the scheduler
+		if publishMessageInput.Delay() > 0 {
+			res, err = models.SchedulePublish(context.Request.Context(), topicARN, publishMessageInput)
+			status = http.StatusAccepted
+		} else {
+			res, err = models.PublishMessageToAllTopicSubscribers(context.Request.Context(), topicARN, publishMessageInput)
+		}
+	}
+	if errors.Is(err, payload.ErrUnknownCompression) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "unknown compression"})
+		return
+	}
+	if errors.Is(err, models.ErrCloudEventRequired) {
+		context.JSON(http.StatusUnsupportedMediaType, gin.H{"message": "topic only accepts CloudEvents"})
+		return
+	}
+	if errors.Is(err, notification.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var validationErr *schema.ValidationError
+	if errors.As(err, &validationErr) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message":       "message does not match topic schema",
+			"schemaVersion": validationErr.Version,
+			"errors":        validationErr.Errors,
+		})
 		return
 	}
-
-	res, err := models.PublishMessageToAllTopicSubscribers(topicARN, publishMessageInput)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not publish message"})
 		return
 	}
 
-	context.JSON(http.StatusOK, res)
+	context.JSON(status, res)
 }
\ No newline at end of file
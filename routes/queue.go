@@ -0,0 +1,165 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/models"
+	"pub-sub-service/payload"
+	queue "pub-sub-service/sqs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func listQueues(context *gin.Context) {
+	res, err := models.ListQueues(context.Request.Context())
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list queues"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func createQueue(context *gin.Context) {
+	var createQueueInput models.CreateQueueInput
+
+	if !bindJSON(context, &createQueueInput) {
+		return
+	}
+
+	res, err := models.CreateQueue(context.Request.Context(), createQueueInput)
+	if errors.Is(err, queue.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not create queue"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getQueueURL(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.GetQueueURL(context.Request.Context(), queueName)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get queue URL"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func deleteQueue(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.DeleteQueue(context.Request.Context(), queueName)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not delete queue"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func sendMessage(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var sendMessageInput models.SendMessageInput
+
+	if !bindJSON(context, &sendMessageInput) {
+		return
+	}
+
+	// Delays SQS cannot hold go through the scheduler
+	if sendMessageInput.Delay() > queue.MaxDelay {
+		res, err := models.ScheduleSend(context.Request.Context(), queueName, sendMessageInput)
+		if errors.Is(err, queue.ErrInvalidAttributes) {
+			context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		if errors.Is(err, queue.ErrQueueNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{"message": "queue not found"})
+			return
+		}
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"message": "could not schedule message"})
+			return
+		}
+
+		context.JSON(http.StatusAccepted, res)
+		return
+	}
+
+	res, err := models.SendMessage(context.Request.Context(), queueName, sendMessageInput)
+	if errors.Is(err, payload.ErrUnknownCompression) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "unknown compression"})
+		return
+	}
+	if errors.Is(err, queue.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not send message"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func receiveMessage(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var receiveMessageInput models.ReceiveMessageInput
+
+	if !bindJSON(context, &receiveMessageInput) {
+		return
+	}
+
+	res, err := models.ReceiveMessage(context.Request.Context(), queueName, receiveMessageInput)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not receive message"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func deleteMessage(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var deleteMessageInput models.DeleteMessageInput
+
+	if !bindJSON(context, &deleteMessageInput) {
+		return
+	}
+
+	res, err := models.DeleteMessage(context.Request.Context(), queueName, deleteMessageInput)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not delete message"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func changeMessageVisibility(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var changeMessageVisibilityInput models.ChangeMessageVisibilityInput
+
+	if !bindJSON(context, &changeMessageVisibilityInput) {
+		return
+	}
+
+	res, err := models.ChangeMessageVisibility(context.Request.Context(), queueName, changeMessageVisibilityInput)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not change message visibility"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
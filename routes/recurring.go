@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/models"
+	"pub-sub-service/scheduler"
+	notification "pub-sub-service/sns"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func listRecurring(context *gin.Context) {
+	res, err := models.ListRecurring(context.Request.Context())
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list recurring schedules"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getRecurring(context *gin.Context) {
+	name := context.Param("scheduleName")
+
+	res, err := models.GetRecurring(context.Request.Context(), name)
+	if errors.Is(err, scheduler.ErrScheduleNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "recurring schedule not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get recurring schedule"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func putRecurring(context *gin.Context) {
+	name := context.Param("scheduleName")
+
+	var recurringInput models.RecurringInput
+
+	if !bindJSON(context, &recurringInput) {
+		return
+	}
+
+	res, created, err := models.PutRecurring(context.Request.Context(), name, recurringInput)
+	if errors.Is(err, notification.ErrTopicNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "topic not found"})
+		return
+	}
+	if errors.Is(err, scheduler.ErrInvalidSchedule) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid recurring schedule", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not save recurring schedule"})
+		return
+	}
+
+	if created {
+		context.JSON(http.StatusCreated, res)
+		return
+	}
+	context.JSON(http.StatusOK, res)
+}
+
+func deleteRecurring(context *gin.Context) {
+	name := context.Param("scheduleName")
+
+	res, err := models.DeleteRecurring(context.Request.Context(), name)
+	if errors.Is(err, scheduler.ErrScheduleNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "recurring schedule not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not delete recurring schedule"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func recurringHistory(context *gin.Context) {
+	name := context.Param("scheduleName")
+
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid limit"})
+		return
+	}
+
+	res, err := models.RecurringHistory(context.Request.Context(), name, limit)
+	if errors.Is(err, scheduler.ErrScheduleNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "recurring schedule not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get recurring schedule history"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
@@ -1,26 +1,112 @@
 package routes
 
-import "github.com/gin-gonic/gin"
+import (
+	"pub-sub-service/metrics"
+	"pub-sub-service/validation"
+
+	"github.com/gin-gonic/gin"
+)
 
 func RegisterRoutes(server *gin.Engine) {
+	validation.Register()
+
+	// Malformed topic ARNs and queue names are rejected before any handler
+	server.Use(validatePathParams)
+
+	// Versioned API addressing topics by name
+	registerV1(server)
+
+	// Legacy API addressing topics by ARN, kept for existing clients
+	legacy := server.Group("", deprecated)
+
 	// ListTopics
-	server.GET("/topics", listTopics)
+	legacy.GET("/topics", listTopics)
 
 	// CreateTopic
-	server.POST("/topics", createTopic)
+	legacy.POST("/topics", createTopic)
 
 	// ListSubscriptions
-	server.GET("/topics/:topicARN/subscriptions", listSubscriptions)
+	legacy.GET("/topics/:topicARN/subscriptions", listSubscriptions)
 
 	// SubscribeEmailToTopic
-	server.PUT("/topics/:topicARN/subscribe/email", subscribeEmailToTopic)
+	legacy.PUT("/topics/:topicARN/subscribe/email", subscribeEmailToTopic)
 
 	// SubscribeQueueToTopic
-	server.PUT("/topics/:topicARN/subscribe/queue", subscribeQueueToTopic)
+	legacy.PUT("/topics/:topicARN/subscribe/queue", subscribeQueueToTopic)
 
 	// UnsubscribeFromTopic
-	server.PUT("/topics/:topicARN/unsubscribe", unsubscribeFromTopic)
+	legacy.PUT("/topics/:topicARN/unsubscribe", unsubscribeFromTopic)
 
 	// PublishMessageToAllTopicSubscribers
-	server.POST("/topics/:topicARN", publishMessageToAllTopicSubscribers)
+	legacy.POST("/topics/:topicARN", idempotent, publishMessageToAllTopicSubscribers)
+
+	// ListQueues
+	legacy.GET("/queues", listQueues)
+
+	// CreateQueue
+	legacy.POST("/queues", createQueue)
+
+	// GetQueueURL
+	legacy.GET("/queues/:queueName", getQueueURL)
+
+	// DeleteQueue
+	legacy.DELETE("/queues/:queueName", deleteQueue)
+
+	// SendMessage
+	legacy.POST("/queues/:queueName/messages", idempotent, sendMessage)
+
+	// ReceiveMessage
+	legacy.PUT("/queues/:queueName/messages/receive", receiveMessage)
+
+	// DeleteMessage
+	legacy.PUT("/queues/:queueName/messages/delete", deleteMessage)
+
+	// ConfigureVisibilityTimeout
+	legacy.PUT("/queues/:queueName/messages/visibility", changeMessageVisibility)
+
+	// Schemas
+	legacy.GET("/topics/:topicARN/schemas", listSchemas)
+	legacy.POST("/topics/:topicARN/schemas", registerSchema)
+	legacy.GET("/topics/:topicARN/schemas/config", getSchemaConfig)
+	legacy.PUT("/topics/:topicARN/schemas/config", setSchemaConfig)
+	legacy.GET("/topics/:topicARN/schemas/:version", getSchema)
+
+	// Attributes and tags
+	legacy.GET("/topics/:topicARN/attributes", getTopicAttributes)
+	legacy.PUT("/topics/:topicARN/attributes", setTopicAttributes)
+	legacy.GET("/topics/:topicARN/tags", listTopicTags)
+	legacy.PUT("/topics/:topicARN/tags", tagTopic)
+	legacy.DELETE("/topics/:topicARN/tags", untagTopic)
+	legacy.GET("/queues/:queueName/attributes", getQueueAttributes)
+	legacy.PUT("/queues/:queueName/attributes", setQueueAttributes)
+	legacy.GET("/queues/:queueName/tags", listQueueTags)
+	legacy.PUT("/queues/:queueName/tags", tagQueue)
+	legacy.DELETE("/queues/:queueName/tags", untagQueue)
+
+	// Server-side encryption
+	legacy.GET("/topics/:topicARN/encryption", getTopicEncryption)
+	legacy.PUT("/topics/:topicARN/encryption", setTopicEncryption)
+	legacy.GET("/queues/:queueName/encryption", getQueueEncryption)
+	legacy.PUT("/queues/:queueName/encryption", setQueueEncryption)
+
+	// Settings
+	legacy.GET("/topics/:topicARN/settings", getTopicSettings)
+	legacy.PUT("/topics/:topicARN/settings", setTopicSettings)
+	legacy.GET("/queues/:queueName/settings", getQueueSettings)
+	legacy.PUT("/queues/:queueName/settings", setQueueSettings)
+
+	// Manifests
+	legacy.POST("/manifest/plan", planManifest)
+	legacy.POST("/manifest/apply", applyManifest)
+
+	// Health
+	server.GET("/healthz", healthz)
+	server.GET("/readyz", readyz)
+
+	// Metrics
+	server.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// API documentation
+	server.GET("/openapi.yaml", getOpenAPIYAML)
+	server.GET("/openapi.json", getOpenAPIJSON)
 }
\ No newline at end of file
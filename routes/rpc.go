@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"pub-sub-service/logging"
+	"pub-sub-service/models"
+	"pub-sub-service/payload"
+	"pub-sub-service/rpc"
+	"pub-sub-service/schema"
+	notification "pub-sub-service/sns"
+	queue "pub-sub-service/sqs"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publishRequest publishes a request to a topic and responds with the reply
+// once it arrives, or 504 after the timeout.
+func publishRequest(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	timeout, ok := requestTimeout(context)
+	if !ok {
+		return
+	}
+
+	var publishMessageInput models.PublishMessageInput
+
+	if !bindJSON(context, &publishMessageInput) {
+		return
+	}
+	if publishMessageInput.Delay() > 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "requests cannot be delayed"})
+		return
+	}
+
+	res, err := models.PublishRequest(context.Request.Context(), topicARN, publishMessageInput, timeout)
+	if errors.Is(err, models.ErrCloudEventRequired) {
+		context.JSON(http.StatusUnsupportedMediaType, gin.H{"message": "topic only accepts CloudEvents"})
+		return
+	}
+	var validationErr *schema.ValidationError
+	if errors.As(err, &validationErr) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message":       "message does not match topic schema",
+			"schemaVersion": validationErr.Version,
+			"errors":        validationErr.Errors,
+		})
+		return
+	}
+	if !requestError(context, err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+// sendRequest sends a request to a queue and responds with the reply once it
+// arrives, or 504 after the timeout.
+func sendRequest(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	timeout, ok := requestTimeout(context)
+	if !ok {
+		return
+	}
+
+	var sendMessageInput models.SendMessageInput
+
+	if !bindJSON(context, &sendMessageInput) {
+		return
+	}
+	if sendMessageInput.Delay() > 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "requests cannot be delayed"})
+		return
+	}
+
+	res, err := models.SendRequest(context.Request.Context(), queueName, sendMessageInput, timeout)
+	if !requestError(context, err) {
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+// replyWriteMargin covers sending the request and writing the reply on top
+// of the wait for it
+const replyWriteMargin = 5 * time.Second
+
+// requestTimeout reads the timeout query parameter, in seconds, and extends
+// the response's write deadline, which HTTP_WRITE_TIMEOUT may set below it
+func requestTimeout(context *gin.Context) (time.Duration, bool) {
+	timeout := models.DefaultRequestTimeout
+	if value := context.Query("timeout"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		timeout = time.Duration(seconds) * time.Second
+		if err != nil || timeout <= 0 || timeout > models.MaxRequestTimeout {
+			context.JSON(http.StatusBadRequest, gin.H{"message": "timeout must be 1 to 60 seconds"})
+			return 0, false
+		}
+	}
+
+	err := http.NewResponseController(context.Writer).SetWriteDeadline(time.Now().Add(timeout + replyWriteMargin))
+	if err != nil {
+		logging.FromContext(context.Request.Context()).Warn("unable to extend write deadline", slog.Any("error", err))
+	}
+	return timeout, true
+}
+
+// requestError responds to the errors publish and send requests share, and
+// reports whether there was none
+func requestError(context *gin.Context, err error) bool {
+	if errors.Is(err, rpc.ErrTimeout) {
+		context.JSON(http.StatusGatewayTimeout, gin.H{"message": "no reply before the timeout"})
+		return false
+	}
+	if errors.Is(err, payload.ErrUnknownCompression) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "unknown compression"})
+		return false
+	}
+	if errors.Is(err, queue.ErrInvalidAttributes) || errors.Is(err, notification.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return false
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not send request"})
+		return false
+	}
+	return true
+}
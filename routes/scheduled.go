@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/models"
+	"pub-sub-service/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func listScheduled(context *gin.Context) {
+	res, err := models.ListScheduled(context.Request.Context())
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list scheduled messages"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getScheduled(context *gin.Context) {
+	id := context.Param("scheduledID")
+
+	res, err := models.GetScheduled(context.Request.Context(), id)
+	if errors.Is(err, scheduler.ErrNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "scheduled message not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get scheduled message"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func cancelScheduled(context *gin.Context) {
+	id := context.Param("scheduledID")
+
+	res, err := models.CancelScheduled(context.Request.Context(), id)
+	if errors.Is(err, scheduler.ErrNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "scheduled message not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not cancel scheduled message"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/models"
+	"pub-sub-service/schema"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func listSchemas(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.ListSchemas(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list schemas"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func registerSchema(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var registerSchemaInput models.RegisterSchemaInput
+
+	if !bindJSON(context, &registerSchemaInput) {
+		return
+	}
+
+	res, err := models.RegisterSchema(context.Request.Context(), topicARN, registerSchemaInput)
+	if errors.Is(err, schema.ErrInvalidSchema) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid schema", "error": err.Error()})
+		return
+	}
+	if errors.Is(err, schema.ErrIncompatible) {
+		context.JSON(http.StatusConflict, gin.H{"message": "schema is incompatible", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not register schema"})
+		return
+	}
+
+	context.JSON(http.StatusCreated, res)
+}
+
+func getSchema(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	version, err := strconv.Atoi(context.Param("version"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid schema version"})
+		return
+	}
+
+	res, err := models.GetSchema(context.Request.Context(), topicARN, version)
+	if errors.Is(err, schema.ErrNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "schema not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get schema"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getSchemaConfig(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.GetSchemaConfig(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get schema config"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setSchemaConfig(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var schemaConfigInput models.SchemaConfigInput
+
+	if !bindJSON(context, &schemaConfigInput) {
+		return
+	}
+
+	res, err := models.SetSchemaConfig(context.Request.Context(), topicARN, schemaConfigInput)
+	if errors.Is(err, schema.ErrNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "schema version not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "could not set schema config", "error": err.Error()})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"pub-sub-service/envelope"
+	"pub-sub-service/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func getTopicSettings(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.GetTopicSettings(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get topic settings"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setTopicSettings(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var topicSettingsInput models.TopicSettingsInput
+
+	if !bindJSON(context, &topicSettingsInput) {
+		return
+	}
+
+	res, err := models.SetTopicSettings(context.Request.Context(), topicARN, topicSettingsInput)
+	if errors.Is(err, envelope.ErrNotConfigured) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "encryption is not configured"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not set topic settings"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func getQueueSettings(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	res, err := models.GetQueueSettings(context.Request.Context(), queueName)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not get queue settings"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func setQueueSettings(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	var queueSettingsInput models.QueueSettingsInput
+
+	if !bindJSON(context, &queueSettingsInput) {
+		return
+	}
+
+	res, err := models.SetQueueSettings(context.Request.Context(), queueName, queueSettingsInput)
+	if errors.Is(err, envelope.ErrNotConfigured) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "encryption is not configured"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not set queue settings"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,195 @@
+package routes
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"pub-sub-service/logging"
+	"pub-sub-service/models"
+	notification "pub-sub-service/sns"
+	"pub-sub-service/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerV1 registers the versioned API, which addresses topics by name
+// instead of ARN. Handlers shared with the legacy routes read the resolved
+// ARN from the topicARN parameter.
+func registerV1(server *gin.Engine) {
+	v1 := server.Group("/v1")
+
+	v1.GET("/topics", listNamedTopics)
+	v1.POST("/topics", createTopic)
+
+	topic := v1.Group("/topics/:topicName", resolveTopic)
+	topic.GET("", getTopicAttributes)
+	topic.DELETE("", deleteTopic)
+	topic.POST("/messages", idempotent, publishMessageToAllTopicSubscribers)
+	topic.POST("/requests", publishRequest)
+	topic.GET("/subscriptions", listTopicSubscriptions)
+	topic.POST("/subscriptions", subscribe)
+	topic.DELETE("/subscriptions/:subscriptionID", unsubscribe)
+	topic.GET("/schemas", listSchemas)
+	topic.POST("/schemas", registerSchema)
+	topic.GET("/schemas/config", getSchemaConfig)
+	topic.PUT("/schemas/config", setSchemaConfig)
+	topic.GET("/schemas/:version", getSchema)
+	topic.GET("/attributes", getTopicAttributes)
+	topic.PUT("/attributes", setTopicAttributes)
+	topic.GET("/tags", listTopicTags)
+	topic.PUT("/tags", tagTopic)
+	topic.DELETE("/tags", untagTopic)
+	topic.GET("/encryption", getTopicEncryption)
+	topic.PUT("/encryption", setTopicEncryption)
+	topic.GET("/settings", getTopicSettings)
+	topic.PUT("/settings", setTopicSettings)
+
+	v1.GET("/queues", listQueues)
+	v1.POST("/queues", createQueue)
+
+	queues := v1.Group("/queues/:queueName")
+	queues.GET("", getQueueURL)
+	queues.DELETE("", deleteQueue)
+	queues.POST("/messages", idempotent, sendMessage)
+	queues.POST("/requests", sendRequest)
+	queues.POST("/messages/receive", receiveMessage)
+	queues.DELETE("/messages", deleteReceivedMessage)
+	queues.PUT("/messages/visibility", changeMessageVisibility)
+	queues.GET("/attributes", getQueueAttributes)
+	queues.PUT("/attributes", setQueueAttributes)
+	queues.GET("/tags", listQueueTags)
+	queues.PUT("/tags", tagQueue)
+	queues.DELETE("/tags", untagQueue)
+	queues.GET("/encryption", getQueueEncryption)
+	queues.PUT("/encryption", setQueueEncryption)
+	queues.GET("/settings", getQueueSettings)
+	queues.PUT("/settings", setQueueSettings)
+
+	v1.GET("/scheduled", listScheduled)
+	v1.GET("/scheduled/:scheduledID", getScheduled)
+	v1.DELETE("/scheduled/:scheduledID", cancelScheduled)
+
+	v1.GET("/recurring", listRecurring)
+	v1.GET("/recurring/:scheduleName", getRecurring)
+	v1.PUT("/recurring/:scheduleName", putRecurring)
+	v1.DELETE("/recurring/:scheduleName", deleteRecurring)
+	v1.GET("/recurring/:scheduleName/history", recurringHistory)
+
+	v1.POST("/manifest/plan", planManifest)
+	v1.POST("/manifest/apply", applyManifest)
+}
+
+// resolveTopic resolves the topicName path parameter to the topic's ARN and
+// adds it as the topicARN parameter.
+func resolveTopic(context *gin.Context) {
+	topicName := context.Param("topicName")
+
+	topicARN, err := notification.ResolveTopicARN(context.Request.Context(), topicName)
+	if errors.Is(err, notification.ErrTopicNotFound) {
+		context.AbortWithStatusJSON(http.StatusNotFound, gin.H{"message": "topic not found"})
+		return
+	}
+	if err != nil {
+		logging.FromContext(context.Request.Context()).Error("could not resolve topic", slog.String("topic", topicName), slog.Any("error", err))
+		context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "could not resolve topic"})
+		return
+	}
+
+	context.Params = append(context.Params, gin.Param{Key: "topicARN", Value: topicARN})
+	context.Next()
+}
+
+// deprecated marks responses from the legacy routes, which address topics by
+// ARN, as superseded by the /v1 API.
+func deprecated(context *gin.Context) {
+	context.Header("Deprecation", "true")
+	context.Header("Link", `</v1>; rel="successor-version"`)
+	context.Next()
+}
+
+func listNamedTopics(context *gin.Context) {
+	res, err := models.ListNamedTopics(context.Request.Context())
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list topics"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func deleteTopic(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.DeleteTopic(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not delete topic"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func listTopicSubscriptions(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	res, err := models.ListTopicSubscriptions(context.Request.Context(), topicARN)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not list subscriptions to topic"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func subscribe(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+
+	var subscribeInput models.SubscribeInput
+
+	if !bindJSON(context, &subscribeInput) {
+		return
+	}
+
+	res, err := models.Subscribe(context.Request.Context(), topicARN, subscribeInput)
+	if errors.Is(err, notification.ErrInvalidAttributes) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not subscribe to topic"})
+		return
+	}
+
+	context.JSON(http.StatusCreated, res)
+}
+
+func unsubscribe(context *gin.Context) {
+	topicARN := context.Param("topicARN")
+	subscriptionID := context.Param("subscriptionID")
+
+	res, err := models.Unsubscribe(context.Request.Context(), topicARN, subscriptionID)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not unsubscribe from topic"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
+
+func deleteReceivedMessage(context *gin.Context) {
+	queueName := context.Param("queueName")
+
+	deleteMessageInput := models.DeleteMessageInput{ReceiptHandle: context.Query("receiptHandle")}
+	if fields := validation.Value("receiptHandle", deleteMessageInput.ReceiptHandle, "required"); fields != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid request", "fields": fields})
+		return
+	}
+
+	res, err := models.DeleteMessage(context.Request.Context(), queueName, deleteMessageInput)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not delete message"})
+		return
+	}
+
+	context.JSON(http.StatusOK, res)
+}
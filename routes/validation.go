@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"log/slog"
+	"net/http"
+	"pub-sub-service/logging"
+	"pub-sub-service/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pathRules are the binding rules checked on path parameters
+var pathRules = map[string]string{
+	"topicARN":       "required," + validation.RuleTopicARN,
+	"topicName":      "required," + validation.RuleTopicName,
+	"queueName":      "required," + validation.RuleQueueName,
+	"subscriptionID": "required,uuid",
+	"scheduleName":   "required," + validation.RuleScheduleName,
+}
+
+// bindJSON binds the request body into input. When that fails it responds
+// with 400, listing the failed fields if the body did not pass validation,
+// and returns false.
+func bindJSON(context *gin.Context, input any) bool {
+	err := context.ShouldBindJSON(input)
+	if err == nil {
+		return true
+	}
+
+	logging.FromContext(context.Request.Context()).Warn("could not parse request body", slog.Any("error", err))
+
+	if fields := validation.Fields(err); fields != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid request", "fields": fields})
+		return false
+	}
+
+	context.JSON(http.StatusBadRequest, gin.H{"message": "could not parse request body"})
+	return false
+}
+
+// validatePathParams rejects requests whose topic, queue, subscription or
+// schedule path parameters are malformed before they reach SNS or SQS.
+func validatePathParams(context *gin.Context) {
+	var fields []validation.FieldError
+	for _, param := range context.Params {
+		rule, ok := pathRules[param.Key]
+		if !ok {
+			continue
+		}
+		fields = append(fields, validation.Value(param.Key, param.Value, rule)...)
+	}
+
+	if len(fields) > 0 {
+		logging.FromContext(context.Request.Context()).Warn("invalid path parameters", slog.Any("fields", fields))
+		context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": "invalid request", "fields": fields})
+		return
+	}
+
+	context.Next()
+}
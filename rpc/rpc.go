@@ -0,0 +1,357 @@
+// Package rpc makes request/reply calls over topics and queues. A request
+// carries a CorrelationId attribute and, in ReplyTo, the name of a temporary
+// queue belonging to the requesting instance. Responders send their reply to
+// that queue with the same CorrelationId, and the requester hands it to the
+// caller waiting for it.
+package rpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	queue "pub-sub-service/sqs"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Message attributes of requests and replies
+const (
+	CorrelationIDAttribute = queue.CorrelationIDAttribute
+	ReplyToAttribute       = queue.ReplyToAttribute
+)
+
+var (
+	ErrTimeout   = errors.New("no reply before the timeout")
+	ErrNoReplyTo = errors.New("message has no valid ReplyTo attribute")
+)
+
+const (
+	replyQueuePrefix = api.ReplyQueuePrefix
+	// ReplyQueueTag marks reply queues with the time their requester last
+	// reported in, so that SweepReplyQueues can delete those left behind by
+	// instances that did not shut down cleanly
+	ReplyQueueTag = "pub-sub-service:reply-queue"
+
+	// replyHeartbeat is how often a requester renews its reply queue's tag,
+	// and replyQueueIdle how long after the last renewal a queue is swept
+	replyHeartbeat = 5 * time.Minute
+	replyQueueIdle = 3 * replyHeartbeat
+
+	// listeners is how many replies are received at a time
+	listeners = 4
+	// replyVisibilityTimeout covers handing a reply over and deleting it
+	replyVisibilityTimeout = 30
+)
+
+var unsafeQueueNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// SendFunc publishes or sends a request with attributes added to it.
+type SendFunc func(ctx context.Context, attributes map[string]string) error
+
+// Requester sends requests and waits for their replies on its reply queue,
+// which it creates with the first request and deletes on Close.
+type Requester struct {
+	queueName string
+
+	mu      sync.Mutex
+	pending map[string]chan *queue.ReceivedMessage
+	started bool
+	stop    context.CancelFunc
+	done    sync.WaitGroup
+}
+
+func NewRequester(queueName string) *Requester {
+	return &Requester{queueName: queueName, pending: map[string]chan *queue.ReceivedMessage{}}
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultRequester *Requester
+)
+
+// Default returns the requester of this instance, whose reply queue is named
+// after the host with a random suffix.
+func Default() *Requester {
+	defaultOnce.Do(func() {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "instance"
+		}
+		hostname = unsafeQueueNameChars.ReplaceAllString(hostname, "-")
+		if len(hostname) > 48 {
+			hostname = hostname[:48]
+		}
+		defaultRequester = NewRequester(replyQueuePrefix + hostname + "-" + newID()[:8])
+	})
+	return defaultRequester
+}
+
+// QueueName returns the name of the reply queue.
+func (r *Requester) QueueName() string {
+	return r.queueName
+}
+
+// Request sends a request with send and returns the reply, or ErrTimeout if
+// none arrives within timeout. A reply arriving later is dropped.
+func (r *Requester) Request(ctx context.Context, timeout time.Duration, send SendFunc) (*queue.ReceivedMessage, error) {
+	if err := r.start(ctx); err != nil {
+		return nil, err
+	}
+
+	correlationID := newID()
+	replies := make(chan *queue.ReceivedMessage, 1)
+
+	r.mu.Lock()
+	r.pending[correlationID] = replies
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, correlationID)
+		r.mu.Unlock()
+	}()
+
+	err := send(ctx, map[string]string{
+		CorrelationIDAttribute: correlationID,
+		ReplyToAttribute:       r.queueName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-timer.C:
+		logging.FromContext(ctx).Warn("request timed out", slog.String("correlation_id", correlationID), slog.Duration("timeout", timeout))
+		return nil, ErrTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// start creates the reply queue and starts receiving from it, once
+func (r *Requester) start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return nil
+	}
+
+	// Replies are only of use while a caller waits, so they are kept for
+	// the shortest time SQS allows, and received with long polling
+	_, err := queue.CreateQueue(ctx, r.queueName, queue.QueueAttributes{
+		DelaySeconds:                  aws.Int64(0),
+		MessageRetentionPeriod:        aws.Int64(60),
+		ReceiveMessageWaitTimeSeconds: aws.Int64(20),
+	})
+	if err != nil {
+		return err
+	}
+	r.heartbeat(ctx)
+
+	listenCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	r.stop = stop
+	r.started = true
+	for range listeners {
+		r.done.Add(1)
+		go func() {
+			defer r.done.Done()
+			r.listen(listenCtx)
+		}()
+	}
+
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(replyHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-listenCtx.Done():
+				return
+			case <-ticker.C:
+				r.heartbeat(listenCtx)
+			}
+		}
+	}()
+	return nil
+}
+
+// heartbeat tags the reply queue with the current time, so that it is not
+// swept while in use
+func (r *Requester) heartbeat(ctx context.Context) {
+	_, err := queue.TagQueue(ctx, r.queueName, map[string]string{ReplyQueueTag: time.Now().UTC().Format(time.RFC3339)})
+	if err != nil {
+		logging.FromContext(ctx).Warn("unable to tag reply queue", slog.String("queue", r.queueName), slog.Any("error", err))
+	}
+}
+
+func (r *Requester) listen(ctx context.Context) {
+	logger := logging.FromContext(ctx).With(slog.String("queue", r.queueName))
+
+	for ctx.Err() == nil {
+		reply, err := queue.ReceiveMessage(ctx, r.queueName, replyVisibilityTimeout)
+		if err != nil {
+			// Back off rather than spin while SQS is unavailable
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		if reply == nil {
+			continue
+		}
+
+		r.deliver(ctx, reply)
+
+		if _, err := queue.DeleteMessage(ctx, r.queueName, reply.ReceiptHandle); err != nil {
+			logger.Warn("unable to delete reply", slog.String("message_id", reply.MessageID), slog.Any("error", err))
+		}
+	}
+}
+
+// deliver hands a reply to the request waiting for it
+func (r *Requester) deliver(ctx context.Context, reply *queue.ReceivedMessage) {
+	correlationID := reply.Attributes[CorrelationIDAttribute]
+
+	r.mu.Lock()
+	replies, ok := r.pending[correlationID]
+	r.mu.Unlock()
+
+	if !ok {
+		logging.FromContext(ctx).Info("dropping reply to no waiting request", slog.String("correlation_id", correlationID))
+		return
+	}
+
+	// Only the first of duplicate replies is used
+	select {
+	case replies <- reply:
+	default:
+	}
+}
+
+// Close stops receiving replies and deletes the reply queue, if it was
+// created.
+func (r *Requester) Close(ctx context.Context) error {
+	r.mu.Lock()
+	started := r.started
+	r.started = false
+	r.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	r.stop()
+	r.done.Wait()
+
+	_, err := queue.DeleteQueue(ctx, r.queueName)
+	return err
+}
+
+// SweepReplyQueues deletes, on each interval until ctx is cancelled, the
+// reply queues of other requesters that have not been renewed for a while,
+// which instances that did not shut down cleanly leave behind.
+func (r *Requester) SweepReplyQueues(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Requester) sweep(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	queueUrls, err := queue.ListQueues(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, queueUrl := range queueUrls {
+		queueName := queueUrl[strings.LastIndex(queueUrl, "/")+1:]
+		if !strings.HasPrefix(queueName, replyQueuePrefix) || queueName == r.queueName {
+			continue
+		}
+
+		queueTags, err := queue.ListQueueTags(ctx, queueName)
+		if err != nil {
+			continue
+		}
+		// Queues without a renewal time are not known to be reply queues
+		renewed, err := time.Parse(time.RFC3339, queueTags[ReplyQueueTag])
+		if err != nil || time.Since(renewed) < replyQueueIdle {
+			continue
+		}
+
+		if _, err := queue.DeleteQueue(ctx, queueName); err != nil {
+			logger.Warn("unable to delete idle reply queue", slog.String("queue", queueName), slog.Any("error", err))
+			continue
+		}
+		logger.Info("deleted idle reply queue", slog.String("queue", queueName), slog.Time("renewed", renewed))
+	}
+}
+
+// Reply sends reply to the queue named in the request's ReplyTo attribute,
+// with the request's CorrelationId. Only reply queues are replied to: any
+// other ReplyTo is ErrNoReplyTo.
+func Reply(ctx context.Context, request *queue.ReceivedMessage, reply queue.Message) error {
+	replyTo := request.Attributes[ReplyToAttribute]
+	if !strings.HasPrefix(replyTo, replyQueuePrefix) {
+		return ErrNoReplyTo
+	}
+
+	reply.CorrelationID = request.Attributes[CorrelationIDAttribute]
+	if reply.Timestamp.IsZero() {
+		reply.Timestamp = time.Now()
+	}
+
+	_, err := queue.SendMessage(ctx, replyTo, reply)
+	return err
+}
+
+// ResponderFunc handles a request and returns the reply to send, or nil to
+// send none.
+type ResponderFunc func(ctx context.Context, request *queue.ReceivedMessage) (*queue.Message, error)
+
+// Responder turns fn into a queue.Handler that replies to each request, for
+// use with a receive loop or a queue.Deduplicator. A request whose handler
+// fails gets no reply, and is left to be redelivered.
+func Responder(fn ResponderFunc) queue.Handler {
+	return func(ctx context.Context, request *queue.ReceivedMessage) error {
+		reply, err := fn(ctx, request)
+		if err != nil || reply == nil {
+			return err
+		}
+		return Reply(ctx, request, *reply)
+	}
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,474 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+	"pub-sub-service/store"
+	"strings"
+	"text/template"
+	"time"
+
+	// Schedules name IANA time zones, which must resolve without a system
+	// zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("recurring schedule not found")
+	ErrInvalidSchedule  = errors.New("invalid recurring schedule")
+)
+
+// Missed run policies, applied to runs that are due more than missedRunGrace
+// ago, such as while no instance was running
+const (
+	// MissedRunsOnce fires the latest missed run once
+	MissedRunsOnce = "once"
+	// MissedRunsSkip fires none of the missed runs
+	MissedRunsSkip = "skip"
+	// MissedRunsAll fires every missed run, up to maxCatchUp, and records
+	// the rest as skipped
+	MissedRunsAll = "all"
+)
+
+// Statuses of recurring runs in the execution history
+const (
+	RunRunning   = "running"
+	RunDelivered = "delivered"
+	RunFailed    = "failed"
+	RunSkipped   = "skipped"
+)
+
+const (
+	schedulePrefix = "recurring/schedules/"
+	statePrefix    = "recurring/state/"
+	historyPrefix  = "recurring/history/"
+	leaderKey      = "recurring/leader"
+
+	// runTimeLayout makes history keys sort by scheduled time
+	runTimeLayout = "20060102T150405Z"
+
+	missedRunGrace   = time.Minute
+	maxCatchUp       = 100
+	historyRetention = 7 * 24 * time.Hour
+	minLeaseTTL      = 15 * time.Second
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Recurring is a schedule that publishes a templated message to a topic, or
+// sends one to a queue, on each run of a cron expression, and Run an entry in
+// its execution history; both are shared with the client. The template is
+// executed with RunData.
+type (
+	Recurring = api.Recurring
+	Run       = api.Run
+)
+
+// RunData is what a schedule's template is executed with. ScheduledTime is
+// in the schedule's time zone.
+type RunData struct {
+	Name          string
+	ScheduledTime time.Time
+	FiredTime     time.Time
+}
+
+// recurringState is kept apart from the schedule so that firing a run never
+// overwrites an update made through the API
+type recurringState struct {
+	NextRun time.Time  `json:"nextRun"`
+	LastRun *time.Time `json:"lastRun,omitempty"`
+}
+
+// parseRecurring checks a schedule and returns its cron schedule, time zone and
+// template.
+func parseRecurring(r *Recurring) (cron.Schedule, *time.Location, *template.Template, error) {
+	if r.Kind != KindTopic && r.Kind != KindQueue {
+		return nil, nil, nil, fmt.Errorf("%w: unknown target kind %q", ErrInvalidSchedule, r.Kind)
+	}
+	if strings.HasPrefix(r.Cron, "CRON_TZ=") || strings.HasPrefix(r.Cron, "TZ=") {
+		return nil, nil, nil, fmt.Errorf("%w: set the time zone in timeZone", ErrInvalidSchedule)
+	}
+
+	schedule, err := cronParser.Parse(r.Cron)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: cron: %v", ErrInvalidSchedule, err)
+	}
+
+	location := time.UTC
+	if r.TimeZone != "" {
+		location, err = time.LoadLocation(r.TimeZone)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: time zone: %v", ErrInvalidSchedule, err)
+		}
+	}
+
+	tmpl, err := template.New(r.Name).Option("missingkey=error").Parse(r.Template)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: template: %v", ErrInvalidSchedule, err)
+	}
+
+	switch r.MissedRuns {
+	case MissedRunsOnce, MissedRunsSkip, MissedRunsAll:
+	default:
+		return nil, nil, nil, fmt.Errorf("%w: unknown missed run policy %q", ErrInvalidSchedule, r.MissedRuns)
+	}
+
+	return schedule, location, tmpl, nil
+}
+
+// PutRecurring creates or replaces a schedule, reporting whether it was
+// created. A new schedule, or one whose cron expression or time zone
+// changed, runs next at the first run after now.
+func (s *Scheduler) PutRecurring(ctx context.Context, recurring Recurring) (*Recurring, bool, error) {
+	if recurring.MissedRuns == "" {
+		recurring.MissedRuns = MissedRunsOnce
+	}
+	schedule, location, _, err := parseRecurring(&recurring)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current, err := s.GetRecurring(ctx, recurring.Name)
+	if err != nil && !errors.Is(err, ErrScheduleNotFound) {
+		return nil, false, err
+	}
+	created := current == nil
+
+	now := time.Now().UTC()
+	recurring.CreatedAt = now
+	recurring.UpdatedAt = now
+	recurring.NextRun = nil
+	recurring.LastRun = nil
+	if !created {
+		recurring.CreatedAt = current.CreatedAt
+	}
+
+	data, err := json.Marshal(recurring)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.store.Put(ctx, schedulePrefix+recurring.Name, data, 0); err != nil {
+		return nil, false, err
+	}
+
+	var state recurringState
+	if !created {
+		state.LastRun = current.LastRun
+		if current.NextRun != nil {
+			state.NextRun = *current.NextRun
+		}
+	}
+	if created || state.NextRun.IsZero() || recurring.Cron != current.Cron || recurring.TimeZone != current.TimeZone {
+		state.NextRun = schedule.Next(now.In(location)).UTC()
+		if err := s.putState(ctx, recurring.Name, state); err != nil {
+			return nil, false, err
+		}
+	}
+	recurring.NextRun = &state.NextRun
+	recurring.LastRun = state.LastRun
+
+	logging.FromContext(ctx).Info("saved recurring schedule",
+		slog.String("schedule", recurring.Name), slog.String("cron", recurring.Cron), slog.Time("next_run", state.NextRun))
+	return &recurring, created, nil
+}
+
+// ListRecurring returns the schedules ordered by name.
+func (s *Scheduler) ListRecurring(ctx context.Context) ([]*Recurring, error) {
+	items, err := s.store.List(ctx, schedulePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*Recurring, 0, len(items))
+	for _, item := range items {
+		recurring, err := s.decodeRecurring(ctx, item.Value)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, recurring)
+	}
+	return schedules, nil
+}
+
+func (s *Scheduler) GetRecurring(ctx context.Context, name string) (*Recurring, error) {
+	if strings.Contains(name, "/") {
+		return nil, ErrScheduleNotFound
+	}
+
+	value, err := s.store.Get(ctx, schedulePrefix+name)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeRecurring(ctx, value)
+}
+
+// DeleteRecurring removes a schedule. Its execution history expires on its
+// own.
+func (s *Scheduler) DeleteRecurring(ctx context.Context, name string) error {
+	if _, err := s.GetRecurring(ctx, name); err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, schedulePrefix+name); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, statePrefix+name); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("deleted recurring schedule", slog.String("schedule", name))
+	return nil
+}
+
+// History returns up to limit of the most recent runs of a schedule, newest
+// first. Runs are kept for seven days.
+func (s *Scheduler) History(ctx context.Context, name string, limit int) ([]*Run, error) {
+	if _, err := s.GetRecurring(ctx, name); err != nil {
+		return nil, err
+	}
+
+	items, err := s.store.List(ctx, historyPrefix+name+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]*Run, 0, min(len(items), limit))
+	for i := len(items) - 1; i >= 0 && len(runs) < limit; i-- {
+		var run Run
+		if err := json.Unmarshal(items[i].Value, &run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, &run)
+	}
+	return runs, nil
+}
+
+func (s *Scheduler) decodeRecurring(ctx context.Context, data []byte) (*Recurring, error) {
+	var recurring Recurring
+	if err := json.Unmarshal(data, &recurring); err != nil {
+		return nil, err
+	}
+
+	state, err := s.getState(ctx, recurring.Name)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		recurring.NextRun = &state.NextRun
+		recurring.LastRun = state.LastRun
+	}
+	return &recurring, nil
+}
+
+// FireRecurring fires the runs of the recurring schedules that are due, if
+// this instance holds the leader lease, and stops if the lease is lost. A
+// renewal races with another instance taking over an expired lease, so each
+// run is also claimed before it fires.
+func (s *Scheduler) FireRecurring(ctx context.Context, leaseTTL time.Duration, dispatch DispatchFunc) error {
+	leader, err := s.lease.Acquire(ctx, leaseTTL)
+	if err != nil || !leader {
+		return err
+	}
+
+	items, err := s.store.List(ctx, schedulePrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil || !s.lease.Held(ctx, leaseTTL) {
+			break
+		}
+
+		var recurring Recurring
+		if err := json.Unmarshal(item.Value, &recurring); err != nil {
+			logging.FromContext(ctx).Error("unable to decode recurring schedule", slog.String("key", item.Key), slog.Any("error", err))
+			continue
+		}
+		if err := s.fireDue(ctx, &recurring, dispatch); err != nil {
+			logging.FromContext(ctx).Warn("unable to fire recurring schedule", slog.String("schedule", recurring.Name), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) fireDue(ctx context.Context, recurring *Recurring, dispatch DispatchFunc) error {
+	schedule, location, tmpl, err := parseRecurring(recurring)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state, err := s.getState(ctx, recurring.Name)
+	if errors.Is(err, store.ErrNotFound) {
+		return s.putState(ctx, recurring.Name, recurringState{NextRun: schedule.Next(now.In(location)).UTC()})
+	}
+	if err != nil {
+		return err
+	}
+	if state.NextRun.After(now) {
+		return nil
+	}
+
+	// Collect the runs due since the last tick. After a long outage only the
+	// oldest maxCatchUp are kept, but all of them are counted
+	var due []time.Time
+	var latest time.Time
+	total := 0
+	for run := state.NextRun; !run.IsZero() && !run.After(now); run = schedule.Next(run.In(location)) {
+		if len(due) < maxCatchUp {
+			due = append(due, run.UTC())
+		}
+		latest = run.UTC()
+		total++
+	}
+	if total == 0 {
+		return nil
+	}
+
+	switch {
+	case recurring.Paused:
+	case recurring.MissedRuns == MissedRunsAll:
+		for _, run := range due {
+			s.fire(ctx, recurring, tmpl, location, run, 0, dispatch)
+		}
+		// The runs beyond maxCatchUp are recorded as skipped, the latest
+		// standing in for the rest
+		if dropped := total - len(due); dropped > 0 {
+			s.recordRun(ctx, recurring, latest, RunSkipped, dropped-1, nil)
+		}
+	case recurring.MissedRuns == MissedRunsSkip && now.Sub(latest) > missedRunGrace:
+		s.recordRun(ctx, recurring, latest, RunSkipped, total-1, nil)
+	default:
+		s.fire(ctx, recurring, tmpl, location, latest, total-1, dispatch)
+	}
+
+	state.NextRun = schedule.Next(now.In(location)).UTC()
+	state.LastRun = &latest
+	return s.putState(ctx, recurring.Name, *state)
+}
+
+// fire claims a run through its history entry, so that it fires once even
+// if two instances briefly both hold the lease, and dispatches its message.
+func (s *Scheduler) fire(ctx context.Context, recurring *Recurring, tmpl *template.Template, location *time.Location, run time.Time, missed int, dispatch DispatchFunc) {
+	logger := logging.FromContext(ctx).With(slog.String("schedule", recurring.Name), slog.Time("scheduled_at", run))
+
+	entry := Run{ScheduledAt: run, Status: RunRunning, Missed: missed, Instance: s.instance}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logger.Error("unable to encode recurring run", slog.Any("error", err))
+		return
+	}
+	claimed, err := s.store.PutIfAbsent(ctx, historyKey(recurring.Name, run), data, historyRetention)
+	if err != nil {
+		logger.Error("unable to claim recurring run", slog.Any("error", err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	firedAt := time.Now().UTC()
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, RunData{Name: recurring.Name, ScheduledTime: run.In(location), FiredTime: firedAt})
+	if err == nil {
+		err = dispatch(ctx, &Message{
+			ID:        recurring.Name + "-" + run.Format(runTimeLayout),
+			Kind:      recurring.Kind,
+			Target:    recurring.Target,
+			DeliverAt: run,
+			Payload:   runPayload(recurring, body.String()),
+			CreatedAt: firedAt,
+			Status:    StatusPending,
+		})
+	}
+
+	if err != nil {
+		logger.Error("recurring run failed", slog.Any("error", err))
+		s.recordRun(ctx, recurring, run, RunFailed, missed, err)
+		return
+	}
+
+	logger.Info("fired recurring schedule", slog.Int("missed", missed))
+	s.recordRun(ctx, recurring, run, RunDelivered, missed, nil)
+}
+
+// runPayload is the publish or send request of a run, in the form
+// DispatchFunc takes it
+func runPayload(r *Recurring, body string) json.RawMessage {
+	var request any
+	if r.Kind == KindTopic {
+		request = struct {
+			Message     string `json:"message"`
+			ContentType string `json:"contentType,omitempty"`
+		}{body, r.ContentType}
+	} else {
+		request = struct {
+			Subject     string            `json:"subject,omitempty"`
+			Body        string            `json:"body"`
+			ContentType string            `json:"contentType,omitempty"`
+			Attributes  map[string]string `json:"attributes,omitempty"`
+		}{r.Subject, body, r.ContentType, r.Attributes}
+	}
+
+	data, _ := json.Marshal(request)
+	return data
+}
+
+func (s *Scheduler) recordRun(ctx context.Context, recurring *Recurring, run time.Time, status string, missed int, runErr error) {
+	metrics.ObserveRecurringRun(recurring.Name, status)
+
+	entry := Run{ScheduledAt: run, Status: status, Missed: missed, Instance: s.instance}
+	if status != RunSkipped {
+		firedAt := time.Now().UTC()
+		entry.FiredAt = &firedAt
+	}
+	if runErr != nil {
+		entry.Error = runErr.Error()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := s.store.Put(ctx, historyKey(recurring.Name, run), data, historyRetention); err != nil {
+		logging.FromContext(ctx).Error("unable to record recurring run", slog.String("schedule", recurring.Name), slog.Any("error", err))
+	}
+}
+
+func historyKey(name string, run time.Time) string {
+	return historyPrefix + name + "/" + run.UTC().Format(runTimeLayout)
+}
+
+func (s *Scheduler) getState(ctx context.Context, name string) (*recurringState, error) {
+	value, err := s.store.Get(ctx, statePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+
+	var state recurringState
+	if err := json.Unmarshal(value, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *Scheduler) putState(ctx context.Context, name string, state recurringState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, statePrefix+name, data, 0)
+}
@@ -0,0 +1,227 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"pub-sub-service/store"
+)
+
+// runRecorder dispatches messages by recording when they were due
+type runRecorder struct {
+	runs []time.Time
+}
+
+func (r *runRecorder) dispatch(ctx context.Context, message *Message) error {
+	r.runs = append(r.runs, message.DeliverAt)
+	return nil
+}
+
+// history returns the runs recorded for a schedule, oldest first
+func history(t *testing.T, s *Scheduler, name string) []Run {
+	t.Helper()
+
+	items, err := s.store.List(context.Background(), historyPrefix+name+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs := make([]Run, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal(item.Value, &runs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return runs
+}
+
+func TestFireDueMissedRuns(t *testing.T) {
+	// An hourly schedule whose next run was due late by the given duration,
+	// so that it missed one run each hour after it
+	tests := []struct {
+		name       string
+		missedRuns string
+		paused     bool
+		late       time.Duration
+
+		wantFired   int
+		wantHistory []Run
+	}{
+		{
+			name:        "once fires the latest run",
+			missedRuns:  MissedRunsOnce,
+			late:        4*time.Hour + 30*time.Minute,
+			wantFired:   1,
+			wantHistory: []Run{{Status: RunDelivered, Missed: 4}},
+		},
+		{
+			name:        "skip records the latest run as skipped",
+			missedRuns:  MissedRunsSkip,
+			late:        4*time.Hour + 30*time.Minute,
+			wantHistory: []Run{{Status: RunSkipped, Missed: 4}},
+		},
+		{
+			name:        "skip fires a run due within the grace period",
+			missedRuns:  MissedRunsSkip,
+			late:        10 * time.Second,
+			wantFired:   1,
+			wantHistory: []Run{{Status: RunDelivered}},
+		},
+		{
+			name:       "all fires every run",
+			missedRuns: MissedRunsAll,
+			late:       2*time.Hour + 30*time.Minute,
+			wantFired:  3,
+			wantHistory: []Run{
+				{Status: RunDelivered},
+				{Status: RunDelivered},
+				{Status: RunDelivered},
+			},
+		},
+		{
+			name:       "paused fires nothing",
+			missedRuns: MissedRunsOnce,
+			paused:     true,
+			late:       4*time.Hour + 30*time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(store.NewMemory())
+			ctx := context.Background()
+			recurring := &Recurring{
+				Name:       "hourly",
+				Cron:       "@every 1h",
+				Kind:       KindTopic,
+				Target:     "arn:aws:sns:us-east-1:000000000000:reports",
+				Template:   "{{.Name}}",
+				MissedRuns: tt.missedRuns,
+				Paused:     tt.paused,
+			}
+
+			now := time.Now().UTC().Truncate(time.Second)
+			first := now.Add(-tt.late)
+			if err := s.putState(ctx, recurring.Name, recurringState{NextRun: first}); err != nil {
+				t.Fatal(err)
+			}
+
+			var rec runRecorder
+			if err := s.fireDue(ctx, recurring, rec.dispatch); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rec.runs) != tt.wantFired {
+				t.Fatalf("fired %d runs, want %d", len(rec.runs), tt.wantFired)
+			}
+			for i, run := range rec.runs {
+				// Every run fires under MissedRunsAll, otherwise the latest
+				want := first.Add(time.Duration(i) * time.Hour)
+				if tt.missedRuns != MissedRunsAll {
+					want = first.Add(tt.late.Truncate(time.Hour))
+				}
+				if !run.Equal(want) {
+					t.Errorf("run %d was due at %v, want %v", i, run, want)
+				}
+			}
+
+			got := history(t, s, recurring.Name)
+			if len(got) != len(tt.wantHistory) {
+				t.Fatalf("history has %d runs, want %d", len(got), len(tt.wantHistory))
+			}
+			for i, run := range got {
+				if run.Status != tt.wantHistory[i].Status || run.Missed != tt.wantHistory[i].Missed {
+					t.Errorf("run %d is %s missing %d, want %s missing %d",
+						i, run.Status, run.Missed, tt.wantHistory[i].Status, tt.wantHistory[i].Missed)
+				}
+			}
+
+			state, err := s.getState(ctx, recurring.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !state.NextRun.After(now) {
+				t.Errorf("next run is %v, want after %v", state.NextRun, now)
+			}
+			if latest := first.Add(tt.late.Truncate(time.Hour)); state.LastRun == nil || !state.LastRun.Equal(latest) {
+				t.Errorf("last run is %v, want %v", state.LastRun, latest)
+			}
+		})
+	}
+}
+
+func TestFireDueCatchUpLimit(t *testing.T) {
+	s := New(store.NewMemory())
+	ctx := context.Background()
+	recurring := &Recurring{
+		Name:       "hourly",
+		Cron:       "@every 1h",
+		Kind:       KindQueue,
+		Target:     "reports",
+		Template:   "{{.Name}}",
+		MissedRuns: MissedRunsAll,
+	}
+
+	missed := maxCatchUp + 50
+	first := time.Now().UTC().Truncate(time.Second).Add(-time.Duration(missed-1)*time.Hour - 30*time.Minute)
+	if err := s.putState(ctx, recurring.Name, recurringState{NextRun: first}); err != nil {
+		t.Fatal(err)
+	}
+
+	var rec runRecorder
+	if err := s.fireDue(ctx, recurring, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.runs) != maxCatchUp {
+		t.Fatalf("fired %d runs, want %d", len(rec.runs), maxCatchUp)
+	}
+
+	// The latest run stands in for the others beyond the limit
+	runs := history(t, s, recurring.Name)
+	if len(runs) != maxCatchUp+1 {
+		t.Fatalf("history has %d runs, want %d", len(runs), maxCatchUp+1)
+	}
+	latest := runs[len(runs)-1]
+	if want := first.Add(time.Duration(missed-1) * time.Hour); latest.Status != RunSkipped || latest.Missed != missed-maxCatchUp-1 || !latest.ScheduledAt.Equal(want) {
+		t.Fatalf("latest run is %+v, want skipped at %v missing %d", latest, want, missed-maxCatchUp-1)
+	}
+}
+
+func TestFireDueSchedulesNewRuns(t *testing.T) {
+	s := New(store.NewMemory())
+	ctx := context.Background()
+	recurring := &Recurring{
+		Name:       "hourly",
+		Cron:       "@every 1h",
+		Kind:       KindTopic,
+		Target:     "arn:aws:sns:us-east-1:000000000000:reports",
+		Template:   "{{.Name}}",
+		MissedRuns: MissedRunsOnce,
+	}
+
+	// A schedule without state runs next after now, missing nothing
+	var rec runRecorder
+	if err := s.fireDue(ctx, recurring, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	state, err := s.getState(ctx, recurring.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.NextRun.After(time.Now()) || state.LastRun != nil {
+		t.Fatalf("state is %+v, want the next run after now", state)
+	}
+
+	// Nothing fires before the next run
+	if err := s.fireDue(ctx, recurring, rec.dispatch); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.runs) != 0 {
+		t.Fatalf("fired %v before the next run", rec.runs)
+	}
+	if runs := history(t, s, recurring.Name); len(runs) != 0 {
+		t.Fatalf("history has %d runs, want none", len(runs))
+	}
+}
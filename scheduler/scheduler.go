@@ -0,0 +1,349 @@
+// Package scheduler holds messages for delivery at a later time, beyond the
+// 15 minutes SQS can delay a message, and fires recurring schedules.
+// Scheduled messages are persisted in the store, keyed by delivery time, and
+// dispatched when due by Run, which any number of instances may run: each
+// message is claimed with a lease before it is dispatched, and recurring
+// schedules are fired by the instance holding the leader lease.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+	"pub-sub-service/payload"
+	"pub-sub-service/store"
+	"pub-sub-service/worker"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNotFound = errors.New("scheduled message not found")
+
+// Kinds of scheduled message targets
+const (
+	KindTopic = "topic"
+	KindQueue = "queue"
+)
+
+// Statuses of scheduled messages. Delivered messages are removed.
+const (
+	StatusPending = "pending"
+	StatusFailed  = "failed"
+)
+
+const (
+	pendingPrefix = "scheduled/pending/"
+	failedPrefix  = "scheduled/failed/"
+	claimPrefix   = "scheduled/claims/"
+
+	// idTimeLayout makes IDs, and so pending keys, sort by delivery time
+	idTimeLayout = "20060102T150405.000000000Z"
+
+	// maxAttempts is how often a message is dispatched before it fails
+	maxAttempts = 5
+	// claimTTL bounds how long a crashed instance holds a claim; a message
+	// whose dispatch outlives it may be dispatched twice
+	claimTTL = time.Minute
+	// maxRetryDelay caps the backoff between dispatch attempts
+	maxRetryDelay = 5 * time.Minute
+)
+
+// Message is a message held for later delivery, shared with the client. For
+// encrypted topics and queues its payload is encrypted; see Plaintext.
+type Message = api.ScheduledMessage
+
+// Plaintext returns a message's payload, decrypted if it is encrypted.
+func Plaintext(ctx context.Context, m *Message) (json.RawMessage, error) {
+	if m.Encryption == "" {
+		return m.Payload, nil
+	}
+
+	var ciphertext []byte
+	if err := json.Unmarshal(m.Payload, &ciphertext); err != nil {
+		return nil, err
+	}
+	return payload.Open(ctx, ciphertext, m.Encryption)
+}
+
+// DispatchFunc publishes or sends a due message.
+type DispatchFunc func(ctx context.Context, message *Message) error
+
+// Scheduler stores scheduled messages and recurring schedules, and
+// dispatches them when due. Instance identifies it in leases and history.
+type Scheduler struct {
+	store    store.Store
+	instance string
+	lease    *worker.Lease
+}
+
+func New(s store.Store) *Scheduler {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "scheduler"
+	}
+	instance := hostname + "-" + hex.EncodeToString(suffix)
+	return &Scheduler{store: s, instance: instance, lease: worker.NewLease(s, leaderKey, instance)}
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultScheduler *Scheduler
+)
+
+// Default returns the scheduler backed by the default store.
+func Default() *Scheduler {
+	defaultOnce.Do(func() {
+		defaultScheduler = New(store.Default())
+	})
+	return defaultScheduler
+}
+
+// Schedule stores a message for delivery to a topic ARN or queue name at
+// deliverAt, encrypting request if encrypt is set. A time in the past
+// delivers it at the next dispatch.
+func (s *Scheduler) Schedule(ctx context.Context, kind, target string, deliverAt time.Time, request any, encrypt bool) (*Message, error) {
+	if kind != KindTopic && kind != KindQueue {
+		return nil, fmt.Errorf("unknown target kind %q", kind)
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var encryption string
+	if encrypt {
+		var ciphertext []byte
+		ciphertext, encryption, err = payload.Seal(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(ciphertext); err != nil {
+			return nil, err
+		}
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	deliverAt = deliverAt.UTC()
+	message := &Message{
+		ID:         deliverAt.Format(idTimeLayout) + "-" + hex.EncodeToString(suffix),
+		Kind:       kind,
+		Target:     target,
+		DeliverAt:  deliverAt,
+		Payload:    data,
+		CreatedAt:  time.Now().UTC(),
+		Status:     StatusPending,
+		Encryption: encryption,
+	}
+	if err := s.put(ctx, pendingPrefix, message); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("scheduled message",
+		slog.String("id", message.ID), slog.String("kind", kind), slog.String("target", target), slog.Time("deliver_at", deliverAt))
+	return message, nil
+}
+
+// List returns the pending messages in delivery order, followed by those
+// that failed.
+func (s *Scheduler) List(ctx context.Context) ([]*Message, error) {
+	var messages []*Message
+	for _, prefix := range []string{pendingPrefix, failedPrefix} {
+		items, err := s.store.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			message, err := decode(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// Get returns a pending or failed message.
+func (s *Scheduler) Get(ctx context.Context, id string) (*Message, error) {
+	if strings.Contains(id, "/") {
+		return nil, ErrNotFound
+	}
+
+	for _, prefix := range []string{pendingPrefix, failedPrefix} {
+		value, err := s.store.Get(ctx, prefix+id)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return decode(value)
+	}
+	return nil, ErrNotFound
+}
+
+// Cancel removes a pending or failed message. A message already being
+// dispatched may still be delivered.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	message, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	prefix := pendingPrefix
+	if message.Status == StatusFailed {
+		prefix = failedPrefix
+	}
+	if err := s.store.Delete(ctx, prefix+id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("cancelled scheduled message", slog.String("id", id))
+	return nil
+}
+
+// Run dispatches due messages, and fires due recurring schedules while this
+// instance leads, on each interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, dispatch DispatchFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	leaseTTL := max(3*interval, minLeaseTTL)
+
+	for {
+		if err := s.DispatchDue(ctx, dispatch); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Warn("unable to dispatch scheduled messages", slog.Any("error", err))
+		}
+		if err := s.FireRecurring(ctx, leaseTTL, dispatch); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Warn("unable to fire recurring schedules", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue dispatches the messages that are due. Failed dispatches are
+// retried with backoff, and moved to the failed messages after maxAttempts.
+func (s *Scheduler) DispatchDue(ctx context.Context, dispatch DispatchFunc) error {
+	// Pending keys start with the delivery time, so those up to now are due
+	now := time.Now().UTC()
+	items, err := s.store.ListRange(ctx, pendingPrefix, pendingPrefix+now.Format(idTimeLayout))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		message, err := decode(item.Value)
+		if err != nil {
+			logging.FromContext(ctx).Error("unable to decode scheduled message", slog.String("key", item.Key), slog.Any("error", err))
+			continue
+		}
+		if message.RetryAt != nil && message.RetryAt.After(now) {
+			continue
+		}
+
+		claimed, err := s.store.PutIfAbsent(ctx, claimPrefix+message.ID, []byte(now.Format(time.RFC3339Nano)), claimTTL)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		s.dispatch(ctx, message, dispatch)
+
+		if err := s.store.Delete(ctx, claimPrefix+message.ID); err != nil {
+			logging.FromContext(ctx).Warn("unable to release scheduled message claim", slog.String("id", message.ID), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) dispatch(ctx context.Context, message *Message, dispatch DispatchFunc) {
+	logger := logging.FromContext(ctx).With(slog.String("id", message.ID), slog.String("kind", message.Kind), slog.String("target", message.Target))
+
+	// A message cancelled since it was listed is not dispatched
+	if _, err := s.store.Get(ctx, pendingPrefix+message.ID); errors.Is(err, store.ErrNotFound) {
+		return
+	}
+
+	err := dispatch(ctx, message)
+	if err == nil {
+		metrics.ObserveScheduledDispatch(message.Kind, "delivered")
+		if err := s.store.Delete(ctx, pendingPrefix+message.ID); err != nil {
+			logger.Error("unable to remove delivered scheduled message", slog.Any("error", err))
+			return
+		}
+		logger.Info("delivered scheduled message", slog.Duration("lateness", time.Since(message.DeliverAt)))
+		return
+	}
+
+	message.Attempts++
+	message.LastError = err.Error()
+
+	if message.Attempts >= maxAttempts {
+		metrics.ObserveScheduledDispatch(message.Kind, "failed")
+		logger.Error("scheduled message failed", slog.Int("attempts", message.Attempts), slog.Any("error", err))
+
+		message.Status = StatusFailed
+		message.RetryAt = nil
+		if err := s.put(ctx, failedPrefix, message); err != nil {
+			logger.Error("unable to store failed scheduled message", slog.Any("error", err))
+			return
+		}
+		if err := s.store.Delete(ctx, pendingPrefix+message.ID); err != nil {
+			logger.Error("unable to remove failed scheduled message", slog.Any("error", err))
+		}
+		return
+	}
+
+	metrics.ObserveScheduledDispatch(message.Kind, "retried")
+	logger.Warn("unable to deliver scheduled message, will retry", slog.Int("attempts", message.Attempts), slog.Any("error", err))
+
+	retryAt := time.Now().UTC().Add(worker.RetryDelay(message.Attempts, maxRetryDelay))
+	message.RetryAt = &retryAt
+	if err := s.put(ctx, pendingPrefix, message); err != nil {
+		logger.Error("unable to store scheduled message retry", slog.Any("error", err))
+	}
+}
+
+func (s *Scheduler) put(ctx context.Context, prefix string, message *Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, prefix+message.ID, data, 0)
+}
+
+func decode(data []byte) (*Message, error) {
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
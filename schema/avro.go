@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamba/avro/v2"
+)
+
+const FormatAvro = "AVRO"
+
+type avroCodec struct {
+	schema avro.Schema
+}
+
+func parseAvro(definition []byte) (avro.Schema, error) {
+	// A fresh cache per parse lets later versions redefine named types.
+	parsed, err := avro.ParseWithCache(string(definition), "", &avro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return parsed, nil
+}
+
+func compileAvro(definition []byte) (*avroCodec, error) {
+	parsed, err := parseAvro(definition)
+	if err != nil {
+		return nil, err
+	}
+	return &avroCodec{schema: parsed}, nil
+}
+
+func (c *avroCodec) Validate(payload []byte) []string {
+	var value any
+	if err := avro.Unmarshal(c.schema, payload, &value); err != nil {
+		return []string{fmt.Sprintf("message is not valid Avro: %v", err)}
+	}
+	return nil
+}
+
+func (c *avroCodec) ToJSON(payload []byte) ([]byte, error) {
+	var value any
+	if err := avro.Unmarshal(c.schema, payload, &value); err != nil {
+		return nil, fmt.Errorf("unable to decode Avro message: %v", err)
+	}
+	return json.Marshal(value)
+}
+
+// avroCanRead uses the Avro schema resolution rules.
+func avroCanRead(reader, writer []byte) ([]string, error) {
+	readerSchema, err := parseAvro(reader)
+	if err != nil {
+		return nil, err
+	}
+	writerSchema, err := parseAvro(writer)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := avro.NewSchemaCompatibility().Compatible(readerSchema, writerSchema); err != nil {
+		return []string{err.Error()}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,210 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// checkCompatibility checks a new schema definition against the versions
+// already registered, as required by the compatibility rule. BACKWARD means
+// consumers using the new schema can read messages written with the previous
+// one, FORWARD the reverse, and FULL both; the transitive variants check
+// every earlier version instead of only the latest.
+func checkCompatibility(compatibility, format string, definition []byte, existing []Schema) error {
+	if compatibility == CompatibilityNone || len(existing) == 0 {
+		return nil
+	}
+
+	targets := existing[len(existing)-1:]
+	if strings.HasSuffix(compatibility, "_TRANSITIVE") {
+		targets = existing
+	}
+	mode := strings.TrimSuffix(compatibility, "_TRANSITIVE")
+
+	for _, target := range targets {
+		if target.Format != format {
+			return fmt.Errorf("%w with version %d: format changed from %s to %s", ErrIncompatible, target.Version, target.Format, format)
+		}
+
+		var problems []string
+		var err error
+		if mode == CompatibilityBackward || mode == CompatibilityFull {
+			problems, err = canRead(format, definition, target.Definition)
+			if err != nil {
+				return err
+			}
+		}
+		if mode == CompatibilityForward || mode == CompatibilityFull {
+			forward, err := canRead(format, target.Definition, definition)
+			if err != nil {
+				return err
+			}
+			problems = append(problems, forward...)
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("%w with version %d: %s", ErrIncompatible, target.Version, strings.Join(problems, "; "))
+		}
+	}
+
+	return nil
+}
+
+// canRead reports why a reader schema cannot read every message valid
+// against a writer schema.
+func canRead(format string, reader, writer []byte) ([]string, error) {
+	switch format {
+	case FormatJSON:
+		var readerDoc, writerDoc any
+		if err := json.Unmarshal(reader, &readerDoc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+		}
+		if err := json.Unmarshal(writer, &writerDoc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+		}
+		return jsonAccepts(readerDoc, writerDoc, "#"), nil
+	case FormatAvro:
+		return avroCanRead(reader, writer)
+	case FormatProtobuf:
+		return protobufCanRead(reader, writer)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidSchema, format)
+	}
+}
+
+// jsonAccepts is a structural check of whether the reader JSON Schema accepts
+// every instance the writer accepts. It compares the type, enum, required,
+// properties, additionalProperties and items keywords and errs on the side
+// of reporting a problem.
+func jsonAccepts(reader, writer any, path string) []string {
+	if accepted, ok := reader.(bool); ok {
+		if accepted {
+			return nil
+		}
+		if rejected, ok := writer.(bool); ok && !rejected {
+			return nil
+		}
+		return []string{path + ": schema rejects every value"}
+	}
+	if _, ok := writer.(bool); ok {
+		if writer == false {
+			return nil
+		}
+		writer = map[string]any{}
+	}
+
+	r, _ := reader.(map[string]any)
+	w, _ := writer.(map[string]any)
+
+	var problems []string
+
+	readerTypes, writerTypes := jsonTypes(r), jsonTypes(w)
+	if readerTypes != nil {
+		if writerTypes == nil {
+			problems = append(problems, fmt.Sprintf("%s: type narrowed to %s", path, strings.Join(keys(readerTypes), ", ")))
+		} else {
+			for t := range writerTypes {
+				if !readerTypes[t] && !(t == "integer" && readerTypes["number"]) {
+					problems = append(problems, fmt.Sprintf("%s: type %s is no longer allowed", path, t))
+				}
+			}
+		}
+	}
+
+	if readerEnum, ok := jsonEnum(r); ok {
+		writerEnum, ok := jsonEnum(w)
+		if !ok {
+			problems = append(problems, path+": enum added")
+		} else {
+			for value := range writerEnum {
+				if !readerEnum[value] {
+					problems = append(problems, fmt.Sprintf("%s: enum value %s removed", path, value))
+				}
+			}
+		}
+	}
+
+	writerRequired := stringSet(w["required"])
+	for field := range stringSet(r["required"]) {
+		if !writerRequired[field] {
+			problems = append(problems, fmt.Sprintf("%s: field %q is now required", path, field))
+		}
+	}
+
+	readerProperties, _ := r["properties"].(map[string]any)
+	writerProperties, _ := w["properties"].(map[string]any)
+	for name, writerProperty := range writerProperties {
+		propertyPath := path + "/properties/" + name
+		if readerProperty, ok := readerProperties[name]; ok {
+			problems = append(problems, jsonAccepts(readerProperty, writerProperty, propertyPath)...)
+			continue
+		}
+		if additional, ok := r["additionalProperties"]; ok {
+			problems = append(problems, jsonAccepts(additional, writerProperty, propertyPath)...)
+		}
+	}
+	if writerAdditional, ok := w["additionalProperties"]; !ok || writerAdditional != false {
+		if readerAdditional, ok := r["additionalProperties"]; ok && readerAdditional == false {
+			problems = append(problems, path+": additional properties are no longer allowed")
+		}
+	}
+
+	if readerItems, ok := r["items"]; ok {
+		writerItems, ok := w["items"]
+		if !ok {
+			writerItems = true
+		}
+		problems = append(problems, jsonAccepts(readerItems, writerItems, path+"/items")...)
+	}
+
+	return problems
+}
+
+func jsonTypes(schema map[string]any) map[string]bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return map[string]bool{t: true}
+	case []any:
+		return stringSet(t)
+	default:
+		return nil
+	}
+}
+
+func jsonEnum(schema map[string]any) (map[string]bool, bool) {
+	values, ok := schema["enum"].([]any)
+	if !ok {
+		if value, ok := schema["const"]; ok {
+			values = []any{value}
+		} else {
+			return nil, false
+		}
+	}
+
+	enum := map[string]bool{}
+	for _, value := range values {
+		encoded, _ := json.Marshal(value)
+		enum[string(encoded)] = true
+	}
+	return enum, true
+}
+
+func stringSet(value any) map[string]bool {
+	items, _ := value.([]any)
+	set := map[string]bool{}
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func keys(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for key := range set {
+		list = append(list, key)
+	}
+	return list
+}
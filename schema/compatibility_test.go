@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const (
+	jsonName = `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`
+	// jsonNameAge also requires age, which jsonName's messages lack
+	jsonNameAge = `{"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}, "required": ["name", "age"]}`
+	// jsonNameOptionalAge only adds an optional age
+	jsonNameOptionalAge = `{"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}, "required": ["name"]}`
+
+	avroName = `{"type": "record", "name": "User", "fields": [{"name": "name", "type": "string"}]}`
+	// avroNameAge adds age without a default, which avroName's messages lack
+	avroNameAge = `{"type": "record", "name": "User", "fields": [{"name": "name", "type": "string"}, {"name": "age", "type": "int"}]}`
+	// avroNameDefaultAge adds age with a default
+	avroNameDefaultAge = `{"type": "record", "name": "User", "fields": [{"name": "name", "type": "string"}, {"name": "age", "type": "int", "default": 0}]}`
+)
+
+func protobufDefinition(source string) string {
+	definition, _ := json.Marshal(ProtobufDefinition{Source: source, MessageType: "test.User"})
+	return string(definition)
+}
+
+var (
+	protobufName = protobufDefinition(`syntax = "proto2"; package test; message User { required string name = 1; }`)
+	// protobufNameAge also requires age, which protobufName's messages lack
+	protobufNameAge = protobufDefinition(`syntax = "proto2"; package test; message User { required string name = 1; required int32 age = 2; }`)
+	// protobufNameOptionalAge only adds an optional age
+	protobufNameOptionalAge = protobufDefinition(`syntax = "proto2"; package test; message User { required string name = 1; optional int32 age = 2; }`)
+	// protobufNameNumber changes the type of field 1
+	protobufNameNumber = protobufDefinition(`syntax = "proto2"; package test; message User { required int64 name = 1; }`)
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	type test struct {
+		name          string
+		compatibility string
+		format        string
+		existing      []string
+		definition    string
+		wantErr       bool
+	}
+
+	// Each format has a base schema, one adding a field its readers require
+	// and one adding a field they don't
+	formats := []struct {
+		format               string
+		base, added, relaxed string
+	}{
+		{FormatJSON, jsonName, jsonNameAge, jsonNameOptionalAge},
+		{FormatAvro, avroName, avroNameAge, avroNameDefaultAge},
+		{FormatProtobuf, protobufName, protobufNameAge, protobufNameOptionalAge},
+	}
+
+	var tests []test
+	for _, f := range formats {
+		tests = append(tests,
+			test{"no versions", CompatibilityFull, f.format, nil, f.added, false},
+			test{"none allows anything", CompatibilityNone, f.format, []string{f.base}, f.added, false},
+
+			test{"backward rejects a new required field", CompatibilityBackward, f.format, []string{f.base}, f.added, true},
+			test{"backward allows an optional field", CompatibilityBackward, f.format, []string{f.base}, f.relaxed, false},
+			test{"backward allows removing a required field", CompatibilityBackward, f.format, []string{f.added}, f.base, false},
+			test{"forward allows a new required field", CompatibilityForward, f.format, []string{f.base}, f.added, false},
+			test{"forward allows an optional field", CompatibilityForward, f.format, []string{f.base}, f.relaxed, false},
+			test{"full rejects a new required field", CompatibilityFull, f.format, []string{f.base}, f.added, true},
+			test{"full allows an optional field", CompatibilityFull, f.format, []string{f.base}, f.relaxed, false},
+
+			test{"backward checks the latest version only", CompatibilityBackward, f.format, []string{f.base, f.added}, f.added, false},
+			test{"backward transitive checks every version", CompatibilityBackwardTransitive, f.format, []string{f.base, f.added}, f.added, true},
+			test{"full checks the latest version only", CompatibilityFull, f.format, []string{f.base, f.added}, f.added, false},
+			test{"full transitive checks every version", CompatibilityFullTransitive, f.format, []string{f.base, f.added}, f.added, true},
+			test{"full transitive allows compatible versions", CompatibilityFullTransitive, f.format, []string{f.base, f.relaxed}, f.relaxed, false},
+		)
+	}
+
+	// Readers of the old JSON and Protobuf schemas require the removed field
+	tests = append(tests,
+		test{"forward rejects removing a required field", CompatibilityForward, FormatJSON, []string{jsonNameAge}, jsonName, true},
+		test{"forward transitive checks every version", CompatibilityForwardTransitive, FormatJSON, []string{jsonNameAge, jsonName}, jsonName, true},
+		test{"forward rejects removing a required field", CompatibilityForward, FormatProtobuf, []string{protobufNameAge}, protobufName, true},
+		test{"forward transitive checks every version", CompatibilityForwardTransitive, FormatProtobuf, []string{protobufNameAge, protobufName}, protobufName, true},
+		test{"forward checks the latest version only", CompatibilityForward, FormatProtobuf, []string{protobufNameAge, protobufName}, protobufName, false},
+		test{"backward rejects a changed field type", CompatibilityBackward, FormatProtobuf, []string{protobufName}, protobufNameNumber, true},
+		test{"backward rejects a changed field type", CompatibilityBackward, FormatJSON, []string{jsonName},
+			`{"type": "object", "properties": {"name": {"type": "integer"}}, "required": ["name"]}`, true},
+		// Avro readers fill a missing field from its default, so forward
+		// compatibility fails on removing a field without one
+		test{"forward rejects removing a field without a default", CompatibilityForward, FormatAvro, []string{avroNameAge}, avroName, true},
+		test{"forward allows removing a field with a default", CompatibilityForward, FormatAvro, []string{avroNameDefaultAge}, avroName, false},
+		test{"forward transitive checks every version", CompatibilityForwardTransitive, FormatAvro, []string{avroNameAge, avroNameDefaultAge}, avroName, true},
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.compatibility+"/"+tt.name, func(t *testing.T) {
+			existing := make([]Schema, len(tt.existing))
+			for i, definition := range tt.existing {
+				existing[i] = Schema{Version: i + 1, Format: tt.format, Definition: json.RawMessage(definition)}
+			}
+
+			err := checkCompatibility(tt.compatibility, tt.format, []byte(tt.definition), existing)
+			if tt.wantErr && !errors.Is(err, ErrIncompatible) {
+				t.Fatalf("checkCompatibility() = %v, want ErrIncompatible", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("checkCompatibility() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestCheckCompatibilityFormatChange(t *testing.T) {
+	existing := []Schema{{Version: 1, Format: FormatJSON, Definition: json.RawMessage(jsonName)}}
+	for _, compatibility := range []string{CompatibilityBackward, CompatibilityForward, CompatibilityFullTransitive} {
+		err := checkCompatibility(compatibility, FormatAvro, []byte(avroName), existing)
+		if !errors.Is(err, ErrIncompatible) {
+			t.Errorf("%s: checkCompatibility() = %v, want ErrIncompatible", compatibility, err)
+		}
+	}
+	if err := checkCompatibility(CompatibilityNone, FormatAvro, []byte(avroName), existing); err != nil {
+		t.Errorf("NONE: checkCompatibility() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+type jsonValidator struct {
+	schema *jsonschema.Schema
+}
+
+func compileJSON(definition []byte) (*jsonValidator, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(definition))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	return &jsonValidator{schema: compiled}, nil
+}
+
+func (v *jsonValidator) Validate(payload []byte) []string {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(payload))
+	if err != nil {
+		return []string{fmt.Sprintf("message is not valid JSON: %v", err)}
+	}
+
+	err = v.schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []string{err.Error()}
+	}
+
+	var errs []string
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", location, unit.Error))
+	}
+	if len(errs) == 0 {
+		errs = append(errs, validationErr.Error())
+	}
+	return errs
+}
+
+func (v *jsonValidator) ToJSON(payload []byte) ([]byte, error) {
+	return payload, nil
+}
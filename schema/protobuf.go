@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const FormatProtobuf = "PROTOBUF"
+
+// ProtobufDefinition is the definition of a Protobuf schema: the source of a
+// .proto file, which may import the well-known types, and the fully
+// qualified name of the message type carried by the topic.
+type ProtobufDefinition struct {
+	Source      string `json:"source"`
+	MessageType string `json:"messageType"`
+}
+
+type protobufCodec struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+func parseProtobuf(definition []byte) (protoreflect.MessageDescriptor, error) {
+	var def ProtobufDefinition
+	if err := json.Unmarshal(definition, &def); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if def.Source == "" || def.MessageType == "" {
+		return nil, fmt.Errorf("%w: source and messageType are required", ErrInvalidSchema)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+				"schema.proto": def.Source,
+			}),
+		}),
+	}
+
+	files, err := compiler.Compile(context.Background(), "schema.proto")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	descriptor, ok := files[0].FindDescriptorByName(protoreflect.FullName(def.MessageType)).(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: message type %s not found", ErrInvalidSchema, def.MessageType)
+	}
+	return descriptor, nil
+}
+
+func compileProtobuf(definition []byte) (*protobufCodec, error) {
+	descriptor, err := parseProtobuf(definition)
+	if err != nil {
+		return nil, err
+	}
+	return &protobufCodec{descriptor: descriptor}, nil
+}
+
+func (c *protobufCodec) unmarshal(payload []byte) (*dynamicpb.Message, error) {
+	message := dynamicpb.NewMessage(c.descriptor)
+	if err := proto.Unmarshal(payload, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (c *protobufCodec) Validate(payload []byte) []string {
+	message, err := c.unmarshal(payload)
+	if err != nil {
+		return []string{fmt.Sprintf("message is not a valid %s: %v", c.descriptor.FullName(), err)}
+	}
+	if err := proto.CheckInitialized(message); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+func (c *protobufCodec) ToJSON(payload []byte) ([]byte, error) {
+	message, err := c.unmarshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode Protobuf message: %v", err)
+	}
+	return protojson.Marshal(message)
+}
+
+// protobufCanRead checks that fields sharing a number keep their kind and
+// cardinality, and that the message type is unchanged. Added and removed
+// fields are wire compatible.
+func protobufCanRead(reader, writer []byte) ([]string, error) {
+	readerDescriptor, err := parseProtobuf(reader)
+	if err != nil {
+		return nil, err
+	}
+	writerDescriptor, err := parseProtobuf(writer)
+	if err != nil {
+		return nil, err
+	}
+
+	if readerDescriptor.FullName() != writerDescriptor.FullName() {
+		return []string{fmt.Sprintf("message type changed from %s to %s", writerDescriptor.FullName(), readerDescriptor.FullName())}, nil
+	}
+	return protobufFieldsCompatible(readerDescriptor, writerDescriptor, string(readerDescriptor.FullName()), map[protoreflect.FullName]bool{}), nil
+}
+
+func protobufFieldsCompatible(reader, writer protoreflect.MessageDescriptor, path string, seen map[protoreflect.FullName]bool) []string {
+	if seen[reader.FullName()] {
+		return nil
+	}
+	seen[reader.FullName()] = true
+
+	var problems []string
+	writerFields := writer.Fields()
+	readerFields := reader.Fields()
+	for i := 0; i < writerFields.Len(); i++ {
+		writerField := writerFields.Get(i)
+		readerField := readerFields.ByNumber(writerField.Number())
+		if readerField == nil {
+			continue
+		}
+
+		fieldPath := fmt.Sprintf("%s.%s", path, readerField.Name())
+		if readerField.Kind() != writerField.Kind() {
+			problems = append(problems, fmt.Sprintf("%s: field %d changed from %s to %s", fieldPath, writerField.Number(), writerField.Kind(), readerField.Kind()))
+			continue
+		}
+		if readerField.Cardinality() != writerField.Cardinality() || readerField.IsMap() != writerField.IsMap() {
+			problems = append(problems, fmt.Sprintf("%s: field %d changed cardinality", fieldPath, writerField.Number()))
+			continue
+		}
+		if readerField.Cardinality() == protoreflect.Required && writerField.Cardinality() != protoreflect.Required {
+			problems = append(problems, fmt.Sprintf("%s: field %d is now required", fieldPath, writerField.Number()))
+		}
+		if readerField.Message() != nil && writerField.Message() != nil {
+			problems = append(problems, protobufFieldsCompatible(readerField.Message(), writerField.Message(), fieldPath, seen)...)
+		}
+	}
+
+	for i := 0; i < readerFields.Len(); i++ {
+		readerField := readerFields.Get(i)
+		if readerField.Cardinality() == protoreflect.Required && writerFields.ByNumber(readerField.Number()) == nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: required field %d added", path, readerField.Name(), readerField.Number()))
+		}
+	}
+
+	return problems
+}
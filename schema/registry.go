@@ -0,0 +1,449 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"pub-sub-service/api"
+	"pub-sub-service/payload"
+	"pub-sub-service/store"
+)
+
+const FormatJSON = "JSON"
+
+// Attribute is stamped on messages validated against a schema, with the
+// schema's ID, version and format as "<id>:<version>:<format>". One attribute
+// carries all three, as SQS allows only ten.
+const Attribute = "Schema"
+
+// Message attributes stamped by earlier versions, whose ID StampedID still
+// reads and which callers still may not set. Since SQS keeps messages for at
+// most 14 days, the fallback is removed in the first release made 14 days or
+// more after the one that introduced Attribute.
+const (
+	IDAttribute      = "SchemaId"
+	VersionAttribute = "SchemaVersion"
+	FormatAttribute  = "SchemaFormat"
+)
+
+const (
+	CompatibilityNone               = "NONE"
+	CompatibilityBackward           = "BACKWARD"
+	CompatibilityBackwardTransitive = "BACKWARD_TRANSITIVE"
+	CompatibilityForward            = "FORWARD"
+	CompatibilityForwardTransitive  = "FORWARD_TRANSITIVE"
+	CompatibilityFull               = "FULL"
+	CompatibilityFullTransitive     = "FULL_TRANSITIVE"
+)
+
+var (
+	ErrNotFound      = errors.New("schema not found")
+	ErrInvalidSchema = errors.New("invalid schema")
+	ErrIncompatible  = errors.New("schema is incompatible")
+)
+
+// ValidationError reports why a message does not match a topic's schema.
+type ValidationError struct {
+	Version int
+	Errors  []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("message does not match schema version %d: %s", e.Version, strings.Join(e.Errors, "; "))
+}
+
+// Schema is a version of a topic's schema, shared with the client. Its ID is
+// derived from the format and definition, so identical schemas share an ID
+// across topics and versions.
+type Schema = api.Schema
+
+// StampedID returns the ID of the schema a message was stamped with, or ""
+// if it was not.
+func StampedID(attributes map[string]string) string {
+	if id, _, ok := strings.Cut(attributes[Attribute], ":"); ok {
+		return id
+	}
+	return attributes[IDAttribute]
+}
+
+// Config is the schema configuration of a topic, shared with the client.
+type Config = api.SchemaConfig
+
+// activeCacheTTL bounds how long the active schema of a topic is reused
+// before it is read again, and so how long other instances take to see a
+// schema registered or a version pinned through this one
+const activeCacheTTL = 10 * time.Second
+
+// Registry stores versioned schemas per topic and validates messages
+// published to a topic against its active schema.
+type Registry struct {
+	store store.Store
+
+	mu       sync.Mutex
+	compiled map[string]codec
+	active   map[string]activeEntry
+	// changes counts schema changes, so that a load that raced one is not
+	// cached
+	changes uint64
+}
+
+// activeEntry is a cached active schema, nil for topics without one
+type activeEntry struct {
+	schema *Schema
+	loaded time.Time
+}
+
+// codec validates payloads against a compiled schema and decodes them.
+type codec interface {
+	Validate(payload []byte) []string
+	ToJSON(payload []byte) ([]byte, error)
+}
+
+func NewRegistry(s store.Store) *Registry {
+	return &Registry{
+		store:    s,
+		compiled: map[string]codec{},
+		active:   map[string]activeEntry{},
+	}
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultRegistry *Registry
+)
+
+// Default returns the registry backed by the default store.
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewRegistry(store.Default())
+	})
+	return defaultRegistry
+}
+
+func configKey(topic string) string {
+	return "schemas/" + topic + "/config"
+}
+
+func versionPrefix(topic string) string {
+	return "schemas/" + topic + "/versions/"
+}
+
+func versionKey(topic string, version int) string {
+	return fmt.Sprintf("%s%08d", versionPrefix(topic), version)
+}
+
+func idKey(id string) string {
+	return "schemas/ids/" + id
+}
+
+// schemaID fingerprints a definition, ignoring insignificant whitespace.
+func schemaID(format string, definition []byte) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, definition); err == nil {
+		definition = compacted.Bytes()
+	}
+
+	sum := sha256.Sum256(append([]byte(format+"\x00"), definition...))
+	return hex.EncodeToString(sum[:8])
+}
+
+func decodeSchema(value []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(value, &schema); err != nil {
+		return nil, err
+	}
+	if schema.ID == "" {
+		schema.ID = schemaID(schema.Format, schema.Definition)
+	}
+	return &schema, nil
+}
+
+// ContentType returns the content type of payloads in a schema format.
+func ContentType(format string) string {
+	switch format {
+	case FormatAvro:
+		return payload.ContentTypeAvro
+	case FormatProtobuf:
+		return payload.ContentTypeProtobuf
+	default:
+		return payload.ContentTypeJSON
+	}
+}
+
+// Register adds a new version of a topic's schema after checking it against
+// the topic's compatibility rule, and returns it.
+func (r *Registry) Register(ctx context.Context, topic, format string, definition []byte) (*Schema, error) {
+	if topic == "" {
+		return nil, errors.New("must supply a topic")
+	}
+	if format == "" {
+		format = FormatJSON
+	}
+
+	if _, err := compile(format, definition); err != nil {
+		return nil, err
+	}
+
+	config, err := r.Config(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := r.List(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCompatibility(config.Compatibility, format, definition, existing); err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		ID:         schemaID(format, definition),
+		Topic:      topic,
+		Version:    1,
+		Format:     format,
+		Definition: json.RawMessage(definition),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if len(existing) > 0 {
+		schema.Version = existing[len(existing)-1].Version + 1
+	}
+
+	value, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := r.store.PutIfAbsent(ctx, versionKey(topic, schema.Version), value, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, fmt.Errorf("schema version %d of topic %s was registered concurrently", schema.Version, topic)
+	}
+
+	if _, err := r.store.PutIfAbsent(ctx, idKey(schema.ID), value, 0); err != nil {
+		return nil, err
+	}
+
+	r.forgetActive(topic)
+	return schema, nil
+}
+
+// GetByID returns the schema with the given ID, as stamped on messages.
+func (r *Registry) GetByID(ctx context.Context, id string) (*Schema, error) {
+	value, err := r.store.Get(ctx, idKey(id))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSchema(value)
+}
+
+// Decode converts a payload written with the schema with the given ID to
+// JSON, so consumers without Avro or Protobuf support can read it.
+func (r *Registry) Decode(ctx context.Context, id string, payload []byte) (json.RawMessage, error) {
+	schema, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := r.codec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ToJSON(payload)
+}
+
+// Get returns a version of a topic's schema.
+func (r *Registry) Get(ctx context.Context, topic string, version int) (*Schema, error) {
+	value, err := r.store.Get(ctx, versionKey(topic, version))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSchema(value)
+}
+
+// List returns every version of a topic's schema, oldest first.
+func (r *Registry) List(ctx context.Context, topic string) ([]Schema, error) {
+	items, err := r.store.List(ctx, versionPrefix(topic))
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make([]Schema, 0, len(items))
+	for _, item := range items {
+		schema, err := decodeSchema(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, *schema)
+	}
+	return schemas, nil
+}
+
+// Config returns the schema configuration of a topic, defaulting to
+// BACKWARD compatibility.
+func (r *Registry) Config(ctx context.Context, topic string) (Config, error) {
+	config := Config{Compatibility: CompatibilityBackward}
+
+	value, err := r.store.Get(ctx, configKey(topic))
+	if errors.Is(err, store.ErrNotFound) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	err = json.Unmarshal(value, &config)
+	return config, err
+}
+
+// SetConfig changes the compatibility rule of a topic or pins its active
+// schema version, e.g. to roll back to an earlier version.
+func (r *Registry) SetConfig(ctx context.Context, topic string, config Config) error {
+	switch config.Compatibility {
+	case CompatibilityNone, CompatibilityBackward, CompatibilityBackwardTransitive,
+		CompatibilityForward, CompatibilityForwardTransitive,
+		CompatibilityFull, CompatibilityFullTransitive:
+	default:
+		return fmt.Errorf("unknown compatibility %q", config.Compatibility)
+	}
+
+	if config.ActiveVersion < 0 {
+		return fmt.Errorf("invalid active version %d", config.ActiveVersion)
+	}
+	if config.ActiveVersion > 0 {
+		if _, err := r.Get(ctx, topic, config.ActiveVersion); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := r.store.Put(ctx, configKey(topic), value, 0); err != nil {
+		return err
+	}
+
+	r.forgetActive(topic)
+	return nil
+}
+
+// Active returns the schema messages published to a topic must match, or
+// nil if the topic has no schema. It is read once per activeCacheTTL, as
+// every publish asks for it.
+func (r *Registry) Active(ctx context.Context, topic string) (*Schema, error) {
+	r.mu.Lock()
+	entry, ok := r.active[topic]
+	changes := r.changes
+	r.mu.Unlock()
+	if ok && time.Since(entry.loaded) < activeCacheTTL {
+		return entry.schema, nil
+	}
+
+	loaded := time.Now()
+	schema, err := r.loadActive(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changes == changes {
+		r.active[topic] = activeEntry{schema: schema, loaded: loaded}
+	}
+	return schema, nil
+}
+
+// forgetActive drops the cached active schema of a topic after a change
+func (r *Registry) forgetActive(topic string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.active, topic)
+	r.changes++
+}
+
+func (r *Registry) loadActive(ctx context.Context, topic string) (*Schema, error) {
+	config, err := r.Config(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ActiveVersion > 0 {
+		return r.Get(ctx, topic, config.ActiveVersion)
+	}
+
+	schemas, err := r.List(ctx, topic)
+	if err != nil || len(schemas) == 0 {
+		return nil, err
+	}
+	return &schemas[len(schemas)-1], nil
+}
+
+// Validate checks payload against the active schema of a topic and returns
+// that schema, or nil if the topic has none. A mismatch is reported as a
+// *ValidationError.
+func (r *Registry) Validate(ctx context.Context, topic string, payload []byte) (*Schema, error) {
+	schema, err := r.Active(ctx, topic)
+	if err != nil || schema == nil {
+		return nil, err
+	}
+
+	c, err := r.codec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := c.Validate(payload); len(errs) > 0 {
+		return schema, &ValidationError{Version: schema.Version, Errors: errs}
+	}
+	return schema, nil
+}
+
+func (r *Registry) codec(schema *Schema) (codec, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.compiled[schema.ID]; ok {
+		return c, nil
+	}
+
+	c, err := compile(schema.Format, schema.Definition)
+	if err != nil {
+		return nil, err
+	}
+	r.compiled[schema.ID] = c
+	return c, nil
+}
+
+func compile(format string, definition []byte) (codec, error) {
+	switch format {
+	case FormatJSON:
+		return compileJSON(definition)
+	case FormatAvro:
+		return compileAvro(definition)
+	case FormatProtobuf:
+		return compileProtobuf(definition)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidSchema, format)
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	MaxBodyBytes      int64
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	// GRPCAddr is where the gRPC API listens; empty disables it
+	GRPCAddr string
+}
+
+// ConfigFromEnv reads the server configuration from the environment, falling
+// back to defaults for anything unset.
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Addr:              os.Getenv("PORT"),
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
+		MaxBodyBytes:      1 << 20,
+		TLSCertFile:       os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:        os.Getenv("TLS_KEY_FILE"),
+		TLSClientCAFile:   os.Getenv("TLS_CLIENT_CA_FILE"),
+		GRPCAddr:          os.Getenv("GRPC_PORT"),
+	}
+	if config.Addr == "" {
+		config.Addr = ":8080"
+	} else if !strings.Contains(config.Addr, ":") {
+		config.Addr = ":" + config.Addr
+	}
+	if config.GRPCAddr != "" && !strings.Contains(config.GRPCAddr, ":") {
+		config.GRPCAddr = ":" + config.GRPCAddr
+	}
+
+	durations := map[string]*time.Duration{
+		"HTTP_READ_TIMEOUT":        &config.ReadTimeout,
+		"HTTP_READ_HEADER_TIMEOUT": &config.ReadHeaderTimeout,
+		"HTTP_WRITE_TIMEOUT":       &config.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        &config.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":         &config.ShutdownTimeout,
+	}
+	for name, field := range durations {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s %q: %v", name, value, err)
+		}
+		*field = duration
+	}
+
+	if value := os.Getenv("HTTP_MAX_BODY_BYTES"); value != "" {
+		maxBodyBytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid HTTP_MAX_BODY_BYTES %q: %v", value, err)
+		}
+		config.MaxBodyBytes = maxBodyBytes
+	}
+
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return Config{}, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if config.TLSClientCAFile != "" && config.TLSCertFile == "" {
+		return Config{}, fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	return config, nil
+}
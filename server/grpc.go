@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// GRPCCredentials returns the server options for serving gRPC with the same
+// TLS and mTLS settings as HTTP, or none when TLS is not configured.
+func GRPCCredentials(config Config) ([]grpc.ServerOption, error) {
+	if config.TLSCertFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig, err := newTLSConfig(config.TLSClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS certificate: %v", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{certificate}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// RunGRPC serves grpcServer on GRPCAddr until ctx is cancelled, then stops
+// accepting calls and waits up to ShutdownTimeout for in-flight calls,
+// cancelling any left, such as open streams, after that.
+func RunGRPC(ctx context.Context, config Config, grpcServer *grpc.Server) error {
+	listener, err := net.Listen("tcp", config.GRPCAddr)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("starting gRPC server", slog.String("addr", config.GRPCAddr), slog.Bool("tls", config.TLSCertFile != ""))
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down gRPC server", slog.Duration("timeout", config.ShutdownTimeout))
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(config.ShutdownTimeout):
+		grpcServer.Stop()
+		<-stopped
+	}
+
+	return <-serveErr
+}
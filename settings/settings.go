@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"pub-sub-service/api"
+	"pub-sub-service/store"
+)
+
+// Topic and Queue hold the service-side settings of a topic and a queue,
+// shared with the client.
+type (
+	Topic = api.TopicSettings
+	Queue = api.QueueSettings
+)
+
+func topicKey(topicARN string) string {
+	return "settings/topics/" + topicARN
+}
+
+func queueKey(queueName string) string {
+	return "settings/queues/" + queueName
+}
+
+// GetTopic returns the settings of a topic, or the zero settings if none have
+// been stored.
+func GetTopic(ctx context.Context, topicARN string) (Topic, error) {
+	var topic Topic
+
+	value, err := store.Default().Get(ctx, topicKey(topicARN))
+	if errors.Is(err, store.ErrNotFound) {
+		return topic, nil
+	}
+	if err != nil {
+		return topic, err
+	}
+
+	err = json.Unmarshal(value, &topic)
+	return topic, err
+}
+
+func SetTopic(ctx context.Context, topicARN string, topic Topic) error {
+	value, err := json.Marshal(topic)
+	if err != nil {
+		return err
+	}
+
+	return store.Default().Put(ctx, topicKey(topicARN), value, 0)
+}
+
+// GetQueue returns the settings of a queue, or the zero settings if none have
+// been stored.
+func GetQueue(ctx context.Context, queueName string) (Queue, error) {
+	var queue Queue
+
+	value, err := store.Default().Get(ctx, queueKey(queueName))
+	if errors.Is(err, store.ErrNotFound) {
+		return queue, nil
+	}
+	if err != nil {
+		return queue, err
+	}
+
+	err = json.Unmarshal(value, &queue)
+	return queue, err
+}
+
+func SetQueue(ctx context.Context, queueName string, queue Queue) error {
+	value, err := json.Marshal(queue)
+	if err != nil {
+		return err
+	}
+
+	return store.Default().Put(ctx, queueKey(queueName), value, 0)
+}
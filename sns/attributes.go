@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/awssession"
+	"pub-sub-service/logging"
+	"pub-sub-service/tracing"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+var ErrInvalidAttributes = errors.New("invalid topic attributes")
+
+// SNS topic attribute names
+const (
+	attributeTopicArn                  = "TopicArn"
+	attributeOwner                     = "Owner"
+	attributePolicy                    = "Policy"
+	attributeSubscriptionsConfirmed    = "SubscriptionsConfirmed"
+	attributeSubscriptionsPending      = "SubscriptionsPending"
+	attributeSubscriptionsDeleted      = "SubscriptionsDeleted"
+	attributeDisplayName               = "DisplayName"
+	attributeDeliveryPolicy            = "DeliveryPolicy"
+	attributeKmsMasterKeyId            = "KmsMasterKeyId"
+	attributeFifoTopic                 = "FifoTopic"
+	attributeContentBasedDeduplication = "ContentBasedDeduplication"
+)
+
+// Topic attribute types, shared with the client
+type (
+	TopicEncryption  = api.TopicEncryption
+	TopicAttributes  = api.TopicAttributes
+	TopicDescription = api.TopicDescription
+)
+
+// validateAttributes checks the values of the attributes that are set.
+func validateAttributes(a TopicAttributes) error {
+	if a.DisplayName != nil && len(*a.DisplayName) > 100 {
+		return fmt.Errorf("%w: %s must be at most 100 characters", ErrInvalidAttributes, attributeDisplayName)
+	}
+	if a.DeliveryPolicy != nil && !json.Valid(a.DeliveryPolicy) {
+		return fmt.Errorf("%w: %s must be a JSON document", ErrInvalidAttributes, attributeDeliveryPolicy)
+	}
+	if a.Policy != nil && !json.Valid(a.Policy) {
+		return fmt.Errorf("%w: %s must be a JSON document", ErrInvalidAttributes, attributePolicy)
+	}
+	return nil
+}
+
+// validateCreate checks the attributes for a new topic with the given name.
+func validateCreate(a TopicAttributes, topicName string) error {
+	if err := validateAttributes(a); err != nil {
+		return err
+	}
+
+	fifo := a.FifoTopic != nil && *a.FifoTopic
+	if fifo != strings.HasSuffix(topicName, ".fifo") {
+		return fmt.Errorf("%w: FIFO topic names, and only those, must end in .fifo", ErrInvalidAttributes)
+	}
+	if !fifo && a.ContentBasedDeduplication != nil && *a.ContentBasedDeduplication {
+		return fmt.Errorf("%w: %s requires a FIFO topic", ErrInvalidAttributes, attributeContentBasedDeduplication)
+	}
+	return nil
+}
+
+// attributesToMap returns the attributes that are set, as SNS expects them.
+func attributesToMap(a TopicAttributes) map[string]*string {
+	attributes := map[string]*string{}
+	if a.KMSMasterKeyID != nil {
+		attributes[attributeKmsMasterKeyId] = a.KMSMasterKeyID
+	}
+	if a.DisplayName != nil {
+		attributes[attributeDisplayName] = a.DisplayName
+	}
+	if a.DeliveryPolicy != nil {
+		attributes[attributeDeliveryPolicy] = aws.String(string(a.DeliveryPolicy))
+	}
+	if a.Policy != nil {
+		attributes[attributePolicy] = aws.String(string(a.Policy))
+	}
+	if a.FifoTopic != nil {
+		attributes[attributeFifoTopic] = aws.String(strconv.FormatBool(*a.FifoTopic))
+	}
+	if a.ContentBasedDeduplication != nil {
+		attributes[attributeContentBasedDeduplication] = aws.String(strconv.FormatBool(*a.ContentBasedDeduplication))
+	}
+	return attributes
+}
+
+func topicDescriptionFromMap(attributes map[string]*string) *TopicDescription {
+	value := func(name string) *string {
+		if v, ok := attributes[name]; ok {
+			return aws.String(aws.StringValue(v))
+		}
+		return nil
+	}
+	document := func(name string) json.RawMessage {
+		if v := aws.StringValue(attributes[name]); v != "" && json.Valid([]byte(v)) {
+			return json.RawMessage(v)
+		}
+		return nil
+	}
+	count := func(name string) int64 {
+		n, _ := strconv.ParseInt(aws.StringValue(attributes[name]), 10, 64)
+		return n
+	}
+	flag := func(name string) *bool {
+		if b, err := strconv.ParseBool(aws.StringValue(attributes[name])); err == nil {
+			return aws.Bool(b)
+		}
+		return nil
+	}
+
+	return &TopicDescription{
+		TopicAttributes: TopicAttributes{
+			TopicEncryption:           TopicEncryption{KMSMasterKeyID: value(attributeKmsMasterKeyId)},
+			DisplayName:               value(attributeDisplayName),
+			DeliveryPolicy:            document(attributeDeliveryPolicy),
+			Policy:                    document(attributePolicy),
+			FifoTopic:                 flag(attributeFifoTopic),
+			ContentBasedDeduplication: flag(attributeContentBasedDeduplication),
+		},
+		TopicARN:               aws.StringValue(attributes[attributeTopicArn]),
+		Owner:                  aws.StringValue(attributes[attributeOwner]),
+		SubscriptionsConfirmed: count(attributeSubscriptionsConfirmed),
+		SubscriptionsPending:   count(attributeSubscriptionsPending),
+		SubscriptionsDeleted:   count(attributeSubscriptionsDeleted),
+	}
+}
+
+// GetTopicAttributes describes a topic.
+func GetTopicAttributes(ctx context.Context, topicARN string) (*TopicDescription, error) {
+	ctx, span := tracing.StartTopic(ctx, "notification.GetTopicAttributes", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	result, err := svc.GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{
+		TopicArn: aws.String(topicARN),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to get topic attributes", slog.String("topic", topicARN), slog.Any("error", err))
+		return nil, err
+	}
+
+	return topicDescriptionFromMap(result.Attributes), nil
+}
+
+// SetTopicAttributes updates the attributes that are set. SNS sets one
+// attribute per call, so a failure can leave earlier ones updated.
+func SetTopicAttributes(ctx context.Context, topicARN string, attributes TopicAttributes) (bool, error) {
+	if attributes.FifoTopic != nil {
+		return false, fmt.Errorf("%w: %s can only be set at creation", ErrInvalidAttributes, attributeFifoTopic)
+	}
+	if err := validateAttributes(attributes); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartTopic(ctx, "notification.SetTopicAttributes", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	for name, value := range attributesToMap(attributes) {
+		_, err := svc.SetTopicAttributesWithContext(ctx, &sns.SetTopicAttributesInput{
+			TopicArn: aws.String(topicARN),
+			AttributeName: aws.String(name),
+			AttributeValue: value,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("unable to set topic attribute", slog.String("topic", topicARN), slog.String("attribute", name), slog.Any("error", err))
+			return false, err
+		}
+	}
+
+	logging.FromContext(ctx).Info("updated topic attributes", slog.String("topic", topicARN))
+	return true, nil
+}
@@ -1,96 +1,193 @@
 package notification
 
 import (
+	"context"
 	"errors"
-	"log"
 	"fmt"
+	"log/slog"
+	"pub-sub-service/awssession"
+	"pub-sub-service/blob"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+	"pub-sub-service/tracing"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func ListTopics() ([]*sns.Topic, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+// MaxMessageAttributes is the most attributes a published message may carry,
+// counting those the service sets. It is the SQS limit, as queues subscribed
+// with raw message delivery drop messages with more.
+const MaxMessageAttributes = 10
+
+func ListTopics(ctx context.Context) ([]*sns.Topic, error) {
+	ctx, span := tracing.Start(ctx, "notification.ListTopics")
+	defer span.End()
+
+	sess := awssession.New()
 
 	svc := sns.New(sess)
 
-	result, err := svc.ListTopics(nil)
+	var topics []*sns.Topic
+	err := svc.ListTopicsPagesWithContext(ctx, &sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+		topics = append(topics, page.Topics...)
+		return true
+	})
 	if err != nil {
-		fmt.Println(err.Error())
+		logging.FromContext(ctx).Error("unable to list topics", slog.Any("error", err))
 		return nil, err
 	}
 
-	var topics []*sns.Topic
-	topics = append(topics, result.Topics...)
-
 	return topics, nil
 }
 
-func CreateTopic(topicName string) (*sns.CreateTopicOutput, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+// CreateTopic creates a topic with the given attributes.
+func CreateTopic(ctx context.Context, topicName string, attributes TopicAttributes) (*sns.CreateTopicOutput, error) {
+	if err := validateCreate(attributes, topicName); err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.StartTopic(ctx, "notification.CreateTopic", topicName)
+	defer span.End()
+
+	sess := awssession.New()
 
 	svc := sns.New(sess)
 
-	result, err := svc.CreateTopic(&sns.CreateTopicInput{
+	result, err := svc.CreateTopicWithContext(ctx, &sns.CreateTopicInput{
 		Name: aws.String(topicName),
+		Attributes: attributesToMap(attributes),
 	})
 
 	if err != nil {
-		fmt.Println(err.Error())
+		logging.FromContext(ctx).Error("unable to create topic", slog.String("topic", topicName), slog.Any("error", err))
 		return nil, err
 	}
 
+	cacheTopicARN(aws.StringValue(result.TopicArn))
 	return result, nil
 }
 
-func ListSubscriptions(topicPtr *string) ([]*sns.Subscription, error) {
+func DeleteTopic(ctx context.Context, topicARN string) (bool, error) {
+	ctx, span := tracing.StartTopic(ctx, "notification.DeleteTopic", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	_, err := svc.DeleteTopicWithContext(ctx, &sns.DeleteTopicInput{
+		TopicArn: aws.String(topicARN),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to delete topic", slog.String("topic", topicARN), slog.Any("error", err))
+		return false, err
+	}
+
+	forgetTopicARN(topicARN)
+	logging.FromContext(ctx).Info("deleted topic", slog.String("topic", topicARN))
+	return true, nil
+}
+
+// GetTopicEncryption returns the server-side encryption settings of a topic.
+func GetTopicEncryption(ctx context.Context, topicARN string) (*TopicEncryption, error) {
+	ctx, span := tracing.StartTopic(ctx, "notification.GetTopicEncryption", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	result, err := svc.GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{
+		TopicArn: aws.String(topicARN),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to get topic attributes", slog.String("topic", topicARN), slog.Any("error", err))
+		return nil, err
+	}
+
+	encryption := &TopicEncryption{KMSMasterKeyID: aws.String("")}
+	if keyID, ok := result.Attributes[attributeKmsMasterKeyId]; ok {
+		encryption.KMSMasterKeyID = aws.String(aws.StringValue(keyID))
+	}
+	return encryption, nil
+}
+
+// SetTopicEncryption updates the server-side encryption settings of a topic.
+func SetTopicEncryption(ctx context.Context, topicARN string, encryption TopicEncryption) (bool, error) {
+	if encryption.KMSMasterKeyID == nil {
+		return true, nil
+	}
+
+	ctx, span := tracing.StartTopic(ctx, "notification.SetTopicEncryption", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	_, err := svc.SetTopicAttributesWithContext(ctx, &sns.SetTopicAttributesInput{
+		TopicArn: aws.String(topicARN),
+		AttributeName: aws.String(attributeKmsMasterKeyId),
+		AttributeValue: encryption.KMSMasterKeyID,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to set topic attributes", slog.String("topic", topicARN), slog.Any("error", err))
+		return false, err
+	}
+
+	logging.FromContext(ctx).Info("updated topic encryption", slog.String("topic", topicARN))
+	return true, nil
+}
+
+func ListSubscriptions(ctx context.Context, topicPtr *string) ([]*sns.Subscription, error) {
 	if *topicPtr == "" {
-		fmt.Println("You must supply a topic ARN")
+		logging.FromContext(ctx).Warn("topic ARN is required to list subscriptions")
 		return nil, errors.New("must supply email and topic")
 	}
 
-	// Initialize a session that the SDK will use to load
-	// credentials from the shared credentials file. (~/.aws/credentials).
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	ctx, span := tracing.StartTopic(ctx, "notification.ListSubscriptions", *topicPtr)
+	defer span.End()
+
+	// Use the shared session, which loads credentials
+	// from the shared credentials file. (~/.aws/credentials).
+	sess := awssession.New()
 
 	svc := sns.New(sess)
-	var previousToken *string
 
-	result, err := svc.ListSubscriptionsByTopic(&sns.ListSubscriptionsByTopicInput{
-		NextToken: previousToken,
+	var subscriptions []*sns.Subscription
+	err := svc.ListSubscriptionsByTopicPagesWithContext(ctx, &sns.ListSubscriptionsByTopicInput{
 		TopicArn: topicPtr,
+	}, func(page *sns.ListSubscriptionsByTopicOutput, lastPage bool) bool {
+		subscriptions = append(subscriptions, page.Subscriptions...)
+		return true
 	})
 	if err != nil {
-		fmt.Println(err.Error())
+		logging.FromContext(ctx).Error("unable to list subscriptions", slog.String("topic", *topicPtr), slog.Any("error", err))
 		return nil, err
 	}
 
-	return result.Subscriptions, nil
+	return subscriptions, nil
 }
 
-func SubscribeEmailToTopic(emailPtr *string, topicPtr *string) (*sns.SubscribeOutput, error) {
+func SubscribeEmailToTopic(ctx context.Context, emailPtr *string, topicPtr *string) (*sns.SubscribeOutput, error) {
 	if *emailPtr == "" || *topicPtr == "" {
-		fmt.Println("You must supply an email address and topic ARN")
+		logging.FromContext(ctx).Warn("email address and topic ARN are required to subscribe")
 		return nil, errors.New("must supply email and topic")
 	}
 
-	// Initialize a session that the SDK will use to load
-	// credentials from the shared credentials file. (~/.aws/credentials).
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	ctx, span := tracing.StartTopic(ctx, "notification.SubscribeEmailToTopic", *topicPtr)
+	defer span.End()
+
+	// Use the shared session, which loads credentials
+	// from the shared credentials file. (~/.aws/credentials).
+	sess := awssession.New()
 
 	svc := sns.New(sess)
 
-	result, err := svc.Subscribe(&sns.SubscribeInput{
+	result, err := svc.SubscribeWithContext(ctx, &sns.SubscribeInput{
 		Endpoint:              emailPtr,
 		Protocol:              aws.String("email"),
 		ReturnSubscriptionArn: aws.Bool(true), // Return the ARN, even if user has yet to confirm
@@ -98,105 +195,116 @@ func SubscribeEmailToTopic(emailPtr *string, topicPtr *string) (*sns.SubscribeOu
 	})
 
 	if err != nil {
-		fmt.Println(err.Error())
+		logging.FromContext(ctx).Error("unable to subscribe email to topic",
+			slog.Any("email", logging.Email(*emailPtr)), slog.String("topic", *topicPtr), slog.Any("error", err))
 		return nil, err
 	}
 
+	logging.FromContext(ctx).Info("subscribed email to topic",
+		slog.Any("email", logging.Email(*emailPtr)), slog.String("topic", *topicPtr))
 	return result, nil
 }
 
-func SubscribeQueueToTopic(queueName string, topicPtr *string) (bool, error) {
+func SubscribeQueueToTopic(ctx context.Context, queueName string, topicPtr *string) (bool, error) {
   if queueName == "" || topicPtr == nil || *topicPtr == "" {
     return false, errors.New("must supply both queue name and topic ARN")
   }
 
-  // Initialize a session to load AWS credentials and configuration from the shared config.
-  sess := session.Must(session.NewSessionWithOptions(session.Options{
-    SharedConfigState: session.SharedConfigEnable,
-  }))
+  _, err := SubscribeQueueToTopicWithAttributes(ctx, queueName, *topicPtr, SubscriptionAttributes{})
+  if err != nil {
+    return false, err
+  }
+
+  return true, nil
+}
+
+// SubscribeQueueToTopicWithAttributes subscribes a queue to a topic with the
+// given subscription attributes, allows the topic to send to the queue, and
+// returns the subscription ARN.
+func SubscribeQueueToTopicWithAttributes(ctx context.Context, queueName, topicArn string, attributes SubscriptionAttributes) (string, error) {
+  if err := validateSubscriptionAttributes(attributes); err != nil {
+    return "", err
+  }
+
+  ctx, span := tracing.StartTopic(ctx, "notification.SubscribeQueueToTopic", topicArn)
+  defer span.End()
+
+  // Use the shared session, which loads AWS credentials and configuration from the shared config.
+  sess := awssession.New()
 
   // Create SNS and SQS clients
   snsSvc := sns.New(sess)
   sqsSvc := sqs.New(sess)
 
-  // Use the provided topic ARN
-  topicArn := *topicPtr
-
   // Get the SQS queue URL and ARN
-  queueUrlOutput, err := sqsSvc.GetQueueUrl(&sqs.GetQueueUrlInput{
+  queueUrlOutput, err := sqsSvc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
     QueueName: aws.String(queueName),
   })
   if err != nil {
-    return false, fmt.Errorf("unable to get SQS queue URL: %v", err)
+    return "", fmt.Errorf("unable to get SQS queue URL: %v", err)
   }
 
-  queueAttrsOutput, err := sqsSvc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+  queueAttrsOutput, err := sqsSvc.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
     QueueUrl:       queueUrlOutput.QueueUrl,
-    AttributeNames: []*string{aws.String("QueueArn")},
+    AttributeNames: []*string{aws.String("QueueArn"), aws.String("Policy")},
   })
   if err != nil {
-    return false, fmt.Errorf("unable to get SQS queue attributes: %v", err)
+    return "", fmt.Errorf("unable to get SQS queue attributes: %v", err)
   }
 
   queueArn := queueAttrsOutput.Attributes["QueueArn"]
 
   // Subscribe the SQS queue to the SNS topic
-  _, err = snsSvc.Subscribe(&sns.SubscribeInput{
-    Protocol: aws.String("sqs"),
-    TopicArn: aws.String(topicArn),
-    Endpoint: aws.String(*queueArn),
+  result, err := snsSvc.SubscribeWithContext(ctx, &sns.SubscribeInput{
+    Protocol:              aws.String("sqs"),
+    TopicArn:              aws.String(topicArn),
+    Endpoint:              aws.String(*queueArn),
+    Attributes:            subscriptionAttributesToMap(attributes),
+    ReturnSubscriptionArn: aws.Bool(true),
   })
   if err != nil {
-    return false, fmt.Errorf("unable to subscribe SQS queue to SNS topic: %v", err)
-  }
-
-  // Set policy to allow SNS to send messages to the SQS queue
-  policy := fmt.Sprintf(`{
-    "Version": "2012-10-17",
-    "Statement": [
-      {
-        "Effect": "Allow",
-        "Principal": "*",
-        "Action": "SQS:SendMessage",
-        "Resource": "%s",
-        "Condition": {
-          "ArnEquals": {
-            "aws:SourceArn": "%s"
-          }
-        }
-      }
-    ]
-  }`, *queueArn, topicArn)
-
-  _, err = sqsSvc.SetQueueAttributes(&sqs.SetQueueAttributesInput{
-    QueueUrl: queueUrlOutput.QueueUrl,
-    Attributes: map[string]*string{
-      "Policy": aws.String(policy),
-    },
-  })
+    return "", fmt.Errorf("unable to subscribe SQS queue to SNS topic: %v", err)
+  }
+
+  // Allow SNS to send messages to the SQS queue, keeping the permissions of
+  // other topics subscribed to it
+  policy, changed, err := allowTopicPolicy(aws.StringValue(queueAttrsOutput.Attributes["Policy"]), *queueArn, topicArn)
   if err != nil {
-    return false, fmt.Errorf("unable to set SQS queue policy: %v", err)
+    return "", fmt.Errorf("unable to update SQS queue policy: %v", err)
   }
 
-  log.Printf("Successfully subscribed SQS queue %s to SNS topic %s", queueName, *topicPtr)
-  return true, nil
+  if changed {
+    _, err = sqsSvc.SetQueueAttributesWithContext(ctx, &sqs.SetQueueAttributesInput{
+      QueueUrl: queueUrlOutput.QueueUrl,
+      Attributes: map[string]*string{
+        "Policy": aws.String(policy),
+      },
+    })
+    if err != nil {
+      return "", fmt.Errorf("unable to set SQS queue policy: %v", err)
+    }
+  }
+
+  logging.FromContext(ctx).Info("subscribed queue to topic", slog.String("queue", queueName), slog.String("topic", topicArn))
+  return aws.StringValue(result.SubscriptionArn), nil
 }
 
-func UnsubscribeFromTopic(subscriptionID, topicPtr *string) (bool, error) {
+func UnsubscribeFromTopic(ctx context.Context, subscriptionID, topicPtr *string) (bool, error) {
   if subscriptionID == nil || topicPtr == nil || *subscriptionID == "" || *topicPtr == "" {
     return false, errors.New("must supply both a subscription ID and topic ARN")
   }
 
-  // Initialize a session to load AWS credentials and configuration from the shared config.
-  sess := session.Must(session.NewSessionWithOptions(session.Options{
-    SharedConfigState: session.SharedConfigEnable,
-  }))
+  ctx, span := tracing.StartTopic(ctx, "notification.UnsubscribeFromTopic", *topicPtr)
+  defer span.End()
+
+  // Use the shared session, which loads AWS credentials and configuration from the shared config.
+  sess := awssession.New()
 
   // Create SNS client
   svc := sns.New(sess)
 
   // Unsubscribe the given subscription ID
-  _, err := svc.Unsubscribe(&sns.UnsubscribeInput{
+  _, err := svc.UnsubscribeWithContext(ctx, &sns.UnsubscribeInput{
     SubscriptionArn: subscriptionID,
   })
 
@@ -204,33 +312,71 @@ func UnsubscribeFromTopic(subscriptionID, topicPtr *string) (bool, error) {
     return false, fmt.Errorf("failed to unsubscribe from topic %s with subscription ID %s: %v", *topicPtr, *subscriptionID, err)
   }
 
-  log.Printf("Successfully unsubscribed from topic %s with subscription ID %s", *topicPtr, *subscriptionID)
+  logging.FromContext(ctx).Info("unsubscribed from topic", slog.String("topic", *topicPtr), slog.String("subscription", *subscriptionID))
   return true, nil
 }
 
-func PublishMessageToAllTopicSubscribers(messagePtr *string, topicPtr *string) (*sns.PublishOutput, error) {
+// PublishMessageToAllTopicSubscribers publishes a message to a topic with the
+// given string message attributes. The message may hold binary data, which is
+// encoded as described by the payload package.
+func PublishMessageToAllTopicSubscribers(ctx context.Context, messagePtr *string, topicPtr *string, attributes map[string]string) (*sns.PublishOutput, error) {
   if messagePtr == nil || topicPtr == nil || *messagePtr == "" || *topicPtr == "" {
     return nil, errors.New("must supply both a message and topic ARN")
   }
 
-  // Initialize a session to load AWS credentials and configuration from the shared config.
-  sess := session.Must(session.NewSessionWithOptions(session.Options{
-    SharedConfigState: session.SharedConfigEnable,
-  }))
+  ctx, span := tracing.StartTopic(ctx, "notification.PublishMessageToAllTopicSubscribers", *topicPtr,
+    trace.WithSpanKind(trace.SpanKindProducer))
+  defer span.End()
+
+  // Use the shared session, which loads AWS credentials and configuration from the shared config.
+  sess := awssession.New()
 
   // Create SNS client
   svc := sns.New(sess)
 
+  encodedAttributes := map[string]string{}
+  for name, value := range attributes {
+    encodedAttributes[name] = value
+  }
+
+  body, err := payload.Encode(ctx, []byte(*messagePtr), encodedAttributes)
+  if err != nil {
+    return nil, fmt.Errorf("failed to encode message for topic %s: %v", *topicPtr, err)
+  }
+
+  messageAttributes := map[string]*sns.MessageAttributeValue{}
+  for name, value := range encodedAttributes {
+    messageAttributes[name] = &sns.MessageAttributeValue{
+      DataType:    aws.String("String"),
+      StringValue: aws.String(value),
+    }
+  }
+
+  // Carry the trace context to subscribers in the message attributes
+  tracing.Inject(ctx, tracing.SNSAttributeCarrier(messageAttributes))
+
+  // The attributes the service sets share the SQS limit with the caller's
+  if len(messageAttributes) > MaxMessageAttributes {
+    if key := encodedAttributes[payload.BlobAttribute]; key != "" {
+      if err := blob.Default().Delete(ctx, key); err != nil {
+        logging.FromContext(ctx).Warn("unable to delete offloaded payload", slog.String("blob", key), slog.Any("error", err))
+      }
+    }
+    return nil, fmt.Errorf("%w: %d message attributes given and %d set by the service, over the limit of %d",
+      ErrInvalidAttributes, len(attributes), len(messageAttributes)-len(attributes), MaxMessageAttributes)
+  }
+
   // Publish the message to the SNS topic
-  result, err := svc.Publish(&sns.PublishInput{
-    Message:  messagePtr,
-    TopicArn: topicPtr,
+  result, err := svc.PublishWithContext(ctx, &sns.PublishInput{
+    Message:           aws.String(body),
+    MessageAttributes: messageAttributes,
+    TopicArn:          topicPtr,
   })
 
   if err != nil {
     return nil, fmt.Errorf("failed to publish message to topic %s: %v", *topicPtr, err)
   }
 
-  log.Printf("Successfully published message to topic %s", *topicPtr)
+  logging.FromContext(ctx).Info("published message to topic", slog.String("topic", *topicPtr), slog.String("message_id", aws.StringValue(result.MessageId)))
   return result, nil
 }
@@ -0,0 +1,143 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pub-sub-service/logging"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrTopicNotFound is returned when no topic has the given name.
+var ErrTopicNotFound = errors.New("topic not found")
+
+const (
+	// topicCacheTTL bounds how long a resolved ARN is trusted; topics deleted
+	// outside the service drop out at the next refresh
+	topicCacheTTL = 5 * time.Minute
+	// topicCacheMinRefresh keeps lookups of unknown names from listing topics
+	// on every request
+	topicCacheMinRefresh = 5 * time.Second
+	// topicRefreshTimeout bounds a refresh, which outlives the request that
+	// started it
+	topicRefreshTimeout = 30 * time.Second
+)
+
+// topicCache maps topic names to ARNs, refreshed in full from ListTopics.
+// Topics created and deleted while a refresh lists them are kept in updates,
+// and applied over its result.
+type topicCache struct {
+	mu        sync.Mutex
+	arns      map[string]string
+	refreshed time.Time
+	updates   map[string]string
+
+	refresh singleflight.Group
+}
+
+var topics = &topicCache{arns: map[string]string{}}
+
+// ResolveTopicARN returns the ARN of the topic with the given name in the
+// configured account and region, or ErrTopicNotFound.
+func ResolveTopicARN(ctx context.Context, name string) (string, error) {
+	topics.mu.Lock()
+	stale := time.Since(topics.refreshed) > topicCacheTTL
+	recent := time.Since(topics.refreshed) < topicCacheMinRefresh
+	arn, ok := topics.arns[name]
+	topics.mu.Unlock()
+
+	if ok && !stale {
+		return arn, nil
+	}
+	if !stale && recent {
+		return "", ErrTopicNotFound
+	}
+
+	// Concurrent lookups share one refresh, which the lock is not held for,
+	// and which carries on when the request that started it is cancelled
+	refreshed := topics.refresh.DoChan("", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), topicRefreshTimeout)
+		defer cancel()
+		return nil, topics.load(ctx)
+	})
+	select {
+	case result := <-refreshed:
+		if result.Err != nil {
+			return "", result.Err
+		}
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	topics.mu.Lock()
+	defer topics.mu.Unlock()
+
+	if arn, ok := topics.arns[name]; ok {
+		return arn, nil
+	}
+	return "", ErrTopicNotFound
+}
+
+// load replaces the cached ARNs with those of the topics listed now
+func (c *topicCache) load(ctx context.Context) error {
+	c.mu.Lock()
+	c.updates = map[string]string{}
+	c.mu.Unlock()
+
+	listed, err := ListTopics(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	updates := c.updates
+	c.updates = nil
+	if err != nil {
+		return err
+	}
+
+	c.arns = make(map[string]string, len(listed))
+	for _, topic := range listed {
+		arn := aws.StringValue(topic.TopicArn)
+		c.arns[TopicName(arn)] = arn
+	}
+	for name, arn := range updates {
+		if arn == "" {
+			delete(c.arns, name)
+		} else {
+			c.arns[name] = arn
+		}
+	}
+	c.refreshed = time.Now()
+	logging.FromContext(ctx).Debug("refreshed topic ARN cache", slog.Int("count", len(c.arns)))
+	return nil
+}
+
+// TopicName returns the name of a topic from its ARN.
+func TopicName(topicARN string) string {
+	return topicARN[strings.LastIndex(topicARN, ":")+1:]
+}
+
+func cacheTopicARN(topicARN string) {
+	topics.mu.Lock()
+	defer topics.mu.Unlock()
+
+	topics.arns[TopicName(topicARN)] = topicARN
+	if topics.updates != nil {
+		topics.updates[TopicName(topicARN)] = topicARN
+	}
+}
+
+func forgetTopicARN(topicARN string) {
+	topics.mu.Lock()
+	defer topics.mu.Unlock()
+
+	delete(topics.arns, TopicName(topicARN))
+	if topics.updates != nil {
+		topics.updates[TopicName(topicARN)] = ""
+	}
+}
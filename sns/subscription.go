@@ -0,0 +1,202 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"pub-sub-service/api"
+	"pub-sub-service/awssession"
+	"pub-sub-service/logging"
+	"pub-sub-service/tracing"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+// SNS subscription attribute names
+const (
+	attributeFilterPolicy       = "FilterPolicy"
+	attributeFilterPolicyScope  = "FilterPolicyScope"
+	attributeRawMessageDelivery = "RawMessageDelivery"
+	attributeRedrivePolicy      = "RedrivePolicy"
+)
+
+// SubscriptionAttributes holds the settable attributes of a subscription,
+// shared with the client.
+type SubscriptionAttributes = api.SubscriptionAttributes
+
+func validateSubscriptionAttributes(a SubscriptionAttributes) error {
+	if len(a.FilterPolicy) > 0 && !json.Valid(a.FilterPolicy) {
+		return fmt.Errorf("%w: %s must be a JSON document", ErrInvalidAttributes, attributeFilterPolicy)
+	}
+	if a.FilterPolicyScope != nil && *a.FilterPolicyScope != "MessageAttributes" && *a.FilterPolicyScope != "MessageBody" {
+		return fmt.Errorf("%w: %s must be MessageAttributes or MessageBody", ErrInvalidAttributes, attributeFilterPolicyScope)
+	}
+	if len(a.RedrivePolicy) > 0 && !json.Valid(a.RedrivePolicy) {
+		return fmt.Errorf("%w: %s must be a JSON document", ErrInvalidAttributes, attributeRedrivePolicy)
+	}
+	return nil
+}
+
+func subscriptionAttributesToMap(a SubscriptionAttributes) map[string]*string {
+	attributes := map[string]*string{}
+	if a.FilterPolicy != nil {
+		attributes[attributeFilterPolicy] = aws.String(string(a.FilterPolicy))
+	}
+	if a.FilterPolicyScope != nil {
+		attributes[attributeFilterPolicyScope] = a.FilterPolicyScope
+	}
+	if a.RawMessageDelivery != nil {
+		attributes[attributeRawMessageDelivery] = aws.String(strconv.FormatBool(*a.RawMessageDelivery))
+	}
+	if a.RedrivePolicy != nil {
+		attributes[attributeRedrivePolicy] = aws.String(string(a.RedrivePolicy))
+	}
+	return attributes
+}
+
+// Subscribe subscribes an endpoint to a topic with the given subscription
+// attributes and returns the subscription ARN, which is "pending
+// confirmation" until the endpoint confirms. Use SubscribeQueueToTopic for
+// queues, which also need permission to receive from the topic.
+func Subscribe(ctx context.Context, topicARN, protocol, endpoint string, attributes SubscriptionAttributes) (string, error) {
+	if err := validateSubscriptionAttributes(attributes); err != nil {
+		return "", err
+	}
+
+	ctx, span := tracing.StartTopic(ctx, "notification.Subscribe", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	result, err := svc.SubscribeWithContext(ctx, &sns.SubscribeInput{
+		TopicArn: aws.String(topicARN),
+		Protocol: aws.String(protocol),
+		Endpoint: aws.String(endpoint),
+		Attributes: subscriptionAttributesToMap(attributes),
+		ReturnSubscriptionArn: aws.Bool(true),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to subscribe to topic", slog.String("topic", topicARN), slog.String("protocol", protocol), slog.Any("error", err))
+		return "", err
+	}
+
+	return aws.StringValue(result.SubscriptionArn), nil
+}
+
+// GetSubscriptionAttributes returns the settable attributes of a confirmed
+// subscription. Unset attributes are returned at their defaults.
+func GetSubscriptionAttributes(ctx context.Context, subscriptionARN string) (*SubscriptionAttributes, error) {
+	ctx, span := tracing.Start(ctx, "notification.GetSubscriptionAttributes")
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	result, err := svc.GetSubscriptionAttributesWithContext(ctx, &sns.GetSubscriptionAttributesInput{
+		SubscriptionArn: aws.String(subscriptionARN),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to get subscription attributes", slog.String("subscription", subscriptionARN), slog.Any("error", err))
+		return nil, err
+	}
+
+	rawMessageDelivery, _ := strconv.ParseBool(aws.StringValue(result.Attributes[attributeRawMessageDelivery]))
+	attributes := &SubscriptionAttributes{
+		FilterPolicyScope:  aws.String("MessageAttributes"),
+		RawMessageDelivery: aws.Bool(rawMessageDelivery),
+	}
+	if scope := aws.StringValue(result.Attributes[attributeFilterPolicyScope]); scope != "" {
+		attributes.FilterPolicyScope = aws.String(scope)
+	}
+	if policy := aws.StringValue(result.Attributes[attributeFilterPolicy]); policy != "" {
+		attributes.FilterPolicy = json.RawMessage(policy)
+	}
+	if policy := aws.StringValue(result.Attributes[attributeRedrivePolicy]); policy != "" {
+		attributes.RedrivePolicy = json.RawMessage(policy)
+	}
+	return attributes, nil
+}
+
+// SetSubscriptionAttributes updates the attributes that are set. SNS sets one
+// attribute per call, so a failure can leave earlier ones updated.
+func SetSubscriptionAttributes(ctx context.Context, subscriptionARN string, attributes SubscriptionAttributes) (bool, error) {
+	if err := validateSubscriptionAttributes(attributes); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.Start(ctx, "notification.SetSubscriptionAttributes")
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	// The scope must be set before a filter policy that relies on it
+	names := []string{attributeFilterPolicyScope, attributeFilterPolicy, attributeRawMessageDelivery, attributeRedrivePolicy}
+	values := subscriptionAttributesToMap(attributes)
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		_, err := svc.SetSubscriptionAttributesWithContext(ctx, &sns.SetSubscriptionAttributesInput{
+			SubscriptionArn: aws.String(subscriptionARN),
+			AttributeName: aws.String(name),
+			AttributeValue: value,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("unable to set subscription attribute", slog.String("subscription", subscriptionARN), slog.String("attribute", name), slog.Any("error", err))
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// allowTopicPolicy adds a statement allowing topicARN to send to the queue to
+// a queue policy, reporting whether the policy changed.
+func allowTopicPolicy(policy, queueARN, topicARN string) (string, bool, error) {
+	document := map[string]any{"Version": "2012-10-17"}
+	if policy != "" {
+		if err := json.Unmarshal([]byte(policy), &document); err != nil {
+			return "", false, err
+		}
+	}
+
+	var statements []any
+	switch statement := document["Statement"].(type) {
+	case []any:
+		statements = statement
+	case map[string]any:
+		statements = []any{statement}
+	}
+
+	for _, statement := range statements {
+		fields, _ := statement.(map[string]any)
+		condition, _ := fields["Condition"].(map[string]any)
+		arnEquals, _ := condition["ArnEquals"].(map[string]any)
+		if arnEquals["aws:SourceArn"] == topicARN {
+			return policy, false, nil
+		}
+	}
+
+	document["Statement"] = append(statements, map[string]any{
+		"Effect":    "Allow",
+		"Principal": map[string]any{"Service": "sns.amazonaws.com"},
+		"Action":    "SQS:SendMessage",
+		"Resource":  queueARN,
+		"Condition": map[string]any{
+			"ArnEquals": map[string]any{"aws:SourceArn": topicARN},
+		},
+	})
+
+	updated, err := json.Marshal(document)
+	return string(updated), true, err
+}
@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/awssession"
+	"pub-sub-service/logging"
+	"pub-sub-service/tags"
+	"pub-sub-service/tracing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+func ListTopicTags(ctx context.Context, topicARN string) (map[string]string, error) {
+	ctx, span := tracing.StartTopic(ctx, "notification.ListTopicTags", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	result, err := svc.ListTagsForResourceWithContext(ctx, &sns.ListTagsForResourceInput{
+		ResourceArn: aws.String(topicARN),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to list topic tags", slog.String("topic", topicARN), slog.Any("error", err))
+		return nil, err
+	}
+
+	topicTags := map[string]string{}
+	for _, tag := range result.Tags {
+		topicTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return topicTags, nil
+}
+
+// TagTopic adds tags to a topic, replacing the values of existing keys.
+func TagTopic(ctx context.Context, topicARN string, topicTags map[string]string) (bool, error) {
+	if err := tags.Validate(topicTags); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartTopic(ctx, "notification.TagTopic", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	var snsTags []*sns.Tag
+	for key, value := range topicTags {
+		snsTags = append(snsTags, &sns.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	_, err := svc.TagResourceWithContext(ctx, &sns.TagResourceInput{
+		ResourceArn: aws.String(topicARN),
+		Tags: snsTags,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to tag topic", slog.String("topic", topicARN), slog.Any("error", err))
+		return false, err
+	}
+
+	return true, nil
+}
+
+func UntagTopic(ctx context.Context, topicARN string, keys []string) (bool, error) {
+	if err := tags.ValidateKeys(keys); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartTopic(ctx, "notification.UntagTopic", topicARN)
+	defer span.End()
+
+	sess := awssession.New()
+
+	svc := sns.New(sess)
+
+	_, err := svc.UntagResourceWithContext(ctx, &sns.UntagResourceInput{
+		ResourceArn: aws.String(topicARN),
+		TagKeys: aws.StringSlice(keys),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("unable to untag topic", slog.String("topic", topicARN), slog.Any("error", err))
+		return false, err
+	}
+
+	return true, nil
+}
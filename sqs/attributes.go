@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pub-sub-service/api"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+var ErrInvalidAttributes = errors.New("invalid queue attributes")
+
+// Queue attribute types, shared with the client
+type (
+	QueueEncryption  = api.QueueEncryption
+	RedrivePolicy    = api.RedrivePolicy
+	QueueAttributes  = api.QueueAttributes
+	QueueDescription = api.QueueDescription
+)
+
+type int64Range struct {
+	min, max int64
+}
+
+var int64Ranges = map[string]int64Range{
+	sqs.QueueAttributeNameKmsDataKeyReusePeriodSeconds:  {60, 86400},
+	sqs.QueueAttributeNameDelaySeconds:                  {0, 900},
+	sqs.QueueAttributeNameMaximumMessageSize:            {1024, 262144},
+	sqs.QueueAttributeNameMessageRetentionPeriod:        {60, 1209600},
+	sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds: {0, 20},
+	sqs.QueueAttributeNameVisibilityTimeout:             {0, 43200},
+}
+
+// validateEncryption checks encryption settings against the limits SQS
+// enforces.
+func validateEncryption(e QueueEncryption) error {
+	if e.SQSManagedSSEEnabled != nil && *e.SQSManagedSSEEnabled && e.KMSMasterKeyID != nil && *e.KMSMasterKeyID != "" {
+		return fmt.Errorf("%w: SQS managed SSE and a KMS key are mutually exclusive", ErrInvalidAttributes)
+	}
+	return checkRange(sqs.QueueAttributeNameKmsDataKeyReusePeriodSeconds, e.KMSDataKeyReusePeriodSeconds)
+}
+
+// ValidateQueueAttributes checks values against the limits SQS enforces, so
+// callers get a clear error before the request is sent.
+func ValidateQueueAttributes(a QueueAttributes) error {
+	if err := validateEncryption(a.QueueEncryption); err != nil {
+		return err
+	}
+
+	for name, value := range map[string]*int64{
+		sqs.QueueAttributeNameDelaySeconds:                  a.DelaySeconds,
+		sqs.QueueAttributeNameMaximumMessageSize:            a.MaximumMessageSize,
+		sqs.QueueAttributeNameMessageRetentionPeriod:        a.MessageRetentionPeriod,
+		sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds: a.ReceiveMessageWaitTimeSeconds,
+		sqs.QueueAttributeNameVisibilityTimeout:             a.VisibilityTimeout,
+	} {
+		if err := checkRange(name, value); err != nil {
+			return err
+		}
+	}
+
+	if a.RedrivePolicy != nil {
+		if !strings.HasPrefix(a.RedrivePolicy.DeadLetterTargetARN, "arn:") {
+			return fmt.Errorf("%w: redrive policy needs a dead-letter queue ARN", ErrInvalidAttributes)
+		}
+		if a.RedrivePolicy.MaxReceiveCount < 1 || a.RedrivePolicy.MaxReceiveCount > 1000 {
+			return fmt.Errorf("%w: maxReceiveCount must be between 1 and 1000", ErrInvalidAttributes)
+		}
+	}
+	return nil
+}
+
+func checkRange(name string, value *int64) error {
+	if value == nil {
+		return nil
+	}
+	r := int64Ranges[name]
+	if *value < r.min || *value > r.max {
+		return fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidAttributes, name, r.min, r.max)
+	}
+	return nil
+}
+
+// encryptionToMap adds the settings that are set to attributes, as SQS
+// expects them.
+func encryptionToMap(e QueueEncryption, attributes map[string]*string) {
+	if e.KMSMasterKeyID != nil {
+		attributes[sqs.QueueAttributeNameKmsMasterKeyId] = e.KMSMasterKeyID
+	}
+	if e.KMSDataKeyReusePeriodSeconds != nil {
+		attributes[sqs.QueueAttributeNameKmsDataKeyReusePeriodSeconds] = aws.String(strconv.FormatInt(*e.KMSDataKeyReusePeriodSeconds, 10))
+	}
+	if e.SQSManagedSSEEnabled != nil {
+		attributes[sqs.QueueAttributeNameSqsManagedSseEnabled] = aws.String(strconv.FormatBool(*e.SQSManagedSSEEnabled))
+	}
+}
+
+func attributesToMap(a QueueAttributes, attributes map[string]*string) {
+	encryptionToMap(a.QueueEncryption, attributes)
+
+	for name, value := range map[string]*int64{
+		sqs.QueueAttributeNameDelaySeconds:                  a.DelaySeconds,
+		sqs.QueueAttributeNameMaximumMessageSize:            a.MaximumMessageSize,
+		sqs.QueueAttributeNameMessageRetentionPeriod:        a.MessageRetentionPeriod,
+		sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds: a.ReceiveMessageWaitTimeSeconds,
+		sqs.QueueAttributeNameVisibilityTimeout:             a.VisibilityTimeout,
+	} {
+		if value != nil {
+			attributes[name] = aws.String(strconv.FormatInt(*value, 10))
+		}
+	}
+
+	if a.RedrivePolicy != nil {
+		// SQS expects maxReceiveCount as a string
+		policy, _ := json.Marshal(map[string]string{
+			"deadLetterTargetArn": a.RedrivePolicy.DeadLetterTargetARN,
+			"maxReceiveCount":     strconv.FormatInt(a.RedrivePolicy.MaxReceiveCount, 10),
+		})
+		attributes[sqs.QueueAttributeNameRedrivePolicy] = aws.String(string(policy))
+	}
+}
+
+func queueEncryptionFromMap(attributes map[string]*string) QueueEncryption {
+	var e QueueEncryption
+	if value, ok := attributes[sqs.QueueAttributeNameKmsMasterKeyId]; ok {
+		e.KMSMasterKeyID = aws.String(aws.StringValue(value))
+	}
+	e.KMSDataKeyReusePeriodSeconds = parseInt64(attributes[sqs.QueueAttributeNameKmsDataKeyReusePeriodSeconds])
+	if value, err := strconv.ParseBool(aws.StringValue(attributes[sqs.QueueAttributeNameSqsManagedSseEnabled])); err == nil {
+		e.SQSManagedSSEEnabled = aws.Bool(value)
+	}
+	return e
+}
+
+func queueDescriptionFromMap(attributes map[string]*string) *QueueDescription {
+	count := func(name string) int64 {
+		n, _ := strconv.ParseInt(aws.StringValue(attributes[name]), 10, 64)
+		return n
+	}
+	timestamp := func(name string) time.Time {
+		return time.Unix(count(name), 0).UTC()
+	}
+
+	description := &QueueDescription{
+		QueueAttributes: QueueAttributes{
+			QueueEncryption:               queueEncryptionFromMap(attributes),
+			DelaySeconds:                  parseInt64(attributes[sqs.QueueAttributeNameDelaySeconds]),
+			MaximumMessageSize:            parseInt64(attributes[sqs.QueueAttributeNameMaximumMessageSize]),
+			MessageRetentionPeriod:        parseInt64(attributes[sqs.QueueAttributeNameMessageRetentionPeriod]),
+			ReceiveMessageWaitTimeSeconds: parseInt64(attributes[sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds]),
+			VisibilityTimeout:             parseInt64(attributes[sqs.QueueAttributeNameVisibilityTimeout]),
+		},
+		QueueARN:                              aws.StringValue(attributes[sqs.QueueAttributeNameQueueArn]),
+		ApproximateNumberOfMessages:           count(sqs.QueueAttributeNameApproximateNumberOfMessages),
+		ApproximateNumberOfMessagesNotVisible: count(sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+		ApproximateNumberOfMessagesDelayed:    count(sqs.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+		CreatedAt:                             timestamp(sqs.QueueAttributeNameCreatedTimestamp),
+		LastModifiedAt:                        timestamp(sqs.QueueAttributeNameLastModifiedTimestamp),
+	}
+
+	if value := aws.StringValue(attributes[sqs.QueueAttributeNameRedrivePolicy]); value != "" {
+		// maxReceiveCount comes back as a number or a string
+		var policy struct {
+			DeadLetterTargetARN string      `json:"deadLetterTargetArn"`
+			MaxReceiveCount     json.Number `json:"maxReceiveCount"`
+		}
+		if json.Unmarshal([]byte(value), &policy) == nil {
+			maxReceiveCount, _ := policy.MaxReceiveCount.Int64()
+			description.RedrivePolicy = &RedrivePolicy{
+				DeadLetterTargetARN: policy.DeadLetterTargetARN,
+				MaxReceiveCount:     maxReceiveCount,
+			}
+		}
+	}
+
+	return description
+}
+
+func parseInt64(value *string) *int64 {
+	if n, err := strconv.ParseInt(aws.StringValue(value), 10, 64); err == nil {
+		return aws.Int64(n)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package queue
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"pub-sub-service/store"
+)
+
+const (
+	// blobReceiptPrefix maps receipt handles of messages whose payload was
+	// offloaded to their blob keys, so that DeleteMessage removes the blob
+	// of the message it deletes and no other. The key is taken from the
+	// received message, never from the receipt handle a client passes back.
+	blobReceiptPrefix = "blobs/receipts/"
+
+	// blobReceiptTTL outlives the longest visibility timeout, after which
+	// the receipt handle is no longer valid for deletion
+	blobReceiptTTL = 12 * time.Hour
+)
+
+// rememberBlob records the blob key of a received message under its receipt
+// handle.
+func rememberBlob(ctx context.Context, receiptHandle, key string) error {
+	return store.Default().Put(ctx, blobReceiptKey(receiptHandle), []byte(key), blobReceiptTTL)
+}
+
+// forgetBlob removes and returns the blob key recorded for a receipt handle,
+// or "" if there is none.
+func forgetBlob(ctx context.Context, receiptHandle string) (string, error) {
+	key, err := store.Default().Get(ctx, blobReceiptKey(receiptHandle))
+	if errors.Is(err, store.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(key), store.Default().Delete(ctx, blobReceiptKey(receiptHandle))
+}
+
+// blobReceiptKey hashes the receipt handle, which is long and secret
+func blobReceiptKey(receiptHandle string) string {
+	hash := sha256.Sum256([]byte(receiptHandle))
+	return blobReceiptPrefix + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,158 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+	"pub-sub-service/store"
+)
+
+// ErrInProgress is returned for a message that another consumer is
+// processing. It is left on the queue and becomes visible again if that
+// consumer fails.
+var ErrInProgress = errors.New("message is being processed by another consumer")
+
+const (
+	dedupPrefix = "dedup/"
+
+	// DefaultDedupTTL matches the default SQS retention period, so that a
+	// message is remembered for as long as it can be redelivered
+	DefaultDedupTTL = 4 * 24 * time.Hour
+
+	// defaultProcessingTTL bounds the processing claim when the queue's own
+	// visibility timeout is used
+	defaultProcessingTTL = 30 * time.Second
+)
+
+var (
+	markProcessing = []byte("processing")
+	markProcessed  = []byte("processed")
+)
+
+// Handler processes a received message.
+type Handler func(ctx context.Context, message *ReceivedMessage) error
+
+// DedupOptions configures a Deduplicator.
+type DedupOptions struct {
+	// Store records processed messages; store.Default() when nil
+	Store store.Store
+	// TTL is how long processed messages are remembered; DefaultDedupTTL
+	// when zero. Keep it longer than the queue's retention period.
+	TTL time.Duration
+	// KeyAttribute names a message attribute holding a producer-assigned
+	// deduplication key, such as an order ID. Messages without it, or all
+	// messages when it is empty, are deduplicated by message ID, or by SNS
+	// message ID when fanned out by SNS.
+	KeyAttribute string
+}
+
+// Deduplicator processes messages from a queue at most once per key on top
+// of SQS's at-least-once delivery. A message is claimed in the store before
+// its handler runs, marked processed when the handler succeeds and only then
+// deleted, so a redelivery of a processed message is deleted without
+// running the handler again. Only a consumer that stops between its handler
+// succeeding and the mark being stored processes a message twice.
+type Deduplicator struct {
+	queueName    string
+	store        store.Store
+	ttl          time.Duration
+	keyAttribute string
+}
+
+func NewDeduplicator(queueName string, options DedupOptions) *Deduplicator {
+	d := &Deduplicator{
+		queueName:    queueName,
+		store:        options.Store,
+		ttl:          options.TTL,
+		keyAttribute: options.KeyAttribute,
+	}
+	if d.store == nil {
+		d.store = store.Default()
+	}
+	if d.ttl <= 0 {
+		d.ttl = DefaultDedupTTL
+	}
+	return d
+}
+
+// Process receives a message and handles it as Handle does. It reports
+// whether a message was received, so callers can back off when the queue is
+// empty.
+func (d *Deduplicator) Process(ctx context.Context, visibilityTimeout int, handler Handler) (bool, error) {
+	message, err := ReceiveMessage(ctx, d.queueName, visibilityTimeout)
+	if err != nil || message == nil {
+		return false, err
+	}
+	return true, d.Handle(ctx, message, visibilityTimeout, handler)
+}
+
+// Handle runs handler for a received message unless a delivery with the
+// same key was processed, then deletes the message. A handler error releases
+// the claim and leaves the message to be redelivered; a message claimed by
+// another consumer is ErrInProgress. visibilityTimeout is the one the
+// message was received with, and bounds the claim.
+func (d *Deduplicator) Handle(ctx context.Context, message *ReceivedMessage, visibilityTimeout int, handler Handler) error {
+	key := d.key(message)
+	logger := logging.FromContext(ctx).With(slog.String("queue", d.queueName), slog.String("message_id", message.MessageID))
+
+	processingTTL := time.Duration(visibilityTimeout) * time.Second
+	if processingTTL <= 0 {
+		processingTTL = defaultProcessingTTL
+	}
+
+	claimed, err := d.store.PutIfAbsent(ctx, key, markProcessing, processingTTL)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		mark, err := d.store.Get(ctx, key)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+		if string(mark) != string(markProcessed) {
+			return ErrInProgress
+		}
+
+		metrics.ObserveDuplicateMessage(d.queueName)
+		logger.Info("skipping duplicate message")
+		_, err = DeleteMessage(ctx, d.queueName, message.ReceiptHandle)
+		return err
+	}
+
+	if err := handler(ctx, message); err != nil {
+		if releaseErr := d.store.Delete(ctx, key); releaseErr != nil {
+			logger.Warn("unable to release message claim", slog.Any("error", releaseErr))
+		}
+		return err
+	}
+
+	if err := d.store.Put(ctx, key, markProcessed, d.ttl); err != nil {
+		// Left unmarked, the message would be processed again once the
+		// claim expires, so it is not deleted either
+		logger.Error("unable to mark message processed", slog.Any("error", err))
+		return err
+	}
+
+	_, err = DeleteMessage(ctx, d.queueName, message.ReceiptHandle)
+	return err
+}
+
+// key returns the store key of a message. Messages fanned out by SNS are
+// keyed by their SNS message ID, as SNS may deliver one twice, each time
+// with a new SQS message ID.
+func (d *Deduplicator) key(message *ReceivedMessage) string {
+	key := message.MessageID
+	if message.TopicMessageID != "" {
+		key = message.TopicMessageID
+	}
+	if d.keyAttribute != "" {
+		if value := message.Attributes[d.keyAttribute]; value != "" {
+			key = value
+		}
+	}
+	return dedupPrefix + d.queueName + "/" + key
+}
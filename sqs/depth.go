@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"pub-sub-service/awssession"
+	"pub-sub-service/logging"
+	"pub-sub-service/metrics"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+var depthAttributes = []*string{
+	aws.String(sqs.QueueAttributeNameApproximateNumberOfMessages),
+	aws.String(sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+	aws.String(sqs.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+}
+
+// PollQueueDepths refreshes the queue depth gauges for every queue on each
+// interval until ctx is cancelled.
+func PollQueueDepths(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	known := map[string]bool{}
+	for {
+		known = refreshQueueDepths(ctx, known)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func refreshQueueDepths(ctx context.Context, known map[string]bool) map[string]bool {
+	svc := sqs.New(awssession.New())
+
+	result, err := svc.ListQueuesWithContext(ctx, &sqs.ListQueuesInput{})
+	if err != nil {
+		logging.FromContext(ctx).Warn("unable to list queues for depth metrics", slog.Any("error", err))
+		return known
+	}
+
+	seen := map[string]bool{}
+	for _, queueUrl := range result.QueueUrls {
+		attrs, err := svc.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       queueUrl,
+			AttributeNames: depthAttributes,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Warn("unable to get queue depth",
+				slog.String("queue", metrics.QueueLabel(*queueUrl)), slog.Any("error", err))
+			continue
+		}
+
+		queueName := metrics.QueueLabel(*queueUrl)
+		seen[queueName] = true
+		metrics.SetQueueDepth(queueName,
+			attributeFloat(attrs.Attributes, sqs.QueueAttributeNameApproximateNumberOfMessages),
+			attributeFloat(attrs.Attributes, sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+			attributeFloat(attrs.Attributes, sqs.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+		)
+	}
+
+	for queueName := range known {
+		if !seen[queueName] {
+			metrics.DeleteQueueDepth(queueName)
+		}
+	}
+
+	return seen
+}
+
+func attributeFloat(attributes map[string]*string, name string) float64 {
+	value, ok := attributes[name]
+	if !ok || value == nil {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		return 0
+	}
+
+	return f
+}
@@ -1,83 +1,215 @@
 package queue
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
+	"pub-sub-service/api"
+	"pub-sub-service/awssession"
+	"pub-sub-service/blob"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+	"pub-sub-service/schema"
+	"pub-sub-service/tracing"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// MaxDelay is the longest SQS can delay a message.
+const MaxDelay = 15 * time.Minute
+
+// Message attributes of request/reply calls
+const (
+	CorrelationIDAttribute = api.CorrelationIDAttribute
+	ReplyToAttribute       = api.ReplyToAttribute
+)
+
+// Message is a message to send to a queue. Body may hold binary data; set
+// ContentType so it is encoded as described by the payload package. Delay
+// holds the message for up to MaxDelay; zero leaves the queue's own delay.
+// CorrelationID and ReplyTo are set on requests and replies, as the
+// attributes they are carried in may not be given in Attributes.
 type Message struct {
 	Subject string
 	Body string
 	Timestamp time.Time
+	ContentType string
+	Attributes map[string]string
+	BinaryAttributes map[string][]byte
+	Delay time.Duration
+	CorrelationID string
+	ReplyTo string
+}
+
+// MaxMessageAttributes is the most attributes SQS accepts on a message,
+// counting those the service sets.
+const MaxMessageAttributes = 10
+
+// reservedAttributes are set by the service, besides those of the payload
+// package, and are not accepted from callers
+var reservedAttributes = map[string]bool{
+	"Subject":               true,
+	"Timestamp":             true,
+	schema.Attribute:        true,
+	schema.IDAttribute:      true,
+	schema.VersionAttribute: true,
+	schema.FormatAttribute:  true,
+	CorrelationIDAttribute:  true,
+	ReplyToAttribute:        true,
+	"traceparent":           true,
+	"tracestate":            true,
+	"baggage":               true,
+}
+
+// ValidateAttributes checks that message attributes given by a caller do not
+// use the names the service sets itself, and leave room for the Timestamp it
+// always sets. SendMessage checks the total once the others are known.
+func ValidateAttributes(attributes map[string]string, binaryAttributes map[string][]byte) error {
+	if given := len(attributes) + len(binaryAttributes); given > MaxMessageAttributes-1 {
+		return fmt.Errorf("%w: %d attributes given, at most %d are allowed", ErrInvalidAttributes, given, MaxMessageAttributes-1)
+	}
+	for name := range attributes {
+		if reservedAttributes[name] || payload.Reserved(name) {
+			return fmt.Errorf("%w: attribute %s is reserved", ErrInvalidAttributes, name)
+		}
+	}
+	for name := range binaryAttributes {
+		if reservedAttributes[name] || payload.Reserved(name) {
+			return fmt.Errorf("%w: attribute %s is reserved", ErrInvalidAttributes, name)
+		}
+	}
+	return nil
 }
 
 // Message operations
-func SendMessage(queueName string, message Message) (bool, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+func SendMessage(ctx context.Context, queueName string, message Message) (bool, error) {
+	if err := ValidateAttributes(message.Attributes, message.BinaryAttributes); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartQueue(ctx, "queue.SendMessage", queueName,
+		trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+	result, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
     QueueName: &queueName,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to get queue URL", slog.Any("error", err))
 		return false, err
 	}
 
 	queueUrl := result.QueueUrl
 
-	_, err = svc.SendMessage(&sqs.SendMessageInput{
-		DelaySeconds: aws.Int64(10),
-		MessageAttributes: map[string]*sqs.MessageAttributeValue{
-			"Subject": &sqs.MessageAttributeValue{
-				DataType: aws.String("String"),
-				StringValue: aws.String(message.Subject),
-			},
-			"Timestamp": &sqs.MessageAttributeValue{
-				DataType: aws.String("String"),
-				StringValue: aws.String(message.Timestamp.String()),
-			},
-		},
-		MessageBody: aws.String(message.Body),
+	attributes := map[string]string{}
+	for name, value := range message.Attributes {
+		attributes[name] = value
+	}
+	// SQS rejects empty attribute values
+	if message.Subject != "" {
+		attributes["Subject"] = message.Subject
+	}
+	attributes["Timestamp"] = message.Timestamp.String()
+	if message.ContentType != "" {
+		attributes[payload.ContentTypeAttribute] = message.ContentType
+	}
+	if message.CorrelationID != "" {
+		attributes[CorrelationIDAttribute] = message.CorrelationID
+	}
+	if message.ReplyTo != "" {
+		attributes[ReplyToAttribute] = message.ReplyTo
+	}
+
+	body, err := payload.Encode(ctx, []byte(message.Body), attributes)
+	if err != nil {
+		logger.Error("unable to encode message", slog.Any("error", err))
+		return false, err
+	}
+
+	messageAttributes := map[string]*sqs.MessageAttributeValue{}
+	for name, value := range attributes {
+		messageAttributes[name] = &sqs.MessageAttributeValue{
+			DataType: aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	for name, value := range message.BinaryAttributes {
+		messageAttributes[name] = &sqs.MessageAttributeValue{
+			DataType: aws.String("Binary"),
+			BinaryValue: value,
+		}
+	}
+	tracing.Inject(ctx, tracing.SQSAttributeCarrier(messageAttributes))
+
+	// The attributes the service sets share the SQS limit with the caller's
+	if len(messageAttributes) > MaxMessageAttributes {
+		if key := attributes[payload.BlobAttribute]; key != "" {
+			if err := blob.Default().Delete(ctx, key); err != nil {
+				logger.Warn("unable to delete offloaded payload", slog.String("blob", key), slog.Any("error", err))
+			}
+		}
+		given := len(message.Attributes) + len(message.BinaryAttributes)
+		return false, fmt.Errorf("%w: %d attributes given and %d set by the service, over the limit of %d",
+			ErrInvalidAttributes, given, len(messageAttributes)-given, MaxMessageAttributes)
+	}
+
+	input := &sqs.SendMessageInput{
+		MessageAttributes: messageAttributes,
+		MessageBody: aws.String(body),
 		QueueUrl: queueUrl,
-	})
+	}
+	if message.Delay > 0 {
+		delay := min(message.Delay, MaxDelay)
+		input.DelaySeconds = aws.Int64(int64(delay.Round(time.Second) / time.Second))
+	}
+
+	_, err = svc.SendMessageWithContext(ctx, input)
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to send message", slog.Any("error", err))
 		return false, err
 	}
 
 	return true, nil
 }
 
-func ReceiveMessage(queueName string, visibilityTimeout int) (*sqs.Message, error) {
+// ReceiveMessage receives a single message from the queue, or nil when the
+// queue is empty, with its payload decoded. The receive span is linked to the
+// span that produced the message; use MessageContext to continue the
+// producer's trace.
+func ReceiveMessage(ctx context.Context, queueName string, visibilityTimeout int) (*ReceivedMessage, error) {
 	if visibilityTimeout < 0 { visibilityTimeout = 0 }
 	if visibilityTimeout > 12 * 60 * 60 { visibilityTimeout = 12 * 60 * 60 }
 
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	ctx, span := tracing.StartQueue(ctx, "queue.ReceiveMessage", queueName,
+		trace.WithSpanKind(trace.SpanKindConsumer))
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+	result, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
 		QueueName: &queueName,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to get queue URL", slog.Any("error", err))
 		return nil, err
 	}
 
 	queueUrl := result.QueueUrl
 
-	messageResult, err := svc.ReceiveMessage(&sqs.ReceiveMessageInput{
+	messageResult, err := svc.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
 		AttributeNames: []*string{
 			aws.String(sqs.MessageSystemAttributeNameSentTimestamp),
 		},
@@ -89,41 +221,69 @@ func ReceiveMessage(queueName string, visibilityTimeout int) (*sqs.Message, erro
 		VisibilityTimeout: aws.Int64(int64(visibilityTimeout)),
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to receive message", slog.Any("error", err))
 		return nil, err
 	}
 
-	message := *messageResult.Messages[0]
-	fmt.Println("Message: " + *message.ReceiptHandle)
+	if len(messageResult.Messages) == 0 {
+		return nil, nil
+	}
+
+	message := newReceivedMessage(ctx, messageResult.Messages[0])
+	logger.Debug("received message",
+		slog.String("message_id", message.MessageID),
+		slog.Any("receipt_handle", logging.Sensitive(message.ReceiptHandle)),
+	)
+
+	if producer := trace.SpanContextFromContext(MessageContext(ctx, message)); producer.IsRemote() {
+		span.AddLink(trace.Link{SpanContext: producer})
+	}
 	
-	return &message, nil
+	return message, nil
 }
 
-func DeleteMessage(queueName, receiptHandle string) (bool, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+// DeleteMessage deletes a received message, and its offloaded payload if it
+// was sent straight to the queue.
+func DeleteMessage(ctx context.Context, queueName, receiptHandle string) (bool, error) {
+	ctx, span := tracing.StartQueue(ctx, "queue.DeleteMessage", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+	result, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
 		QueueName: &queueName,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to get queue URL", slog.Any("error", err))
 		return false, err
 	}
 
 	queueUrl := result.QueueUrl
 
-	_, err = svc.DeleteMessage(&sqs.DeleteMessageInput{
+	_, err = svc.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
 		QueueUrl: queueUrl,
 		ReceiptHandle: &receiptHandle,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to delete message", slog.Any("error", err))
 		return false, err
 	}
 
+	blobKey, err := forgetBlob(ctx, receiptHandle)
+	if err != nil {
+		logger.Warn("unable to look up offloaded payload", slog.Any("error", err))
+	}
+	if blobKey != "" {
+		if err := blob.Default().Delete(ctx, blobKey); err != nil {
+			// The message is gone; the blob is left to expire after BLOB_TTL,
+			// or by the bucket's lifecycle rule
+			logger.Warn("unable to delete offloaded payload", slog.String("blob", blobKey), slog.Any("error", err))
+		}
+	}
+
 	return true, nil
 }
\ No newline at end of file
@@ -1,134 +1,321 @@
 package queue
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"pub-sub-service/awssession"
+	"pub-sub-service/logging"
+	"pub-sub-service/tracing"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sqs"
 )
 
+// ErrQueueNotFound is returned when a queue that must exist does not.
+var ErrQueueNotFound = errors.New("queue not found")
+
 // Queue operations
-func ListQueues() ([]string, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+func ListQueues(ctx context.Context) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "queue.ListQueues")
+	defer span.End()
+
+	logger := logging.FromContext(ctx)
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.ListQueues(nil)
+	// SQS only paginates when MaxResults is set
+	var queueUrls []string
+	err := svc.ListQueuesPagesWithContext(ctx, &sqs.ListQueuesInput{
+		MaxResults: aws.Int64(1000),
+	}, func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+		for _, url := range page.QueueUrls {
+			queueUrls = append(queueUrls, *url)
+		}
+		return true
+	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to list queues", slog.Any("error", err))
 		return nil, err
 	}
 
-	var queueUrls []string
-	for i, url := range result.QueueUrls {
-		queueUrls = append(queueUrls, *url)
-		fmt.Printf("%d: %s\n", i, *url)
-	}
+	logger.Debug("listed queues", slog.Int("count", len(queueUrls)))
 
 	return queueUrls, nil
 }
 
-func CreateQueue(queueName string) (bool, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+// CreateQueue creates a queue with the given attributes. Unset delay and
+// retention default to 60 seconds and one day.
+func CreateQueue(ctx context.Context, queueName string, attributes QueueAttributes) (bool, error) {
+	if err := ValidateQueueAttributes(attributes); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartQueue(ctx, "queue.CreateQueue", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.CreateQueue(&sqs.CreateQueueInput{
+	queueAttributes := map[string]*string{
+		"DelaySeconds": aws.String("60"),
+		"MessageRetentionPeriod": aws.String("86400"),
+	}
+	attributesToMap(attributes, queueAttributes)
+
+	result, err := svc.CreateQueueWithContext(ctx, &sqs.CreateQueueInput{
 		QueueName: &queueName,
-		Attributes: map[string]*string{
-			"DelaySeconds": aws.String("60"),
-			"MessageRetentionPeriod": aws.String("86400"),
-		},
+		Attributes: queueAttributes,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to create queue", slog.Any("error", err))
 		return false, err
 	}
 
-	fmt.Println("URL: " + *result.QueueUrl)
+	logger.Info("created queue", slog.Any("queue_url", logging.Sensitive(*result.QueueUrl)))
 	return true, nil
 }
 
-func GetQueueURL(queueName string) (string, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+func GetQueueURL(ctx context.Context, queueName string) (string, error) {
+	ctx, span := tracing.StartQueue(ctx, "queue.GetQueueURL", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+	result, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
 		QueueName: &queueName,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to get queue URL", slog.Any("error", err))
 		return "", err
 	}
 
-	fmt.Println("URL: " + *result.QueueUrl)
+	logger.Debug("resolved queue URL", slog.Any("queue_url", logging.Sensitive(*result.QueueUrl)))
 	return *result.QueueUrl, nil
 }
 
-func DeleteQueue(queueName string) (bool, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+func DeleteQueue(ctx context.Context, queueName string) (bool, error) {
+	ctx, span := tracing.StartQueue(ctx, "queue.DeleteQueue", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	queueUrl, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
 		QueueName: &queueName,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to get queue URL", slog.Any("error", err))
 		return false, err
 	}
 
-	_, err = svc.DeleteQueue(&sqs.DeleteQueueInput{
+	_, err = svc.DeleteQueueWithContext(ctx, &sqs.DeleteQueueInput{
 		QueueUrl: queueUrl.QueueUrl,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to delete queue", slog.Any("error", err))
 		return false, err
 	}
 
 	return true, nil
 }
 
-func ConfigureVisibilityTimeout(queueName, receiptHandle string, visibilityDuration int) (bool, error) {
+func ConfigureVisibilityTimeout(ctx context.Context, queueName, receiptHandle string, visibilityDuration int) (bool, error) {
 	if visibilityDuration < 0 { visibilityDuration = 0 }
 	if visibilityDuration > 12 * 60 * 60 { visibilityDuration = 12 * 60 * 60 }
 
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	ctx, span := tracing.StartQueue(ctx, "queue.ConfigureVisibilityTimeout", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
 
 	svc := sqs.New(sess)
 
-	result, err := svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+	result, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
     QueueName: &queueName,
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to get queue URL", slog.Any("error", err))
 		return false, err
 	}
 
 	queueUrl := result.QueueUrl
 
-	_, err = svc.ChangeMessageVisibility(&sqs.ChangeMessageVisibilityInput{
+	_, err = svc.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
 		ReceiptHandle:     &receiptHandle,
 		QueueUrl:          queueUrl,
 		VisibilityTimeout: aws.Int64(int64(visibilityDuration)),
 	})
 	if err != nil {
-		log.Println(err)
+		logger.Error("unable to change message visibility", slog.Any("error", err))
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetQueueEncryption returns the server-side encryption settings of a queue.
+func GetQueueEncryption(ctx context.Context, queueName string) (*QueueEncryption, error) {
+	ctx, span := tracing.StartQueue(ctx, "queue.GetQueueEncryption", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return nil, err
+	}
+
+	result, err := svc.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: queueUrl.QueueUrl,
+		AttributeNames: aws.StringSlice([]string{
+			sqs.QueueAttributeNameKmsMasterKeyId,
+			sqs.QueueAttributeNameKmsDataKeyReusePeriodSeconds,
+			sqs.QueueAttributeNameSqsManagedSseEnabled,
+		}),
+	})
+	if err != nil {
+		logger.Error("unable to get queue attributes", slog.Any("error", err))
+		return nil, err
+	}
+
+	encryption := queueEncryptionFromMap(result.Attributes)
+	return &encryption, nil
+}
+
+// SetQueueEncryption updates the server-side encryption settings of a queue.
+// Set an empty KMS key ID to turn SSE-KMS off.
+func SetQueueEncryption(ctx context.Context, queueName string, encryption QueueEncryption) (bool, error) {
+	if err := validateEncryption(encryption); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartQueue(ctx, "queue.SetQueueEncryption", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return false, err
+	}
+
+	attributes := map[string]*string{}
+	encryptionToMap(encryption, attributes)
+	if len(attributes) == 0 {
+		return true, nil
+	}
+
+	_, err = svc.SetQueueAttributesWithContext(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: queueUrl.QueueUrl,
+		Attributes: attributes,
+	})
+	if err != nil {
+		logger.Error("unable to set queue attributes", slog.Any("error", err))
+		return false, err
+	}
+
+	logger.Info("updated queue encryption")
+	return true, nil
+}
+
+// GetQueueAttributes describes a queue.
+func GetQueueAttributes(ctx context.Context, queueName string) (*QueueDescription, error) {
+	ctx, span := tracing.StartQueue(ctx, "queue.GetQueueAttributes", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return nil, err
+	}
+
+	result, err := svc.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: queueUrl.QueueUrl,
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameAll}),
+	})
+	if err != nil {
+		logger.Error("unable to get queue attributes", slog.Any("error", err))
+		return nil, err
+	}
+
+	return queueDescriptionFromMap(result.Attributes), nil
+}
+
+// SetQueueAttributes updates the attributes that are set.
+func SetQueueAttributes(ctx context.Context, queueName string, attributes QueueAttributes) (bool, error) {
+	if err := ValidateQueueAttributes(attributes); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartQueue(ctx, "queue.SetQueueAttributes", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return false, err
+	}
+
+	queueAttributes := map[string]*string{}
+	attributesToMap(attributes, queueAttributes)
+	if len(queueAttributes) == 0 {
+		return true, nil
+	}
+
+	_, err = svc.SetQueueAttributesWithContext(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: queueUrl.QueueUrl,
+		Attributes: queueAttributes,
+	})
+	if err != nil {
+		logger.Error("unable to set queue attributes", slog.Any("error", err))
 		return false, err
 	}
 
+	logger.Info("updated queue attributes")
 	return true, nil
 }
@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pub-sub-service/api"
+	"pub-sub-service/logging"
+	"pub-sub-service/payload"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// ReceivedMessage is a message received from a queue, shared with the client.
+type ReceivedMessage = api.QueueMessage
+
+func newReceivedMessage(ctx context.Context, message *sqs.Message) *ReceivedMessage {
+	received := &ReceivedMessage{
+		MessageID:        aws.StringValue(message.MessageId),
+		ReceiptHandle:    aws.StringValue(message.ReceiptHandle),
+		Attributes:       map[string]string{},
+		BinaryAttributes: map[string][]byte{},
+	}
+
+	for name, value := range message.MessageAttributes {
+		if strings.HasPrefix(aws.StringValue(value.DataType), "Binary") {
+			received.BinaryAttributes[name] = value.BinaryValue
+		} else {
+			received.Attributes[name] = aws.StringValue(value.StringValue)
+		}
+	}
+
+	if sent, err := strconv.ParseInt(aws.StringValue(message.Attributes[sqs.MessageSystemAttributeNameSentTimestamp]), 10, 64); err == nil {
+		received.SentAt = time.UnixMilli(sent).UTC()
+	}
+
+	body := aws.StringValue(message.Body)
+	if envelope, ok := unwrapNotification(body); ok {
+		received.TopicARN = envelope.TopicArn
+		received.TopicMessageID = envelope.MessageId
+		body = envelope.Message
+
+		for name, value := range envelope.MessageAttributes {
+			if strings.HasPrefix(value.Type, "Binary") {
+				if data, err := base64.StdEncoding.DecodeString(value.Value); err == nil {
+					received.BinaryAttributes[name] = data
+				}
+			} else {
+				received.Attributes[name] = value.Value
+			}
+		}
+	}
+
+	received.ContentType = received.Attributes[payload.ContentTypeAttribute]
+
+	// Blobs of messages sent straight to a queue are removed with the message.
+	// Blobs fanned out by SNS are shared by every subscriber and left to the
+	// blob store's expiry.
+	if key := received.Attributes[payload.BlobAttribute]; key != "" && received.TopicARN == "" {
+		if err := rememberBlob(ctx, received.ReceiptHandle, key); err != nil {
+			logging.FromContext(ctx).Warn("unable to record offloaded payload of message",
+				slog.String("message_id", received.MessageID), slog.Any("error", err))
+		}
+	}
+
+	data, err := payload.Decode(ctx, body, received.Attributes)
+	if err != nil {
+		// Leave the body as it arrived so the consumer can still see it
+		logging.FromContext(ctx).Warn("unable to decode message payload",
+			slog.String("message_id", received.MessageID), slog.Any("error", err))
+		received.Body = body
+		return received
+	}
+
+	if payload.IsBinary(received.ContentType) || !utf8.Valid(data) {
+		received.Data = data
+	} else {
+		received.Body = string(data)
+	}
+
+	return received
+}
@@ -0,0 +1,115 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"pub-sub-service/awssession"
+	"pub-sub-service/logging"
+	"pub-sub-service/tags"
+	"pub-sub-service/tracing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+func ListQueueTags(ctx context.Context, queueName string) (map[string]string, error) {
+	ctx, span := tracing.StartQueue(ctx, "queue.ListQueueTags", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return nil, err
+	}
+
+	result, err := svc.ListQueueTagsWithContext(ctx, &sqs.ListQueueTagsInput{
+		QueueUrl: queueUrl.QueueUrl,
+	})
+	if err != nil {
+		logger.Error("unable to list queue tags", slog.Any("error", err))
+		return nil, err
+	}
+
+	queueTags := map[string]string{}
+	for key, value := range result.Tags {
+		queueTags[key] = aws.StringValue(value)
+	}
+	return queueTags, nil
+}
+
+// TagQueue adds tags to a queue, replacing the values of existing keys.
+func TagQueue(ctx context.Context, queueName string, queueTags map[string]string) (bool, error) {
+	if err := tags.Validate(queueTags); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartQueue(ctx, "queue.TagQueue", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return false, err
+	}
+
+	_, err = svc.TagQueueWithContext(ctx, &sqs.TagQueueInput{
+		QueueUrl: queueUrl.QueueUrl,
+		Tags: aws.StringMap(queueTags),
+	})
+	if err != nil {
+		logger.Error("unable to tag queue", slog.Any("error", err))
+		return false, err
+	}
+
+	return true, nil
+}
+
+func UntagQueue(ctx context.Context, queueName string, keys []string) (bool, error) {
+	if err := tags.ValidateKeys(keys); err != nil {
+		return false, err
+	}
+
+	ctx, span := tracing.StartQueue(ctx, "queue.UntagQueue", queueName)
+	defer span.End()
+
+	logger := logging.FromContext(ctx).With(slog.String("queue", queueName))
+
+	sess := awssession.New()
+
+	svc := sqs.New(sess)
+
+	queueUrl, err := svc.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &queueName,
+	})
+	if err != nil {
+		logger.Error("unable to get queue URL", slog.Any("error", err))
+		return false, err
+	}
+
+	_, err = svc.UntagQueueWithContext(ctx, &sqs.UntagQueueInput{
+		QueueUrl: queueUrl.QueueUrl,
+		TagKeys: aws.StringSlice(keys),
+	})
+	if err != nil {
+		logger.Error("unable to untag queue", slog.Any("error", err))
+		return false, err
+	}
+
+	return true, nil
+}
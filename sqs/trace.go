@@ -0,0 +1,43 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+
+	"pub-sub-service/tracing"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// notification is the envelope SNS wraps around messages delivered to a
+// subscribed queue without raw message delivery.
+type notification struct {
+	Type              string
+	MessageId         string
+	TopicArn          string
+	Message           string
+	MessageAttributes map[string]struct {
+		Type  string
+		Value string
+	}
+}
+
+// unwrapNotification returns the SNS envelope of a message body, if any.
+func unwrapNotification(body string) (*notification, bool) {
+	var envelope notification
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return nil, false
+	}
+	if envelope.Type != "Notification" || envelope.TopicArn == "" {
+		return nil, false
+	}
+
+	return &envelope, true
+}
+
+// MessageContext returns ctx carrying the trace context of the producer of
+// message, taken from its message attributes or, for messages fanned out by
+// SNS, from the attributes in the notification envelope.
+func MessageContext(ctx context.Context, message *ReceivedMessage) context.Context {
+	return tracing.Extract(ctx, propagation.MapCarrier(message.Attributes))
+}
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"pub-sub-service/awssession"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// pingKey is read by Ping and never written
+const pingKey = "health/ping"
+
+// DynamoDB is a Store backed by a DynamoDB table with a string partition key
+// "pk" and string sort key "sk". The namespace of a key is its partition key
+// and the rest its sort key, so that a namespace can be queried in key order,
+// except in spread namespaces: there the partition key is a hash of the key
+// and the sort key the whole key, so that their keys do not all contend for
+// one partition. Expiring items carry an "expiresAt" epoch
+// timestamp, which should be enabled as the table's TTL attribute; expired
+// items are also filtered out on read.
+type DynamoDB struct {
+	table string
+	svc   *dynamodb.DynamoDB
+}
+
+func NewDynamoDB(table string) *DynamoDB {
+	return &DynamoDB{
+		table: table,
+		svc:   dynamodb.New(awssession.New()),
+	}
+}
+
+// Ping verifies that the table is reachable by reading a key that is never
+// written, which needs no permissions beyond those the store uses.
+func (d *DynamoDB) Ping(ctx context.Context) error {
+	_, err := d.Get(ctx, pingKey)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (d *DynamoDB) Get(ctx context.Context, key string) ([]byte, error) {
+	result, err := d.svc.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.table),
+		Key:            primaryKey(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil || itemExpired(result.Item, time.Now()) {
+		return nil, ErrNotFound
+	}
+	return result.Item["value"].B, nil
+}
+
+func (d *DynamoDB) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := d.svc.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.table),
+		Item:      newItem(key, value, ttl),
+	})
+	return err
+}
+
+func (d *DynamoDB) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	_, err := d.svc.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.table),
+		Item:                newItem(key, value, ttl),
+		ConditionExpression: aws.String("attribute_not_exists(pk) OR expiresAt <= :now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(time.Now().Unix(), 10))},
+		},
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (d *DynamoDB) CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	_, err := d.svc.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.table),
+		Item:                newItem(key, value, ttl),
+		ConditionExpression: aws.String("#value = :old AND (attribute_not_exists(expiresAt) OR expiresAt > :now)"),
+		// value is a DynamoDB reserved word
+		ExpressionAttributeNames: map[string]*string{"#value": aws.String("value")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":old": {B: old},
+			":now": {N: aws.String(strconv.FormatInt(time.Now().Unix(), 10))},
+		},
+	})
+	if isConditionFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (d *DynamoDB) Delete(ctx context.Context, key string) error {
+	_, err := d.svc.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.table),
+		Key:       primaryKey(key),
+	})
+	return err
+}
+
+func (d *DynamoDB) List(ctx context.Context, prefix string) ([]Item, error) {
+	if err := checkListable(prefix); err != nil {
+		return nil, err
+	}
+	namespace, rest := splitKey(prefix)
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(d.table),
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :prefix)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pk":     {S: aws.String(namespace)},
+			":prefix": {S: aws.String(rest)},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+	if rest == "" {
+		input.KeyConditionExpression = aws.String("pk = :pk")
+		delete(input.ExpressionAttributeValues, ":prefix")
+	}
+	return d.query(ctx, namespace, input, "")
+}
+
+func (d *DynamoDB) ListRange(ctx context.Context, prefix, last string) ([]Item, error) {
+	if err := checkListable(prefix); err != nil {
+		return nil, err
+	}
+	namespace, rest := splitKey(prefix)
+	_, lastRest := splitKey(last)
+
+	// The prefix is checked on the way out, as a key condition can hold
+	// either it or the range
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(d.table),
+		KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :first AND :last"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pk":    {S: aws.String(namespace)},
+			":first": {S: aws.String(rest)},
+			":last":  {S: aws.String(lastRest)},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+	return d.query(ctx, namespace, input, rest)
+}
+
+// query runs a query of a namespace's items, keeping those whose sort key
+// starts with prefix
+func (d *DynamoDB) query(ctx context.Context, namespace string, input *dynamodb.QueryInput, prefix string) ([]Item, error) {
+	now := time.Now()
+	var items []Item
+	err := d.svc.QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			if itemExpired(item, now) || !strings.HasPrefix(aws.StringValue(item["sk"].S), prefix) {
+				continue
+			}
+			items = append(items, Item{
+				Key:   namespace + "/" + aws.StringValue(item["sk"].S),
+				Value: item["value"].B,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func splitKey(key string) (string, string) {
+	namespace, rest, _ := strings.Cut(key, "/")
+	return namespace, rest
+}
+
+func primaryKey(key string) map[string]*dynamodb.AttributeValue {
+	namespace, rest := splitKey(key)
+	if spreadNamespaces[namespace] {
+		hash := sha256.Sum256([]byte(key))
+		return map[string]*dynamodb.AttributeValue{
+			"pk": {S: aws.String(hex.EncodeToString(hash[:8]))},
+			"sk": {S: aws.String(key)},
+		}
+	}
+	return map[string]*dynamodb.AttributeValue{
+		"pk": {S: aws.String(namespace)},
+		"sk": {S: aws.String(rest)},
+	}
+}
+
+func newItem(key string, value []byte, ttl time.Duration) map[string]*dynamodb.AttributeValue {
+	item := primaryKey(key)
+	item["value"] = &dynamodb.AttributeValue{B: value}
+	if ttl > 0 {
+		item["expiresAt"] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)),
+		}
+	}
+	return item
+}
+
+func itemExpired(item map[string]*dynamodb.AttributeValue, now time.Time) bool {
+	expiresAt, ok := item["expiresAt"]
+	if !ok || expiresAt.N == nil {
+		return false
+	}
+
+	epoch, err := strconv.ParseInt(*expiresAt.N, 10, 64)
+	return err == nil && epoch <= now.Unix()
+}
+
+func isConditionFailed(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
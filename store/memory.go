@@ -0,0 +1,121 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// Memory is a Store held in process memory. State is lost on restart.
+type Memory struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+}
+
+func NewMemory() *Memory {
+	return &Memory{items: map[string]memoryItem{}}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok || item.expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), item.value...), nil
+}
+
+func (m *Memory) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items[key] = newMemoryItem(value, ttl)
+	return nil
+}
+
+func (m *Memory) PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if item, ok := m.items[key]; ok && !item.expired(time.Now()) {
+		return false, nil
+	}
+
+	m.items[key] = newMemoryItem(value, ttl)
+	return true, nil
+}
+
+func (m *Memory) CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok || item.expired(time.Now()) || !bytes.Equal(item.value, old) {
+		return false, nil
+	}
+
+	m.items[key] = newMemoryItem(value, ttl)
+	return true, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.items, key)
+	return nil
+}
+
+func (m *Memory) List(ctx context.Context, prefix string) ([]Item, error) {
+	return m.list(prefix, func(string) bool { return true })
+}
+
+func (m *Memory) ListRange(ctx context.Context, prefix, last string) ([]Item, error) {
+	return m.list(prefix, func(key string) bool { return key <= last })
+}
+
+func (m *Memory) list(prefix string, include func(key string) bool) ([]Item, error) {
+	if err := checkListable(prefix); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var items []Item
+	for key, item := range m.items {
+		if item.expired(now) {
+			delete(m.items, key)
+			continue
+		}
+		if strings.HasPrefix(key, prefix) && include(key) {
+			items = append(items, Item{Key: key, Value: append([]byte(nil), item.value...)})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items, nil
+}
+
+func newMemoryItem(value []byte, ttl time.Duration) memoryItem {
+	item := memoryItem{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	return item
+}
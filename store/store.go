@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrNotListable is returned when listing a spread namespace
+	ErrNotListable = errors.New("namespace cannot be listed")
+)
+
+// spreadNamespaces hold keys written at a high rate and only ever read one
+// at a time. The DynamoDB store spreads them across partitions by a hash of
+// the key, rather than keeping each namespace in a partition of its own, so
+// they cannot be listed.
+var spreadNamespaces = map[string]bool{
+	"idempotency": true,
+	"dedup":       true,
+	"blobs":       true,
+}
+
+// checkListable returns ErrNotListable for prefixes in a spread namespace.
+func checkListable(prefix string) error {
+	namespace, _, _ := strings.Cut(prefix, "/")
+	if spreadNamespaces[namespace] {
+		return fmt.Errorf("%w: %s", ErrNotListable, namespace)
+	}
+	return nil
+}
+
+type Item struct {
+	Key   string
+	Value []byte
+}
+
+// Store is a key-value store for service state such as schemas. Keys are
+// namespaced as "<namespace>/<rest>"; List prefixes must include the
+// namespace, which must not be a spread one. A ttl of zero keeps the value
+// until it is deleted.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// PutIfAbsent stores value only if key does not exist, reporting whether
+	// it was stored.
+	PutIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// CompareAndSwap stores value only if key holds old and has not
+	// expired, reporting whether it was stored.
+	CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// List returns the items whose keys start with prefix, ordered by key.
+	List(ctx context.Context, prefix string) ([]Item, error)
+	// ListRange returns the items whose keys start with prefix and sort at
+	// or before last, ordered by key.
+	ListRange(ctx context.Context, prefix, last string) ([]Item, error)
+}
+
+var (
+	mu           sync.Mutex
+	defaultStore Store
+)
+
+// Setup builds the default store from STORE_BACKEND: "memory" (the default,
+// for local runs) or "dynamodb", which uses the table named by STORE_TABLE.
+func Setup() error {
+	var s Store
+	switch backend := os.Getenv("STORE_BACKEND"); backend {
+	case "", "memory":
+		s = NewMemory()
+	case "dynamodb":
+		table := os.Getenv("STORE_TABLE")
+		if table == "" {
+			return errors.New("STORE_TABLE is required for the dynamodb store")
+		}
+		s = NewDynamoDB(table)
+	default:
+		return fmt.Errorf("unknown STORE_BACKEND %q", backend)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultStore = s
+	return nil
+}
+
+// Default returns the store configured by Setup, or an in-memory store if
+// Setup has not been called.
+func Default() Store {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if defaultStore == nil {
+		defaultStore = NewMemory()
+	}
+	return defaultStore
+}
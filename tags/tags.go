@@ -0,0 +1,60 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidTags = errors.New("invalid tags")
+
+// MaxTags is the most tags AWS allows on a topic or queue.
+const MaxTags = 50
+
+var pattern = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// Validate checks tags against the AWS cost-allocation tag rules.
+func Validate(tags map[string]string) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("%w: no tags given", ErrInvalidTags)
+	}
+	if len(tags) > MaxTags {
+		return fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTags, MaxTags)
+	}
+
+	for key, value := range tags {
+		if err := validateKey(key); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(value) > 256 || !pattern.MatchString(value) {
+			return fmt.Errorf("%w: invalid value for tag %q", ErrInvalidTags, key)
+		}
+	}
+	return nil
+}
+
+// ValidateKeys checks the keys of tags to remove.
+func ValidateKeys(keys []string) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: no tag keys given", ErrInvalidTags)
+	}
+
+	for _, key := range keys {
+		if err := validateKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateKey(key string) error {
+	if key == "" || utf8.RuneCountInString(key) > 128 || !pattern.MatchString(key) {
+		return fmt.Errorf("%w: invalid tag key %q", ErrInvalidTags, key)
+	}
+	if strings.HasPrefix(strings.ToLower(key), "aws:") {
+		return fmt.Errorf("%w: tag key %q uses the reserved aws: prefix", ErrInvalidTags, key)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentAWS adds handlers that wrap every AWS call made with the given
+// handlers in a client span, parented to the context passed to the call.
+func InstrumentAWS(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "pubsub.tracing.Start",
+		Fn:   startRequestSpan,
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "pubsub.tracing.End",
+		Fn:   endRequestSpan,
+	})
+}
+
+func startRequestSpan(r *request.Request) {
+	ctx, _ := Start(r.Context(), r.ClientInfo.ServiceName+"."+r.Operation.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "aws-api"),
+			attribute.String("rpc.service", r.ClientInfo.ServiceName),
+			attribute.String("rpc.method", r.Operation.Name),
+			attribute.String("cloud.region", aws.StringValue(r.Config.Region)),
+		),
+	)
+	r.SetContext(ctx)
+}
+
+func endRequestSpan(r *request.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	span.SetAttributes(
+		attribute.String("aws.request_id", r.RequestID),
+		attribute.Int("aws.retry_count", r.RetryCount),
+	)
+	if r.HTTPResponse != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", r.HTTPResponse.StatusCode))
+	}
+	if r.Error != nil {
+		span.RecordError(r.Error)
+		span.SetStatus(codes.Error, r.Error.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// SNSAttributeCarrier carries trace context in SNS message attributes.
+type SNSAttributeCarrier map[string]*sns.MessageAttributeValue
+
+func (c SNSAttributeCarrier) Get(key string) string {
+	if value, ok := c[key]; ok {
+		return aws.StringValue(value.StringValue)
+	}
+	return ""
+}
+
+func (c SNSAttributeCarrier) Set(key, value string) {
+	c[key] = &sns.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c SNSAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SQSAttributeCarrier carries trace context in SQS message attributes.
+type SQSAttributeCarrier map[string]*sqs.MessageAttributeValue
+
+func (c SQSAttributeCarrier) Get(key string) string {
+	if value, ok := c[key]; ok {
+		return aws.StringValue(value.StringValue)
+	}
+	return ""
+}
+
+func (c SQSAttributeCarrier) Set(key, value string) {
+	c[key] = &sqs.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c SQSAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,211 @@
+// Package validation registers the rules used by the binding tags on request
+// inputs and reports their failures per field.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"pub-sub-service/api"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Custom rules available in binding tags
+const (
+	RuleTopicARN        = "topicarn"
+	RuleSubscriptionARN = "subscriptionarn"
+	RuleTopicName       = "topicname"
+	RuleQueueName       = "queuename"
+	RuleScheduleName    = "schedulename"
+)
+
+var (
+	namePattern            = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	scheduleNamePattern    = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+	topicARNPattern        = regexp.MustCompile(`^arn:aws[a-z-]*:sns:[a-z0-9-]+:[0-9]{12}:([A-Za-z0-9_.-]+)$`)
+	subscriptionARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:sns:[a-z0-9-]+:[0-9]{12}:([A-Za-z0-9_.-]+):[A-Za-z0-9-]+$`)
+)
+
+var registerOnce sync.Once
+
+// FieldError describes why a field failed validation, shared with the
+// client.
+type FieldError = api.FieldError
+
+// Register adds the custom rules to the binding validator and makes it name
+// fields after their JSON keys. It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		validate, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+
+		validate.RegisterValidation(RuleTopicARN, stringRule(TopicARN))
+		validate.RegisterValidation(RuleSubscriptionARN, stringRule(SubscriptionARN))
+		validate.RegisterValidation(RuleTopicName, stringRule(TopicName))
+		validate.RegisterValidation(RuleQueueName, stringRule(QueueName))
+		validate.RegisterValidation(RuleScheduleName, stringRule(ScheduleName))
+	})
+}
+
+func stringRule(valid func(string) bool) validator.Func {
+	return func(field validator.FieldLevel) bool {
+		return valid(field.Field().String())
+	}
+}
+
+// TopicName reports whether name is a valid SNS topic name: up to 256
+// letters, digits, hyphens and underscores, with an optional .fifo suffix.
+func TopicName(name string) bool {
+	return validName(name, 256)
+}
+
+// QueueName reports whether name is a valid SQS queue name: up to 80
+// letters, digits, hyphens and underscores, with an optional .fifo suffix.
+func QueueName(name string) bool {
+	return validName(name, 80)
+}
+
+// ScheduleName reports whether name is a valid recurring schedule name: up
+// to 128 letters, digits, hyphens and underscores.
+func ScheduleName(name string) bool {
+	return scheduleNamePattern.MatchString(name)
+}
+
+func validName(name string, maxLength int) bool {
+	if len(name) > maxLength {
+		return false
+	}
+	return namePattern.MatchString(strings.TrimSuffix(name, ".fifo"))
+}
+
+// TopicARN reports whether arn is the ARN of an SNS topic.
+func TopicARN(arn string) bool {
+	match := topicARNPattern.FindStringSubmatch(arn)
+	return match != nil && TopicName(match[1])
+}
+
+// SubscriptionARN reports whether arn is the ARN of an SNS subscription.
+func SubscriptionARN(arn string) bool {
+	match := subscriptionARNPattern.FindStringSubmatch(arn)
+	return match != nil && TopicName(match[1])
+}
+
+// Value validates a single value, such as a path parameter, against the
+// rules in tag and reports its failures under field.
+func Value(field string, value any, tag string) []FieldError {
+	Register()
+
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil
+	}
+
+	fields := Fields(validate.Var(value, tag))
+	for i := range fields {
+		fields[i].Field = field
+	}
+	return fields
+}
+
+// Fields returns the field failures of a validation error, or nil if err is
+// not one.
+func Fields(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fieldErr),
+			Rule:    fieldErr.Tag(),
+			Message: message(fieldErr),
+		})
+	}
+	return fields
+}
+
+// fieldPath drops the struct name from the namespace, leaving the JSON path
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+func message(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not set", lowerFirst(fieldErr.Param()))
+	case "excluded_with":
+		return fmt.Sprintf("must not be set when %s is set", lowerFirst(fieldErr.Param()))
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a UUID"
+	case "timezone":
+		return "must be an IANA time zone such as Europe/Berlin"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	case "min":
+		return bound("at least", fieldErr)
+	case "max":
+		return bound("at most", fieldErr)
+	case RuleTopicARN:
+		return "must be an SNS topic ARN"
+	case RuleSubscriptionARN:
+		return "must be an SNS subscription ARN"
+	case RuleTopicName:
+		return "must be up to 256 letters, digits, hyphens or underscores, with an optional .fifo suffix"
+	case RuleQueueName:
+		return "must be up to 80 letters, digits, hyphens or underscores, with an optional .fifo suffix"
+	case RuleScheduleName:
+		return "must be up to 128 letters, digits, hyphens or underscores"
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
+
+func bound(limit string, fieldErr validator.FieldError) string {
+	switch fieldErr.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", limit, fieldErr.Param())
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("must have %s %s entries", limit, fieldErr.Param())
+	default:
+		return fmt.Sprintf("must be %s %s", limit, fieldErr.Param())
+	}
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,6 @@
+// Package version identifies the build of the service and its client.
+package version
+
+// Version is the release of the service. Release builds set it with
+// -ldflags "-X pub-sub-service/version.Version=v1.2.3".
+var Version = "dev"
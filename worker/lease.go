@@ -0,0 +1,71 @@
+// Package worker holds what the background workers that dispatch scheduled
+// messages, fire recurring schedules and relay outboxes share: the lease that
+// makes one instance do the work, and the backoff between attempts.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pub-sub-service/logging"
+	"pub-sub-service/store"
+)
+
+// Lease is held by one instance at a time, under a key in the store that
+// holds the ID of the instance.
+type Lease struct {
+	store    store.Store
+	key      string
+	instance string
+
+	// renewed is when the lease was last acquired or renewed
+	renewed time.Time
+}
+
+func NewLease(s store.Store, key, instance string) *Lease {
+	return &Lease{store: s, key: key, instance: instance}
+}
+
+// Acquire renews the lease for ttl if this instance holds it, or acquires it
+// if no instance does, and reports whether this instance holds it. Renewing
+// only succeeds while the store still holds this instance's ID, so a lease
+// another instance took over after it expired is not taken back.
+func (l *Lease) Acquire(ctx context.Context, ttl time.Duration) (bool, error) {
+	instance := []byte(l.instance)
+
+	renewed, err := l.store.CompareAndSwap(ctx, l.key, instance, instance, ttl)
+	if err != nil {
+		return false, err
+	}
+	if renewed {
+		l.renewed = time.Now()
+		return true, nil
+	}
+
+	acquired, err := l.store.PutIfAbsent(ctx, l.key, instance, ttl)
+	if err != nil {
+		return false, err
+	}
+	if acquired {
+		l.renewed = time.Now()
+		logging.FromContext(ctx).Info("acquired lease", slog.String("lease", l.key), slog.String("instance", l.instance))
+	}
+	return acquired, nil
+}
+
+// Held reports whether this instance still holds the lease acquired for
+// ttl, renewing it once a third of ttl has passed, for work that may outlast
+// the lease. Work should stop once it is lost.
+func (l *Lease) Held(ctx context.Context, ttl time.Duration) bool {
+	if time.Since(l.renewed) < ttl/3 {
+		return true
+	}
+
+	held, err := l.Acquire(ctx, ttl)
+	if err != nil || !held {
+		logging.FromContext(ctx).Warn("lost lease", slog.String("lease", l.key), slog.String("instance", l.instance), slog.Any("error", err))
+		return false
+	}
+	return true
+}
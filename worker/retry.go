@@ -0,0 +1,13 @@
+package worker
+
+import "time"
+
+// RetryDelay is the backoff before the next of attempts, doubling from one
+// second up to maxDelay.
+func RetryDelay(attempts int, maxDelay time.Duration) time.Duration {
+	delay := time.Second << (attempts - 1)
+	if delay <= 0 || delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}